@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Sledro/hllrcon/auth"
+	"github.com/Sledro/hllrcon/profile"
+	"github.com/Sledro/hllrcon/session"
+	"github.com/gin-gonic/gin"
+)
+
+// keySession returns the pooled session for an API key's profile, opening one if the
+// key has none yet or its session expired. The dial runs without holding
+// keySessionsMu, so one slow server does not stall every other key.
+func (a *API) keySession(ctx context.Context, key auth.APIKey) (*session.Session, error) {
+	if a.profiles == nil {
+		return nil, errors.New("server profiles are not enabled")
+	}
+	if _, err := a.profiles.Get(key.Profile); err != nil {
+		return nil, err
+	}
+	if sess, ok := a.cachedKeySession(key.ID); ok {
+		return sess, nil
+	}
+
+	host, port, password, err := a.profiles.Credentials(key.Profile)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := a.sessionManager.CreateForProfile(ctx, key.Profile, host, port, password)
+	if err != nil {
+		return nil, err
+	}
+
+	a.keySessionsMu.Lock()
+	defer a.keySessionsMu.Unlock()
+
+	// Another request for the same key may have won the race, or the key was revoked
+	// while dialing; dropKeySession runs after the revocation is stored
+	if id, ok := a.keySessions[key.ID]; ok {
+		if existing, ok := a.sessionManager.Get(id); ok {
+			a.sessionManager.Remove(sess.ID)
+			return existing, nil
+		}
+	}
+	if !a.keys.Has(key.ID) {
+		a.sessionManager.Remove(sess.ID)
+		return nil, errors.New("API key was revoked")
+	}
+	a.keySessions[key.ID] = sess.ID
+	slog.Info("API key session created", "key_id", key.ID, "profile", key.Profile)
+	return sess, nil
+}
+
+// cachedKeySession returns the live session held for a key, forgetting an expired one
+func (a *API) cachedKeySession(keyID string) (*session.Session, bool) {
+	a.keySessionsMu.Lock()
+	defer a.keySessionsMu.Unlock()
+
+	id, ok := a.keySessions[keyID]
+	if !ok {
+		return nil, false
+	}
+	if sess, ok := a.sessionManager.Get(id); ok {
+		return sess, true
+	}
+	delete(a.keySessions, keyID)
+	return nil, false
+}
+
+// dropKeySession closes the session held for a revoked key
+func (a *API) dropKeySession(keyID string) {
+	a.keySessionsMu.Lock()
+	defer a.keySessionsMu.Unlock()
+
+	if id, ok := a.keySessions[keyID]; ok {
+		a.sessionManager.Remove(id)
+		delete(a.keySessions, keyID)
+	}
+}
+
+// requireKeys reports whether API keys are configured
+func (a *API) requireKeys(c *gin.Context) bool {
+	if a.keys == nil || a.profiles == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API keys require server profiles to be enabled"})
+		return false
+	}
+	if _, ok := currentAPIKey(c); ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API keys cannot manage API keys"})
+		return false
+	}
+	return true
+}
+
+// ListAPIKeys returns all API keys without their secrets
+func (a *API) ListAPIKeys(c *gin.Context) {
+	if !a.requireKeys(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"api_keys": a.keys.List()})
+}
+
+// CreateAPIKey issues a key bound to a server profile. The token is only returned once.
+func (a *API) CreateAPIKey(c *gin.Context) {
+	if !a.requireKeys(c) {
+		return
+	}
+
+	var req createAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role, err := auth.ParseRole(req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if role == auth.RoleOwner {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "API keys can be at most admin"})
+		return
+	}
+	if _, err := a.profiles.Get(req.Profile); err != nil {
+		if errors.Is(err, profile.ErrNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "profile not found"})
+			return
+		}
+		profileError(c, err)
+		return
+	}
+
+	key := auth.APIKey{
+		Name:    req.Name,
+		Role:    role,
+		Profile: req.Profile,
+	}
+	if user, ok := currentUser(c); ok {
+		if !user.Role.Allows(role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "cannot create a key with a higher role than your own"})
+			return
+		}
+		key.CreatedBy = user.Username
+	}
+	if req.ExpiresInDays > 0 {
+		expires := time.Now().UTC().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expires
+	}
+
+	key, token, err := a.keys.Create(key)
+	if err != nil {
+		slog.Error("Failed to create API key", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	slog.Info("API key created", "key_id", key.ID, "name", key.Name, "profile", key.Profile, "role", key.Role.String(), "client_ip", c.ClientIP())
+	c.JSON(http.StatusCreated, gin.H{
+		"api_key": key,
+		"token":   token,
+	})
+}
+
+// RevokeAPIKey deletes a key and closes its session
+func (a *API) RevokeAPIKey(c *gin.Context) {
+	if !a.requireKeys(c) {
+		return
+	}
+
+	key, err := a.keys.Revoke(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, auth.ErrKeyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		slog.Error("Failed to revoke API key", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	a.dropKeySession(key.ID)
+
+	slog.Info("API key revoked", "key_id", key.ID, "name", key.Name, "client_ip", c.ClientIP())
+	c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+}
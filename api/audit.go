@@ -0,0 +1,153 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Sledro/hllrcon/audit"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/session"
+	"github.com/gin-gonic/gin"
+)
+
+// recordAudit appends a state-changing command and its outcome to the audit log
+func (a *API) recordAudit(c *gin.Context, sess *session.Session, command string, contentBody any, resp *rcon.Response, execErr error) {
+	if a.audit == nil || rcon.IsReadOnly(command) {
+		return
+	}
+
+	entry := newAuditEntry(c, sess, command, contentBody)
+	if execErr != nil {
+		entry.Error = execErr.Error()
+	}
+	if resp != nil {
+		entry.StatusCode = resp.StatusCode
+		entry.StatusMessage = resp.StatusMessage
+	}
+
+	a.appendAudit(entry)
+}
+
+// newAuditEntry describes a command run by the caller on the session's server
+func newAuditEntry(c *gin.Context, sess *session.Session, command string, contentBody any) audit.Entry {
+	entry := audit.Entry{
+		ClientIP:  c.ClientIP(),
+		SessionID: sess.ID,
+		Server:    fmt.Sprintf("%s:%d", sess.Host, sess.Port),
+		Profile:   sess.Profile,
+		Command:   command,
+		Body:      audit.RedactBody(contentBody),
+	}
+	if user, ok := currentUser(c); ok {
+		entry.User = user.Username
+	}
+	return entry
+}
+
+func (a *API) appendAudit(entry audit.Entry) {
+	if _, err := a.audit.Append(entry); err != nil {
+		slog.Error("Failed to write audit entry", "command", entry.Command, "error", err)
+	}
+}
+
+// requireAudit reports whether the audit log is configured
+func (a *API) requireAudit(c *gin.Context) bool {
+	if a.audit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Audit log is not enabled"})
+		return false
+	}
+	return true
+}
+
+// GetAuditLog returns audited commands filtered by user, server, command and time range.
+// format=csv or format=jsonl downloads the matching entries instead of returning JSON.
+func (a *API) GetAuditLog(c *gin.Context) {
+	if !a.requireAudit(c) {
+		return
+	}
+
+	query := audit.Query{
+		User:    c.Query("user"),
+		Server:  c.Query("server"),
+		Command: c.Query("command"),
+	}
+
+	var err error
+	if query.Since, err = parseTimeParam(c.Query("since")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since: " + err.Error()})
+		return
+	}
+	if query.Until, err = parseTimeParam(c.Query("until")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "until: " + err.Error()})
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+			return
+		}
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format == "json" && query.Limit == 0 {
+		query.Limit = 500
+	}
+
+	entries, err := a.audit.Query(query)
+	if err != nil {
+		slog.Error("Failed to read audit log", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := "hllrcon-audit-" + time.Now().UTC().Format("20060102-150405")
+	switch format {
+	case "json":
+		c.JSON(http.StatusOK, gin.H{"entries": entries})
+	case "csv":
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+		if err := audit.WriteCSV(c.Writer, entries); err != nil {
+			slog.Error("Failed to export audit log", "format", format, "error", err)
+		}
+	case "jsonl":
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.jsonl"`)
+		if err := audit.WriteJSONL(c.Writer, entries); err != nil {
+			slog.Error("Failed to export audit log", "format", format, "error", err)
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, csv or jsonl"})
+	}
+}
+
+// VerifyAuditLog checks the audit log's hash chain for tampering
+func (a *API) VerifyAuditLog(c *gin.Context) {
+	if !a.requireAudit(c) {
+		return
+	}
+
+	result := a.audit.Verify()
+	if !result.Valid {
+		slog.Warn("Audit log verification failed", "broken_at", result.BrokenAt, "reason", result.Reason)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// parseTimeParam accepts RFC 3339 timestamps or Unix seconds; empty means unset
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected RFC 3339 time or Unix seconds")
+	}
+	return t, nil
+}
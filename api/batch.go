@@ -0,0 +1,156 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/gin-gonic/gin"
+)
+
+// batchResult is the outcome of one batch operation
+type batchResult struct {
+	Name       string `json:"name"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Response   any    `json:"response,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Skipped    bool   `json:"skipped,omitempty"`
+}
+
+// batchResponse reports every operation of a batch in request order
+type batchResponse struct {
+	Results   []batchResult `json:"results"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+}
+
+// batchAuditItem records one operation inside a batch's audit entry
+type batchAuditItem struct {
+	Name          string `json:"name"`
+	Body          any    `json:"body,omitempty"`
+	StatusCode    int    `json:"status_code,omitempty"`
+	StatusMessage string `json:"status_message,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// ExecuteBatch validates a list of commands, then runs them in order on one connection
+// without other requests interleaving. Every operation is checked against the caller's
+// role and the command's reference data before anything runs.
+func (a *API) ExecuteBatch(c *gin.Context) {
+	var req batchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sess, err := a.getSession(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not connected. Please connect first."})
+		return
+	}
+
+	user, hasUser := currentUser(c)
+	catalog := a.commandCatalogs.get(fmt.Sprintf("%s:%d", sess.Host, sess.Port))
+	commands := make([]rcon.BatchCommand, len(req.Operations))
+	for i, op := range req.Operations {
+		if handshakeCommands[op.Name] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("operations[%d]: %s is handled by /connect", i, op.Name)})
+			return
+		}
+
+		info, ref, err := catalog.lookup(c.Request.Context(), sess.Client, op.Name)
+		if err == errUnknownCommand {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("operations[%d]: unknown command %q: %s", i, op.Name, err)})
+			return
+		}
+		if err != nil {
+			a.commandError(c, op.Name, err)
+			return
+		}
+
+		if hasUser {
+			required, ok := routeRole(info.ID)
+			if !ok {
+				required = commandRole(info.ID)
+			}
+			if !user.Role.Allows(required) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error":    fmt.Sprintf("operations[%d]: insufficient permissions for %s", i, info.ID),
+					"required": required.String(),
+					"role":     user.Role.String(),
+				})
+				return
+			}
+		}
+
+		content, err := ref.ParseContent(op.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("operations[%d]: %s", i, err)})
+			return
+		}
+		commands[i] = rcon.BatchCommand{Command: info.ID, ContentBody: content}
+	}
+
+	slog.Info("API batch request",
+		"operations", len(commands),
+		"stop_on_error", req.StopOnError,
+		"client_ip", c.ClientIP(),
+	)
+
+	results, err := sess.Client.ExecuteBatch(c.Request.Context(), commands, req.StopOnError)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := batchResponse{Results: make([]batchResult, len(commands))}
+	items := make([]batchAuditItem, 0, len(results))
+	mutating := false
+	for i, cmd := range commands {
+		result := batchResult{Name: cmd.Command}
+		item := batchAuditItem{Name: cmd.Command, Body: cmd.ContentBody}
+		switch {
+		case i >= len(results):
+			result.Skipped = true
+			resp.Skipped++
+		case results[i].Err != nil:
+			result.StatusCode = errorStatus(results[i].Err)
+			result.Error = results[i].Err.Error()
+			item.Error = result.Error
+			resp.Failed++
+		default:
+			r := results[i].Response
+			result.StatusCode = r.StatusCode
+			item.StatusCode = r.StatusCode
+			item.StatusMessage = r.StatusMessage
+			if r.StatusCode == http.StatusOK {
+				result.Response = a.parseContentBody(r.ContentBody)
+				resp.Succeeded++
+			} else {
+				result.Error = r.StatusMessage
+				result.Response = a.parseContentBody(r.ContentBody)
+				resp.Failed++
+			}
+		}
+		resp.Results[i] = result
+
+		if i < len(results) {
+			items = append(items, item)
+			mutating = mutating || !rcon.IsReadOnly(cmd.Command)
+		}
+	}
+
+	if a.audit != nil && mutating {
+		entry := newAuditEntry(c, sess, "Batch", items)
+		entry.StatusCode = http.StatusOK
+		entry.StatusMessage = fmt.Sprintf("%d succeeded, %d failed, %d skipped", resp.Succeeded, resp.Failed, resp.Skipped)
+		if resp.Failed > 0 {
+			entry.Error = fmt.Sprintf("%d of %d operations failed", resp.Failed, len(commands))
+		}
+		a.appendAudit(entry)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
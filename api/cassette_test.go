@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/rcon/rcontest"
+	"github.com/Sledro/hllrcon/session"
+	"github.com/gin-gonic/gin"
+)
+
+// newReplayRouter serves the API against a fake server replaying the cassette at path
+func newReplayRouter(t *testing.T, path string) (*gin.Engine, *rcontest.Server) {
+	t.Helper()
+	srv, err := rcontest.NewReplayServer("secret", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+
+	pool := session.NewPool(func(host string, port int, password string) *rcon.Client {
+		return rcon.NewClient(host, port, password, time.Second, 1<<20, 1<<20)
+	}, session.PoolConfig{MaxConnsPerServer: 1, IdleTimeout: time.Minute, HealthCheckInterval: time.Minute})
+	t.Cleanup(pool.Close)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewAPI(session.NewManager(time.Hour, pool), "test", "", "", false, RCONConfig{}, nil, Stores{}).SetupRoutes(router)
+	return router, srv
+}
+
+// connect opens a session and returns its cookie
+func connect(t *testing.T, router *gin.Engine, srv *rcontest.Server) *http.Cookie {
+	t.Helper()
+	body := fmt.Sprintf(`{"host": %q, "port": %d, "password": %q}`, srv.Host(), srv.Port(), srv.Password)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v2/connect", bytes.NewBufferString(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("connect: %d %s", w.Code, w.Body)
+	}
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "hll_session" {
+			return cookie
+		}
+	}
+	t.Fatal("connect set no session cookie")
+	return nil
+}
+
+func TestReplayPlayers(t *testing.T) {
+	path := filepath.Join("testdata", "players.cassette.jsonl")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret")) {
+		t.Fatal("cassette contains the server password")
+	}
+
+	router, srv := newReplayRouter(t, path)
+	cookie := connect(t, router, srv)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/players", nil)
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("players: %d %s", w.Code, w.Body)
+	}
+
+	var got rcon.PlayerList
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Players) != 2 {
+		t.Fatalf("got %d players, want 2", len(got.Players))
+	}
+	able := got.Players[0]
+	if able.Name != "Able" || able.ClanTag != "[7TH]" || able.ID != "76561198000000001" || able.ScoreData.Combat != 140 || able.WorldPosition.X != 1.5 {
+		t.Errorf("first player = %+v", able)
+	}
+}
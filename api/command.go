@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/auth"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/gin-gonic/gin"
+)
+
+// changelistRecheck is how long a server's command catalog is trusted before its
+// changelist is checked again
+const changelistRecheck = time.Minute
+
+// handshakeCommands are part of the connection setup and never passed through
+var handshakeCommands = map[string]bool{"ServerConnect": true, "Login": true}
+
+// commandCatalog caches a server's commands and their reference data for one changelist
+type commandCatalog struct {
+	mu         sync.Mutex
+	changelist string
+	checkedAt  time.Time
+	commands   map[string]rcon.CommandInfo // Keyed by lowercased ID
+	references map[string]*rcon.CommandReference
+}
+
+// commandCatalogs holds a catalog per server
+type commandCatalogs struct {
+	mu      sync.Mutex
+	servers map[string]*commandCatalog
+}
+
+func (cc *commandCatalogs) get(server string) *commandCatalog {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.servers == nil {
+		cc.servers = make(map[string]*commandCatalog)
+	}
+	catalog, ok := cc.servers[server]
+	if !ok {
+		catalog = &commandCatalog{}
+		cc.servers[server] = catalog
+	}
+	return catalog
+}
+
+// lookup resolves a command name to its reference data, refreshing the catalog when the
+// server's changelist has changed
+func (cat *commandCatalog) lookup(ctx context.Context, client *rcon.Client, name string) (rcon.CommandInfo, *rcon.CommandReference, error) {
+	cat.mu.Lock()
+	defer cat.mu.Unlock()
+
+	if cat.commands == nil || time.Since(cat.checkedAt) > changelistRecheck {
+		changelist, err := client.GetServerChangelist(ctx)
+		if err != nil {
+			return rcon.CommandInfo{}, nil, err
+		}
+		if cat.commands == nil || changelist != cat.changelist {
+			commands, err := client.GetDisplayableCommands(ctx)
+			if err != nil {
+				return rcon.CommandInfo{}, nil, err
+			}
+			cat.commands = make(map[string]rcon.CommandInfo, len(commands))
+			for _, cmd := range commands {
+				cat.commands[strings.ToLower(cmd.ID)] = cmd
+			}
+			cat.references = make(map[string]*rcon.CommandReference)
+			cat.changelist = changelist
+		}
+		cat.checkedAt = time.Now()
+	}
+
+	info, ok := cat.commands[strings.ToLower(name)]
+	if !ok {
+		return rcon.CommandInfo{}, nil, errUnknownCommand
+	}
+
+	ref, ok := cat.references[info.ID]
+	if !ok {
+		var err error
+		if ref, err = client.GetClientReferenceData(ctx, info.ID); err != nil {
+			return rcon.CommandInfo{}, nil, err
+		}
+		cat.references[info.ID] = ref
+	}
+	return info, ref, nil
+}
+
+var errUnknownCommand = fmt.Errorf("command is not reported by GetDisplayableCommands")
+
+// routeRole returns the highest role required by the dedicated routes that run command,
+// and false if there are none
+func routeRole(command string) (auth.Role, bool) {
+	var role auth.Role
+	for _, key := range commandRoutes[command] {
+		method, path, _ := strings.Cut(key, " ")
+		if required := requiredRole(method, path); required > role {
+			role = required
+		}
+	}
+	return role, role != 0
+}
+
+// commandRole returns the role needed to pass a command through: admin, or higher if a
+// dedicated route for the command requires more
+func commandRole(command string) auth.Role {
+	if role, ok := routeRole(command); ok && role > auth.RoleAdmin {
+		return role
+	}
+	return auth.RoleAdmin
+}
+
+// ExecuteRawCommand runs any command the server reports, after validating the body
+// against the command's reference data
+func (a *API) ExecuteRawCommand(c *gin.Context) {
+	var req commandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if handshakeCommands[req.Name] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": req.Name + " is handled by /connect"})
+		return
+	}
+
+	sess, err := a.getSession(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not connected. Please connect first."})
+		return
+	}
+
+	catalog := a.commandCatalogs.get(fmt.Sprintf("%s:%d", sess.Host, sess.Port))
+	info, ref, err := catalog.lookup(c.Request.Context(), sess.Client, req.Name)
+	if err == errUnknownCommand {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown command %q: %s", req.Name, err)})
+		return
+	}
+	if err != nil {
+		a.commandError(c, req.Name, err)
+		return
+	}
+
+	if user, ok := currentUser(c); ok {
+		if required := commandRole(info.ID); !user.Role.Allows(required) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":    "insufficient permissions for " + info.ID,
+				"required": required.String(),
+				"role":     user.Role.String(),
+			})
+			return
+		}
+	}
+
+	content, err := ref.ParseContent(req.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	a.executeCommand(c, info.ID, content)
+}
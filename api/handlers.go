@@ -1,18 +1,37 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
-
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/audit"
+	"github.com/Sledro/hllrcon/auth"
+	"github.com/Sledro/hllrcon/history"
+	"github.com/Sledro/hllrcon/logparse"
+	"github.com/Sledro/hllrcon/logstream"
 	"github.com/Sledro/hllrcon/maps"
+	"github.com/Sledro/hllrcon/profile"
 	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/schedule"
 	"github.com/Sledro/hllrcon/session"
+	"github.com/Sledro/hllrcon/watchlist"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/Sledro/hllrcon/api")
+
 type API struct {
 	sessionManager *session.Manager
 	version        string
@@ -20,15 +39,62 @@ type API struct {
 	buildDate      string
 	secureCookie   bool
 	rconConfig     RCONConfig
+	logHub         *logstream.Hub
+	profiles       *profile.Store           // nil when profiles are disabled
+	users          *auth.UserStore          // nil when access control is disabled
+	audit          *audit.Log               // nil when auditing is disabled
+	keys           *auth.KeyStore           // nil when API keys are disabled
+	history        *history.Store           // nil when player history is disabled
+	watchlist      *watchlist.Store         // nil when the watchlist is disabled
+	broadcasts     *schedule.BroadcastStore // nil when scheduled broadcasts are disabled
+	tasks          *schedule.TaskStore      // nil when scheduled tasks are disabled
+
+	openapi         []byte // Generated by SetupRoutes
+	commandCatalogs commandCatalogs
+
+	keySessionsMu sync.Mutex
+	keySessions   map[string]string // API key ID -> session ID
+}
+
+// Stores holds the optional persistent subsystems; nil fields disable the matching endpoints
+type Stores struct {
+	Profiles   *profile.Store
+	Users      *auth.UserStore
+	Audit      *audit.Log
+	APIKeys    *auth.KeyStore
+	History    *history.Store
+	Watchlist  *watchlist.Store
+	Broadcasts *schedule.BroadcastStore
+	Tasks      *schedule.TaskStore
 }
 
 type RCONConfig struct {
 	DialTimeout     int
 	MaxRequestSize  int
 	MaxResponseSize int
+	Timeouts        rcon.Timeouts
+	Reconnect       rcon.ReconnectPolicy
+	Observer        rcon.Observer  // Receives traffic measurements, e.g. for metrics
+	Recorder        *rcon.Recorder // Records every exchange to a cassette
+	MaxInFlight     int            // Pipelined requests per connection; below 2 is serial
+}
+
+// NewClient builds an unconnected RCON client with the configured limits
+func (r RCONConfig) NewClient(host string, port int, password string) *rcon.Client {
+	client := rcon.NewClient(host, port, password, time.Duration(r.DialTimeout)*time.Second, r.MaxRequestSize, r.MaxResponseSize)
+	client.SetTimeouts(r.Timeouts)
+	client.SetReconnectPolicy(r.Reconnect)
+	client.SetPipelining(r.MaxInFlight)
+	if r.Observer != nil {
+		client.SetObserver(r.Observer)
+	}
+	if r.Recorder != nil {
+		client.SetRecorder(r.Recorder)
+	}
+	return client
 }
 
-func NewAPI(sessionManager *session.Manager, version, gitCommit, buildDate string, secureCookie bool, rconConfig RCONConfig) *API {
+func NewAPI(sessionManager *session.Manager, version, gitCommit, buildDate string, secureCookie bool, rconConfig RCONConfig, logHub *logstream.Hub, stores Stores) *API {
 	return &API{
 		sessionManager: sessionManager,
 		version:        version,
@@ -36,11 +102,25 @@ func NewAPI(sessionManager *session.Manager, version, gitCommit, buildDate strin
 		buildDate:      buildDate,
 		secureCookie:   secureCookie,
 		rconConfig:     rconConfig,
+		logHub:         logHub,
+		profiles:       stores.Profiles,
+		users:          stores.Users,
+		audit:          stores.Audit,
+		keys:           stores.APIKeys,
+		history:        stores.History,
+		watchlist:      stores.Watchlist,
+		broadcasts:     stores.Broadcasts,
+		tasks:          stores.Tasks,
+		keySessions:    make(map[string]string),
 	}
 }
 
-// getClient returns the RCON client from the user's session
-func (a *API) getClient(c *gin.Context) (*rcon.Client, error) {
+// getSession returns the user's session, or the API key's session for bearer requests
+func (a *API) getSession(c *gin.Context) (*session.Session, error) {
+	if key, ok := currentAPIKey(c); ok {
+		return a.keySession(c.Request.Context(), key)
+	}
+
 	// Get from session cookie
 	sessionID, err := c.Cookie("hll_session")
 	if err != nil {
@@ -52,11 +132,27 @@ func (a *API) getClient(c *gin.Context) (*rcon.Client, error) {
 		return nil, fmt.Errorf("session not found or expired")
 	}
 
+	return sess, nil
+}
+
+// getClient returns the RCON client from the user's session
+func (a *API) getClient(c *gin.Context) (*rcon.Client, error) {
+	sess, err := a.getSession(c)
+	if err != nil {
+		return nil, err
+	}
 	return sess.Client, nil
 }
 
 // Generic command executor
 func (a *API) executeCommand(c *gin.Context, command string, contentBody interface{}) {
+	a.executeDecoded(c, command, contentBody, func(body any) (any, error) {
+		return a.parseContentBody(body), nil
+	})
+}
+
+// executeDecoded runs a command and responds with its content body converted by decode
+func (a *API) executeDecoded(c *gin.Context, command string, contentBody interface{}, decode func(any) (any, error)) {
 	slog.Info("API request",
 		"method", c.Request.Method,
 		"path", c.Request.URL.Path,
@@ -64,16 +160,25 @@ func (a *API) executeCommand(c *gin.Context, command string, contentBody interfa
 		"client_ip", c.ClientIP(),
 	)
 
-	client, err := a.getClient(c)
+	ctx, span := tracer.Start(c.Request.Context(), "executeCommand", trace.WithAttributes(attribute.String("rcon.command", command)))
+	defer span.End()
+
+	sess, err := a.getSession(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not connected. Please connect first."})
 		return
 	}
+	span.SetAttributes(attribute.String("session.server", fmt.Sprintf("%s:%d", sess.Host, sess.Port)))
 
-	resp, err := client.Execute(command, contentBody)
+	resp, err := sess.Client.ExecuteContext(ctx, command, contentBody)
+	a.recordAudit(c, sess, command, contentBody, resp, err)
+	if resp != nil {
+		span.SetAttributes(attribute.Int("rcon.status_code", resp.StatusCode))
+	}
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		slog.Error("Command execution failed", "command", command, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -92,11 +197,51 @@ func (a *API) executeCommand(c *gin.Context, command string, contentBody interfa
 
 	slog.Debug("Command successful", "command", command)
 
-	// Parse ContentBody if it's a JSON string
-	result := a.parseContentBody(resp.ContentBody)
+	result, err := decode(resp.ContentBody)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		slog.Error("Unexpected command response", "command", command, "error", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "unexpected response from server: " + err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, result)
 }
 
+// serverInfo runs GetServerInformation and responds with the typed payload for infoType
+func (a *API) serverInfo(c *gin.Context, infoType, value string) {
+	a.executeDecoded(c, "GetServerInformation", rcon.ServerInformationRequest{
+		Name:  infoType,
+		Value: value,
+	}, func(body any) (any, error) {
+		return rcon.DecodeServerInfo(infoType, body)
+	})
+}
+
+// commandError responds with the failure of a typed RCON command
+func (a *API) commandError(c *gin.Context, command string, err error) {
+	var statusErr *rcon.StatusError
+	if errors.As(err, &statusErr) {
+		slog.Warn("Command returned non-200 status",
+			"command", command,
+			"status", statusErr.StatusCode,
+			"message", statusErr.StatusMessage,
+		)
+		c.JSON(statusErr.StatusCode, gin.H{"error": statusErr.StatusMessage})
+		return
+	}
+
+	slog.Error("Command execution failed", "command", command, "error", err)
+	c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+}
+
+// errorStatus maps an RCON execution error to an HTTP status code
+func errorStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
 // parseContentBody attempts to parse ContentBody string as JSON, returns raw value if not JSON
 func (a *API) parseContentBody(contentBody interface{}) interface{} {
 	// If it's already not a string, return as-is
@@ -136,23 +281,31 @@ func (a *API) Version(c *gin.Context) {
 
 // Connect establishes a new RCON connection for this session
 func (a *API) Connect(c *gin.Context) {
-	var req struct {
-		Host     string `json:"host" binding:"required"`
-		Port     int    `json:"port" binding:"required"`
-		Password string `json:"password" binding:"required"`
-	}
+	var req connectRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	slog.Info("New connection request", "client_ip", c.ClientIP())
+	if req.Profile != "" {
+		if !a.requireProfiles(c) {
+			return
+		}
+		host, port, password, err := a.profiles.Credentials(req.Profile)
+		if err != nil {
+			profileError(c, err)
+			return
+		}
+		req.Host, req.Port, req.Password = host, port, password
+	}
+
+	slog.Info("New connection request", "client_ip", c.ClientIP(), "profile", req.Profile)
 
-	sess, err := a.sessionManager.Create(req.Host, req.Port, req.Password, a.rconConfig.DialTimeout, a.rconConfig.MaxRequestSize, a.rconConfig.MaxResponseSize)
+	sess, err := a.sessionManager.CreateForProfile(c.Request.Context(), req.Profile, req.Host, req.Port, req.Password)
 	if err != nil {
 		slog.Error("Failed to create session", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to connect: " + err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": "Failed to connect: " + err.Error()})
 		return
 	}
 
@@ -165,6 +318,7 @@ func (a *API) Connect(c *gin.Context) {
 		"session_id": sess.ID,
 		"host":       req.Host,
 		"port":       req.Port,
+		"profile":    req.Profile,
 	})
 }
 
@@ -199,13 +353,30 @@ func (a *API) ConnectionStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"connected":    true,
+		"state":        sess.Client.State().String(),
 		"host":         sess.Host,
 		"port":         sess.Port,
+		"profile":      sess.Profile,
 		"connected_at": sess.CreatedAt,
 		"last_used":    sess.LastUsed,
 	})
 }
 
+// PoolStats reports connection pool usage for this session's server and overall
+func (a *API) PoolStats(c *gin.Context) {
+	sess, err := a.getSession(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not connected. Please connect first."})
+		return
+	}
+
+	pool := a.sessionManager.Pool()
+	c.JSON(http.StatusOK, gin.H{
+		"server": pool.ServerStats(sess.Host, sess.Port),
+		"total":  pool.Stats(),
+	})
+}
+
 // GetServerInfo gets server information
 func (a *API) GetServerInfo(c *gin.Context) {
 	infoType := c.Query("type")
@@ -213,141 +384,158 @@ func (a *API) GetServerInfo(c *gin.Context) {
 		infoType = "session"
 	}
 
+	if !rcon.IsServerInfoType(infoType) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("unknown type %q; expected one of %s", infoType, strings.Join(rcon.ServerInfoTypes(), ", ")),
+		})
+		return
+	}
+
 	value := c.Query("value")
+	if infoType == "player" && value == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type player requires a value (player ID)"})
+		return
+	}
 
-	a.executeCommand(c, "GetServerInformation", map[string]string{
-		"Name":  infoType,
-		"Value": value,
-	})
+	a.serverInfo(c, infoType, value)
 }
 
 // GetPlayers gets all players
 func (a *API) GetPlayers(c *gin.Context) {
-	a.executeCommand(c, "GetServerInformation", map[string]string{
-		"Name":  "players",
-		"Value": "",
-	})
+	a.serverInfo(c, "players", "")
 }
 
 // GetPlayer gets a specific player
 func (a *API) GetPlayer(c *gin.Context) {
 	playerID := c.Param("id")
-	a.executeCommand(c, "GetServerInformation", map[string]string{
-		"Name":  "player",
-		"Value": playerID,
-	})
+	a.serverInfo(c, "player", playerID)
 }
 
 // GetMapRotation gets the map rotation
 func (a *API) GetMapRotation(c *gin.Context) {
-	a.executeCommand(c, "GetServerInformation", map[string]string{
-		"Name":  "maprotation",
-		"Value": "",
-	})
+	a.serverInfo(c, "maprotation", "")
 }
 
 // GetMapSequence gets the map sequence
 func (a *API) GetMapSequence(c *gin.Context) {
-	a.executeCommand(c, "GetServerInformation", map[string]string{
-		"Name":  "mapsequence",
-		"Value": "",
-	})
+	a.serverInfo(c, "mapsequence", "")
 }
 
 // ServerBroadcast sends a broadcast message
 func (a *API) ServerBroadcast(c *gin.Context) {
-	var req struct {
-		Message string `json:"message" binding:"required"`
-	}
+	var req messageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "ServerBroadcast", map[string]string{
-		"Message": req.Message,
+	a.executeCommand(c, "ServerBroadcast", rcon.MessageRequest{
+		Message: req.Message,
 	})
 }
 
 // KickPlayer kicks a player
 func (a *API) KickPlayer(c *gin.Context) {
-	var req struct {
-		PlayerID string `json:"player_id" binding:"required"`
-		Reason   string `json:"reason"`
-	}
+	var req playerReasonRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "KickPlayer", map[string]string{
-		"PlayerId": req.PlayerID,
-		"Reason":   req.Reason,
+	a.executeCommand(c, "KickPlayer", rcon.PlayerReasonRequest{
+		PlayerID: req.PlayerID,
+		Reason:   req.Reason,
 	})
 }
 
 // ChangeMap changes the current map
 func (a *API) ChangeMap(c *gin.Context) {
-	var req struct {
-		MapName string `json:"map_name" binding:"required"`
-	}
+	var req mapNameRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "ChangeMap", map[string]string{
-		"MapName": req.MapName,
+	a.executeCommand(c, "ChangeMap", rcon.MapRequest{
+		MapName: req.MapName,
 	})
 }
 
 // GetAdminLog gets admin logs
 func (a *API) GetAdminLog(c *gin.Context) {
-	seconds := c.DefaultQuery("seconds", "3600") // Default to 1 hour
+	seconds, err := strconv.Atoi(c.DefaultQuery("seconds", "3600")) // Default to 1 hour
+	if err != nil || seconds < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "seconds must be a non-negative integer"})
+		return
+	}
 
-	a.executeCommand(c, "GetAdminLog", map[string]string{
-		"LogBackTrackTime": seconds,
-		"Filters":          "",
-	})
+	switch c.DefaultQuery("format", "raw") {
+	case "raw":
+		a.executeCommand(c, "GetAdminLog", rcon.AdminLogRequest{
+			LogBackTrackTime: seconds,
+		})
+	case "events":
+		a.getAdminLogEvents(c, seconds)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be 'raw' or 'events'"})
+	}
+}
+
+// getAdminLogEvents returns the admin log parsed into typed events, filtered by the
+// optional type (comma-separated) and player (ID or name) query parameters
+func (a *API) getAdminLogEvents(c *gin.Context, seconds int) {
+	types, err := logparse.ParseTypes(c.Query("type"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter := logparse.Filter{Types: types, Player: c.Query("player")}
+
+	client, err := a.getClient(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not connected. Please connect first."})
+		return
+	}
+
+	entries, err := client.GetAdminLog(c.Request.Context(), seconds, "")
+	if err != nil {
+		a.commandError(c, "GetAdminLog", err)
+		return
+	}
+
+	events := filter.Apply(logparse.ParseEntries(entries))
+	c.JSON(http.StatusOK, gin.H{"events": events})
 }
 
 // GetVIPs gets VIP list
 func (a *API) GetVIPs(c *gin.Context) {
-	a.executeCommand(c, "GetServerInformation", map[string]string{
-		"Name":  "vipplayers",
-		"Value": "",
-	})
+	a.serverInfo(c, "vipplayers", "")
 }
 
 // AddVIP adds a VIP
 func (a *API) AddVIP(c *gin.Context) {
-	var req struct {
-		PlayerID string `json:"player_id" binding:"required"`
-		Comment  string `json:"comment"`
-	}
+	var req addVIPRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "AddVip", map[string]string{
-		"PlayerId": req.PlayerID,
-		"Comment":  req.Comment,
+	a.executeCommand(c, "AddVip", rcon.AddVipRequest{
+		PlayerID: req.PlayerID,
+		Comment:  req.Comment,
 	})
 }
 
 // RemoveVIP removes a VIP
 func (a *API) RemoveVIP(c *gin.Context) {
-	var req struct {
-		PlayerID string `json:"player_id" binding:"required"`
-	}
+	var req playerIDRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "RemoveVip", map[string]string{
-		"PlayerId": req.PlayerID,
+	a.executeCommand(c, "RemoveVip", rcon.PlayerRequest{
+		PlayerID: req.PlayerID,
 	})
 }
 
@@ -373,104 +561,86 @@ func (a *API) GetBans(c *gin.Context) {
 func (a *API) MessagePlayer(c *gin.Context) {
 	playerID := c.Param("id")
 
-	var req struct {
-		Message string `json:"message" binding:"required"`
-	}
+	var req messageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "MessagePlayer", map[string]string{
-		"PlayerId": playerID,
-		"Message":  req.Message,
+	a.executeCommand(c, "MessagePlayer", rcon.MessagePlayerRequest{
+		PlayerID: playerID,
+		Message:  req.Message,
 	})
 }
 
 // Punish Player
 func (a *API) PunishPlayer(c *gin.Context) {
-	var req struct {
-		PlayerID string `json:"player_id" binding:"required"`
-		Reason   string `json:"reason"`
-	}
+	var req playerReasonRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "PunishPlayer", map[string]string{
-		"PlayerId": req.PlayerID,
-		"Reason":   req.Reason,
+	a.executeCommand(c, "PunishPlayer", rcon.PlayerReasonRequest{
+		PlayerID: req.PlayerID,
+		Reason:   req.Reason,
 	})
 }
 
 // TempBan temporarily bans a player
 func (a *API) TempBan(c *gin.Context) {
-	var req struct {
-		PlayerID  string `json:"player_id" binding:"required"`
-		Duration  int    `json:"duration" binding:"required"` // hours
-		Reason    string `json:"reason"`
-		AdminName string `json:"admin_name"`
-	}
+	var req tempBanRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "TemporaryBanPlayer", map[string]interface{}{
-		"PlayerId":  req.PlayerID,
-		"Duration":  req.Duration,
-		"Reason":    req.Reason,
-		"AdminName": req.AdminName,
+	a.executeCommand(c, "TemporaryBanPlayer", rcon.TemporaryBanRequest{
+		PlayerID:  req.PlayerID,
+		Duration:  req.Duration,
+		Reason:    req.Reason,
+		AdminName: req.AdminName,
 	})
 }
 
 // PermaBan permanently bans a player
 func (a *API) PermaBan(c *gin.Context) {
-	var req struct {
-		PlayerID  string `json:"player_id" binding:"required"`
-		Reason    string `json:"reason"`
-		AdminName string `json:"admin_name"`
-	}
+	var req permaBanRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "PermanentBanPlayer", map[string]string{
-		"PlayerId":  req.PlayerID,
-		"Reason":    req.Reason,
-		"AdminName": req.AdminName,
+	a.executeCommand(c, "PermanentBanPlayer", rcon.PermanentBanRequest{
+		PlayerID:  req.PlayerID,
+		Reason:    req.Reason,
+		AdminName: req.AdminName,
 	})
 }
 
 // RemoveTempBan removes a temporary ban
 func (a *API) RemoveTempBan(c *gin.Context) {
-	var req struct {
-		PlayerID string `json:"player_id" binding:"required"`
-	}
+	var req playerIDRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "RemoveTemporaryBan", map[string]string{
-		"PlayerId": req.PlayerID,
+	a.executeCommand(c, "RemoveTemporaryBan", rcon.PlayerRequest{
+		PlayerID: req.PlayerID,
 	})
 }
 
 // RemovePermaBan removes a permanent ban
 func (a *API) RemovePermaBan(c *gin.Context) {
-	var req struct {
-		PlayerID string `json:"player_id" binding:"required"`
-	}
+	var req playerIDRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "RemovePermanentBan", map[string]string{
-		"PlayerId": req.PlayerID,
+	a.executeCommand(c, "RemovePermanentBan", rcon.PlayerRequest{
+		PlayerID: req.PlayerID,
 	})
 }
 
@@ -486,44 +656,35 @@ func (a *API) GetAdminGroups(c *gin.Context) {
 
 // AddAdmin adds an admin
 func (a *API) AddAdmin(c *gin.Context) {
-	var req struct {
-		PlayerID   string `json:"player_id" binding:"required"`
-		AdminGroup string `json:"admin_group" binding:"required"`
-		Comment    string `json:"comment"`
-	}
+	var req addAdminRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "AddAdmin", map[string]string{
-		"PlayerId":   req.PlayerID,
-		"AdminGroup": req.AdminGroup,
-		"Comment":    req.Comment,
+	a.executeCommand(c, "AddAdmin", rcon.AddAdminRequest{
+		PlayerID:   req.PlayerID,
+		AdminGroup: req.AdminGroup,
+		Comment:    req.Comment,
 	})
 }
 
 // RemoveAdmin removes an admin
 func (a *API) RemoveAdmin(c *gin.Context) {
-	var req struct {
-		PlayerID string `json:"player_id" binding:"required"`
-	}
+	var req playerIDRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "RemoveAdmin", map[string]string{
-		"PlayerId": req.PlayerID,
+	a.executeCommand(c, "RemoveAdmin", rcon.PlayerRequest{
+		PlayerID: req.PlayerID,
 	})
 }
 
 // ForceTeamSwitch forces a player to switch teams
 func (a *API) ForceTeamSwitch(c *gin.Context) {
-	var req struct {
-		PlayerID  string `json:"player_id" binding:"required"`
-		ForceMode *int   `json:"force_mode" binding:"required"` // 0=on death, 1=immediately
-	}
+	var req forceTeamSwitchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -535,438 +696,370 @@ func (a *API) ForceTeamSwitch(c *gin.Context) {
 		return
 	}
 
-	a.executeCommand(c, "ForceTeamSwitch", map[string]interface{}{
-		"PlayerId":  req.PlayerID,
-		"ForceMode": *req.ForceMode,
+	a.executeCommand(c, "ForceTeamSwitch", rcon.ForceTeamSwitchRequest{
+		PlayerID:  req.PlayerID,
+		ForceMode: rcon.ForceMode(*req.ForceMode),
 	})
 }
 
 // RemovePlayerFromSquad removes player from their squad
 func (a *API) RemovePlayerFromSquad(c *gin.Context) {
-	var req struct {
-		PlayerID string `json:"player_id" binding:"required"`
-		Reason   string `json:"reason"`
-	}
+	var req playerReasonRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "RemovePlayerFromPlatoon", map[string]string{
-		"PlayerId": req.PlayerID,
-		"Reason":   req.Reason,
+	a.executeCommand(c, "RemovePlayerFromPlatoon", rcon.PlayerReasonRequest{
+		PlayerID: req.PlayerID,
+		Reason:   req.Reason,
 	})
 }
 
 // DisbandSquad disbands a squad
 func (a *API) DisbandSquad(c *gin.Context) {
-	var req struct {
-		TeamIndex  *int   `json:"team_index" binding:"required"`
-		SquadIndex *int   `json:"squad_index" binding:"required"`
-		Reason     string `json:"reason"`
-	}
+	var req disbandSquadRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "DisbandPlatoon", map[string]interface{}{
-		"TeamIndex":  *req.TeamIndex,
-		"SquadIndex": *req.SquadIndex,
-		"Reason":     req.Reason,
+	a.executeCommand(c, "DisbandPlatoon", rcon.DisbandPlatoonRequest{
+		TeamIndex:  *req.TeamIndex,
+		SquadIndex: *req.SquadIndex,
+		Reason:     req.Reason,
 	})
 }
 
 // AddMapToRotation adds a map to rotation
 func (a *API) AddMapToRotation(c *gin.Context) {
-	var req struct {
-		MapName string `json:"map_name" binding:"required"`
-		Index   int    `json:"index"`
-	}
+	var req mapIndexRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "AddMapToRotation", map[string]interface{}{
-		"MapName": req.MapName,
-		"Index":   req.Index,
+	a.executeCommand(c, "AddMapToRotation", rcon.MapIndexRequest{
+		MapName: req.MapName,
+		Index:   req.Index,
 	})
 }
 
 // RemoveMapFromRotation removes a map from rotation
 func (a *API) RemoveMapFromRotation(c *gin.Context) {
-	var req struct {
-		Index *int `json:"index" binding:"required"`
-	}
+	var req indexRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "RemoveMapFromRotation", map[string]interface{}{
-		"Index": *req.Index,
+	a.executeCommand(c, "RemoveMapFromRotation", rcon.IndexRequest{
+		Index: *req.Index,
 	})
 }
 
 // SetWelcomeMessage sets the server welcome message
 func (a *API) SetWelcomeMessage(c *gin.Context) {
-	var req struct {
-		Message string `json:"message" binding:"required"`
-	}
+	var req messageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetWelcomeMessage", map[string]string{
-		"Message": req.Message,
+	a.executeCommand(c, "SetWelcomeMessage", rcon.MessageRequest{
+		Message: req.Message,
 	})
 }
 
 // GetProfanities gets banned words list
 func (a *API) GetProfanities(c *gin.Context) {
-	a.executeCommand(c, "GetServerInformation", map[string]string{
-		"Name":  "bannedwords",
-		"Value": "",
-	})
+	a.serverInfo(c, "bannedwords", "")
 }
 
 // AddProfanities adds banned words
 func (a *API) AddProfanities(c *gin.Context) {
-	var req struct {
-		BannedWords string `json:"banned_words" binding:"required"`
-	}
+	var req bannedWordsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "AddBannedWords", map[string]string{
-		"BannedWords": req.BannedWords,
+	a.executeCommand(c, "AddBannedWords", rcon.BannedWordsRequest{
+		BannedWords: req.BannedWords,
 	})
 }
 
 // RemoveProfanities removes banned words
 func (a *API) RemoveProfanities(c *gin.Context) {
-	var req struct {
-		BannedWords string `json:"banned_words" binding:"required"`
-	}
+	var req bannedWordsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "RemoveBannedWords", map[string]string{
-		"BannedWords": req.BannedWords,
+	a.executeCommand(c, "RemoveBannedWords", rcon.BannedWordsRequest{
+		BannedWords: req.BannedWords,
 	})
 }
 
 // SetSectorLayout sets the objective sector layout
 func (a *API) SetSectorLayout(c *gin.Context) {
-	var req struct {
-		Sector1 string `json:"sector_1" binding:"required"`
-		Sector2 string `json:"sector_2" binding:"required"`
-		Sector3 string `json:"sector_3" binding:"required"`
-		Sector4 string `json:"sector_4" binding:"required"`
-		Sector5 string `json:"sector_5" binding:"required"`
-	}
+	var req sectorLayoutRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetSectorLayout", map[string]string{
-		"Sector_1": req.Sector1,
-		"Sector_2": req.Sector2,
-		"Sector_3": req.Sector3,
-		"Sector_4": req.Sector4,
-		"Sector_5": req.Sector5,
+	a.executeCommand(c, "SetSectorLayout", rcon.SectorLayoutRequest{
+		Sector1: req.Sector1,
+		Sector2: req.Sector2,
+		Sector3: req.Sector3,
+		Sector4: req.Sector4,
+		Sector5: req.Sector5,
 	})
 }
 
 // AddMapToSequence adds a map to the sequence
 func (a *API) AddMapToSequence(c *gin.Context) {
-	var req struct {
-		MapName string `json:"map_name" binding:"required"`
-		Index   int    `json:"index"`
-	}
+	var req mapIndexRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "AddMapToSequence", map[string]interface{}{
-		"MapName": req.MapName,
-		"Index":   req.Index,
+	a.executeCommand(c, "AddMapToSequence", rcon.MapIndexRequest{
+		MapName: req.MapName,
+		Index:   req.Index,
 	})
 }
 
 // RemoveMapFromSequence removes a map from the sequence
 func (a *API) RemoveMapFromSequence(c *gin.Context) {
-	var req struct {
-		Index *int `json:"index" binding:"required"`
-	}
+	var req indexRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "RemoveMapFromSequence", map[string]interface{}{
-		"Index": *req.Index,
+	a.executeCommand(c, "RemoveMapFromSequence", rcon.IndexRequest{
+		Index: *req.Index,
 	})
 }
 
 // MoveMapInSequence moves a map in the sequence
 func (a *API) MoveMapInSequence(c *gin.Context) {
-	var req struct {
-		CurrentIndex *int `json:"current_index" binding:"required"`
-		NewIndex     *int `json:"new_index" binding:"required"`
-	}
+	var req moveMapRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "MoveMapInSequence", map[string]interface{}{
-		"CurrentIndex": *req.CurrentIndex,
-		"NewIndex":     *req.NewIndex,
+	a.executeCommand(c, "MoveMapInSequence", rcon.MoveMapRequest{
+		CurrentIndex: *req.CurrentIndex,
+		NewIndex:     *req.NewIndex,
 	})
 }
 
 // SetMapShuffleEnabled enables/disables map shuffle
 func (a *API) SetMapShuffleEnabled(c *gin.Context) {
-	var req struct {
-		Enable bool `json:"enable" binding:"required"`
-	}
+	var req enableRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetMapShuffleEnabled", map[string]interface{}{
-		"Enable": req.Enable,
+	a.executeCommand(c, "SetMapShuffleEnabled", rcon.EnableRequest{
+		Enable: req.Enable,
 	})
 }
 
 // SetTeamSwitchCooldown sets the team switch cooldown
 func (a *API) SetTeamSwitchCooldown(c *gin.Context) {
-	var req struct {
-		TeamSwitchTimer int `json:"team_switch_timer" binding:"required"`
-	}
+	var req teamSwitchCooldownRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetTeamSwitchCooldown", map[string]interface{}{
-		"TeamSwitchTimer": req.TeamSwitchTimer,
+	a.executeCommand(c, "SetTeamSwitchCooldown", rcon.TeamSwitchCooldownRequest{
+		TeamSwitchTimer: req.TeamSwitchTimer,
 	})
 }
 
 // SetMaxQueuedPlayers sets max queued players
 func (a *API) SetMaxQueuedPlayers(c *gin.Context) {
-	var req struct {
-		MaxQueuedPlayers int `json:"max_queued_players" binding:"required"`
-	}
+	var req maxQueuedPlayersRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetMaxQueuedPlayers", map[string]interface{}{
-		"MaxQueuedPlayers": req.MaxQueuedPlayers,
+	a.executeCommand(c, "SetMaxQueuedPlayers", rcon.MaxQueuedPlayersRequest{
+		MaxQueuedPlayers: req.MaxQueuedPlayers,
 	})
 }
 
 // SetIdleKickDuration sets idle kick duration
 func (a *API) SetIdleKickDuration(c *gin.Context) {
-	var req struct {
-		IdleTimeoutMinutes int `json:"idle_timeout_minutes" binding:"required"`
-	}
+	var req idleKickDurationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetIdleKickDuration", map[string]interface{}{
-		"IdleTimeoutMinutes": req.IdleTimeoutMinutes,
+	a.executeCommand(c, "SetIdleKickDuration", rcon.IdleKickDurationRequest{
+		IdleTimeoutMinutes: req.IdleTimeoutMinutes,
 	})
 }
 
 // SetHighPingThreshold sets high ping threshold
 func (a *API) SetHighPingThreshold(c *gin.Context) {
-	var req struct {
-		HighPingThresholdMs int `json:"high_ping_threshold_ms" binding:"required"`
-	}
+	var req highPingThresholdRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetHighPingThreshold", map[string]interface{}{
-		"HighPingThresholdMs": req.HighPingThresholdMs,
+	a.executeCommand(c, "SetHighPingThreshold", rcon.HighPingThresholdRequest{
+		HighPingThresholdMs: req.HighPingThresholdMs,
 	})
 }
 
 // SetVipSlotCount sets VIP slot count
 func (a *API) SetVipSlotCount(c *gin.Context) {
-	var req struct {
-		VipSlotCount int `json:"vip_slot_count" binding:"required"`
-	}
+	var req vipSlotCountRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetVipSlotCount", map[string]interface{}{
-		"VipSlotCount": req.VipSlotCount,
+	a.executeCommand(c, "SetVipSlotCount", rcon.VipSlotCountRequest{
+		VipSlotCount: req.VipSlotCount,
 	})
 }
 
 // ResetVoteKickThreshold resets vote kick threshold to default
 func (a *API) ResetVoteKickThreshold(c *gin.Context) {
-	a.executeCommand(c, "ResetVoteKickThreshold", map[string]interface{}{})
+	a.executeCommand(c, "ResetVoteKickThreshold", struct{}{})
 }
 
 // SetVoteKickEnabled enables/disables vote kick
 func (a *API) SetVoteKickEnabled(c *gin.Context) {
-	var req struct {
-		Enable bool `json:"enable" binding:"required"`
-	}
+	var req enableRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetVoteKickEnabled", map[string]interface{}{
-		"Enable": req.Enable,
+	a.executeCommand(c, "SetVoteKickEnabled", rcon.EnableRequest{
+		Enable: req.Enable,
 	})
 }
 
 // SetVoteKickThreshold sets vote kick threshold
 func (a *API) SetVoteKickThreshold(c *gin.Context) {
-	var req struct {
-		ThresholdValue string `json:"threshold_value" binding:"required"`
-	}
+	var req voteKickThresholdRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetVoteKickThreshold", map[string]string{
-		"ThresholdValue": req.ThresholdValue,
+	a.executeCommand(c, "SetVoteKickThreshold", rcon.VoteKickThresholdRequest{
+		ThresholdValue: req.ThresholdValue,
 	})
 }
 
 // SetAutoBalanceEnabled enables/disables auto balance
 func (a *API) SetAutoBalanceEnabled(c *gin.Context) {
-	var req struct {
-		Enable bool `json:"enable" binding:"required"`
-	}
+	var req enableRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetAutoBalanceEnabled", map[string]interface{}{
-		"Enable": req.Enable,
+	a.executeCommand(c, "SetAutoBalanceEnabled", rcon.EnableRequest{
+		Enable: req.Enable,
 	})
 }
 
 // SetAutoBalanceThreshold sets auto balance threshold
 func (a *API) SetAutoBalanceThreshold(c *gin.Context) {
-	var req struct {
-		AutoBalanceThreshold int `json:"auto_balance_threshold" binding:"required"`
-	}
+	var req autoBalanceThresholdRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetAutoBalanceThreshold", map[string]interface{}{
-		"AutoBalanceThreshold": req.AutoBalanceThreshold,
+	a.executeCommand(c, "SetAutoBalanceThreshold", rcon.AutoBalanceThresholdRequest{
+		AutoBalanceThreshold: req.AutoBalanceThreshold,
 	})
 }
 
 // SetMatchTimer sets match timer for a game mode
 func (a *API) SetMatchTimer(c *gin.Context) {
-	var req struct {
-		GameMode    string `json:"game_mode" binding:"required"`
-		MatchLength int    `json:"match_length" binding:"required"`
-	}
+	var req matchTimerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetMatchTimer", map[string]interface{}{
-		"GameMode":    req.GameMode,
-		"MatchLength": req.MatchLength,
+	a.executeCommand(c, "SetMatchTimer", rcon.MatchTimerRequest{
+		GameMode:    req.GameMode,
+		MatchLength: req.MatchLength,
 	})
 }
 
 // RemoveMatchTimer removes match timer for a game mode
 func (a *API) RemoveMatchTimer(c *gin.Context) {
-	var req struct {
-		GameMode string `json:"game_mode" binding:"required"`
-	}
+	var req gameModeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "RemoveMatchTimer", map[string]string{
-		"GameMode": req.GameMode,
+	a.executeCommand(c, "RemoveMatchTimer", rcon.GameModeRequest{
+		GameMode: req.GameMode,
 	})
 }
 
 // SetWarmupTimer sets warmup timer for a game mode
 func (a *API) SetWarmupTimer(c *gin.Context) {
-	var req struct {
-		GameMode     string `json:"game_mode" binding:"required"`
-		WarmupLength int    `json:"warmup_length" binding:"required"`
-	}
+	var req warmupTimerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetWarmupTimer", map[string]interface{}{
-		"GameMode":     req.GameMode,
-		"WarmupLength": req.WarmupLength,
+	a.executeCommand(c, "SetWarmupTimer", rcon.WarmupTimerRequest{
+		GameMode:     req.GameMode,
+		WarmupLength: req.WarmupLength,
 	})
 }
 
 // RemoveWarmupTimer removes warmup timer for a game mode
 func (a *API) RemoveWarmupTimer(c *gin.Context) {
-	var req struct {
-		GameMode string `json:"game_mode" binding:"required"`
-	}
+	var req gameModeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "RemoveWarmupTimer", map[string]string{
-		"GameMode": req.GameMode,
+	a.executeCommand(c, "RemoveWarmupTimer", rcon.GameModeRequest{
+		GameMode: req.GameMode,
 	})
 }
 
 // SetDynamicWeatherEnabled enables/disables dynamic weather for a map
 func (a *API) SetDynamicWeatherEnabled(c *gin.Context) {
-	var req struct {
-		MapId  string `json:"map_id" binding:"required"`
-		Enable bool   `json:"enable" binding:"required"`
-	}
+	var req dynamicWeatherRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	a.executeCommand(c, "SetDynamicWeatherEnabled", map[string]interface{}{
-		"MapId":  req.MapId,
-		"Enable": req.Enable,
+	a.executeCommand(c, "SetDynamicWeatherEnabled", rcon.DynamicWeatherRequest{
+		MapID:  req.MapId,
+		Enable: req.Enable,
 	})
 }
 
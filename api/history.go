@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sledro/hllrcon/history"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPlayerHistory returns everything recorded about a player across the connected servers
+func (a *API) GetPlayerHistory(c *gin.Context) {
+	if a.history == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Player history is not enabled"})
+		return
+	}
+
+	record, err := a.history.Get(c.Param("id"))
+	if errors.Is(err, history.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to read player history", "player_id", c.Param("id"), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read player history"})
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// SearchPlayers finds current and recorded players by ID or by any name they have used
+func (a *API) SearchPlayers(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	limit := 20
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+	}
+
+	// Without history only the connected server's players can be searched
+	client, err := a.getClient(c)
+	if err != nil && a.history == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not connected. Please connect first."})
+		return
+	}
+
+	var matches []history.Match
+	if a.history != nil {
+		if matches, err = a.history.Search(query, 0); err != nil {
+			slog.Error("Failed to search player history", "query", query, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search player history"})
+			return
+		}
+	}
+
+	var live []rcon.Player
+	if client != nil {
+		if live, err = client.GetPlayers(c.Request.Context()); err != nil {
+			if a.history == nil {
+				a.commandError(c, "GetServerInformation", err)
+				return
+			}
+			slog.Warn("Player search without live players", "error", err)
+		}
+	}
+
+	matches = history.MergeLive(matches, query, live, time.Now().UTC(), limit)
+	if matches == nil {
+		matches = []history.Match{}
+	}
+	c.JSON(http.StatusOK, gin.H{"players": matches})
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Sledro/hllrcon/logstream"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const streamHeartbeat = 15 * time.Second
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+}
+
+// subscribeLogs starts a log subscription for the caller's server, resuming after cursor
+func (a *API) subscribeLogs(c *gin.Context, cursor string) (*logstream.Subscription, bool) {
+	sess, err := a.getSession(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not connected. Please connect first."})
+		return nil, false
+	}
+
+	pool := a.sessionManager.Pool()
+	retain := func() func() {
+		if release, ok := pool.Retain(sess.Client); ok {
+			return release
+		}
+		return func() {}
+	}
+
+	server := net.JoinHostPort(sess.Host, strconv.Itoa(sess.Port))
+	return a.logHub.Subscribe(server, sess.Client, retain, cursor), true
+}
+
+// StreamAdminLog streams new admin log entries as Server-Sent Events. Reconnecting
+// clients resume via the Last-Event-ID header or the cursor query parameter.
+func (a *API) StreamAdminLog(c *gin.Context) {
+	cursor := c.GetHeader("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.Query("cursor")
+	}
+
+	sub, ok := a.subscribeLogs(c, cursor)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-sub.Done():
+			return
+		case entry := <-sub.Entries():
+			c.Render(-1, sse.Event{Id: entry.Cursor, Event: "log", Data: entry})
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// StreamAdminLogWS streams new admin log entries as JSON messages over a WebSocket,
+// resuming after the cursor query parameter if given
+func (a *API) StreamAdminLogWS(c *gin.Context) {
+	sub, ok := a.subscribeLogs(c, c.Query("cursor"))
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// Drain client frames so close and ping frames are processed
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-sub.Done():
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "resume from last cursor"), time.Now().Add(time.Second))
+			return
+		case entry := <-sub.Entries():
+			conn.SetWriteDeadline(time.Now().Add(streamHeartbeat))
+			if err := conn.WriteJSON(entry); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// schema is a literal JSON Schema fragment, used where a handler responds with gin.H
+type schema map[string]any
+
+// oneOf documents a value that is one of several Go types or schema literals
+type oneOf []any
+
+// typeRef embeds a Go type's schema inside a schema literal
+type typeRef struct{ t reflect.Type }
+
+// typeOf returns a typeRef for T
+func typeOf[T any]() typeRef {
+	return typeRef{reflect.TypeOf((*T)(nil)).Elem()}
+}
+
+// queryParam documents a query string parameter
+type queryParam struct {
+	Name        string
+	Description string
+	Enum        []string
+	Required    bool
+}
+
+// routeDoc documents one route. Request and Response are Go values whose types are
+// reflected into JSON Schema, or schema literals.
+type routeDoc struct {
+	Summary     string
+	Tag         string
+	Command     string   // RCON command executed, if any
+	Alternates  []string // Other RCON commands the request can select instead of Command
+	Query       []queryParam
+	Request     any
+	Response    any
+	ContentType string // Response media type when not application/json
+}
+
+// commands lists every RCON command the route can run
+func (doc routeDoc) commands() []string {
+	if doc.Command == "" {
+		return nil
+	}
+	return append([]string{doc.Command}, doc.Alternates...)
+}
+
+var pathParamRe = regexp.MustCompile(`:(\w+)`)
+
+// schemaBuilder turns Go types into JSON Schema, collecting named structs as components
+type schemaBuilder struct {
+	components map[string]any
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// componentName names a struct schema; types outside this package keep their package prefix
+func componentName(t reflect.Type) string {
+	pkg := path.Base(t.PkgPath())
+	if pkg == "api" {
+		name := t.Name()
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+	return pkg + "." + t.Name()
+}
+
+// of returns the schema for v, which is a schema literal, oneOf, typeRef or Go value
+func (b *schemaBuilder) of(v any) any {
+	switch t := v.(type) {
+	case schema, oneOf, typeRef:
+		return b.resolve(t)
+	}
+	return b.typeSchema(reflect.TypeOf(v))
+}
+
+// resolve replaces typeRef and oneOf values nested in schema literals
+func (b *schemaBuilder) resolve(v any) any {
+	switch t := v.(type) {
+	case schema:
+		resolved := make(schema, len(t))
+		for k, val := range t {
+			resolved[k] = b.resolve(val)
+		}
+		return resolved
+	case oneOf:
+		variants := make([]any, len(t))
+		for i, variant := range t {
+			variants[i] = b.of(variant)
+		}
+		return schema{"oneOf": variants}
+	case typeRef:
+		return b.typeSchema(t.t)
+	default:
+		return v
+	}
+}
+
+func (b *schemaBuilder) typeSchema(t reflect.Type) any {
+	if t == nil {
+		return schema{}
+	}
+	switch t {
+	case timeType:
+		return schema{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return schema{}
+	}
+
+	// Types with custom JSON encodings that marshal to a string, such as auth.Role
+	if t.Kind() == reflect.Int && reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		return schema{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.typeSchema(t.Elem())
+	case reflect.String:
+		return schema{"type": "string"}
+	case reflect.Bool:
+		return schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return schema{"type": "array", "items": b.typeSchema(t.Elem())}
+	case reflect.Map:
+		return schema{"type": "object", "additionalProperties": b.typeSchema(t.Elem())}
+	case reflect.Interface:
+		return schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name := componentName(t)
+		if _, ok := b.components[name]; !ok {
+			b.components[name] = schema{} // Placeholder guards against recursive types
+			b.components[name] = b.structSchema(t)
+		}
+		return schema{"$ref": "#/components/schemas/" + name}
+	}
+	return schema{}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) schema {
+	properties := schema{}
+	var required []string
+	b.addFields(t, properties, &required)
+
+	s := schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s
+}
+
+// addFields adds a struct's JSON fields, flattening embedded structs as encoding/json does
+func (b *schemaBuilder) addFields(t reflect.Type, properties schema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			b.addFields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = b.typeSchema(field.Type)
+		binding := field.Tag.Get("binding")
+		for _, rule := range strings.Split(binding, ",") {
+			if rule == "required" {
+				*required = append(*required, name)
+			}
+		}
+	}
+}
+
+// buildOpenAPI generates the OpenAPI document for the registered routes.
+// Routes without a routeDocs entry are still listed, with a generic operation.
+func buildOpenAPI(routes gin.RoutesInfo, version string) map[string]any {
+	b := &schemaBuilder{components: map[string]any{
+		"Error": schema{
+			"type":       "object",
+			"properties": schema{"error": schema{"type": "string"}},
+			"required":   []string{"error"},
+		},
+		"RCONContent": schema{
+			"description": "ContentBody of the RCON response, decoded from JSON when possible",
+		},
+	}}
+
+	for _, v := range rconPayloads {
+		b.of(v)
+	}
+
+	paths := map[string]map[string]any{}
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, "/api/") && route.Path != "/health" && route.Path != "/version" {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		doc, documented := routeDocs[key]
+
+		op := schema{
+			"operationId": operationID(route.Handler),
+			"summary":     doc.Summary,
+			"tags":        []string{doc.Tag},
+			"responses":   b.responses(doc),
+		}
+		if !documented {
+			op["summary"] = "Undocumented route"
+			op["tags"] = []string{"Undocumented"}
+		}
+		switch commands := doc.commands(); len(commands) {
+		case 0:
+		case 1:
+			op["x-rcon-command"] = commands[0]
+		default:
+			op["x-rcon-command"] = commands
+		}
+		if strings.HasPrefix(route.Path, "/api/") {
+			op["x-required-role"] = requiredRole(route.Method, route.Path).String()
+		}
+
+		var params []any
+		for _, name := range pathParamRe.FindAllStringSubmatch(route.Path, -1) {
+			params = append(params, schema{"name": name[1], "in": "path", "required": true, "schema": schema{"type": "string"}})
+		}
+		for _, q := range doc.Query {
+			s := schema{"type": "string"}
+			if len(q.Enum) > 0 {
+				s["enum"] = q.Enum
+			}
+			params = append(params, schema{"name": q.Name, "in": "query", "required": q.Required, "description": q.Description, "schema": s})
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		if doc.Request != nil {
+			op["requestBody"] = schema{
+				"required": true,
+				"content":  schema{"application/json": schema{"schema": b.of(doc.Request)}},
+			}
+		}
+
+		openAPIPath := pathParamRe.ReplaceAllString(route.Path, "{$1}")
+		if paths[openAPIPath] == nil {
+			paths[openAPIPath] = map[string]any{}
+		}
+		paths[openAPIPath][strings.ToLower(route.Method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": schema{
+			"title":       "HLLRCON API",
+			"description": "REST API wrapper for the Hell Let Loose RCON V2 protocol",
+			"version":     version,
+		},
+		"servers": []any{schema{"url": "/"}},
+		"paths":   paths,
+		"components": schema{
+			"schemas": b.components,
+			"securitySchemes": schema{
+				"basicAuth":  schema{"type": "http", "scheme": "basic"},
+				"bearerAuth": schema{"type": "http", "scheme": "bearer", "description": "API key from /api/v2/api-keys"},
+				"session":    schema{"type": "apiKey", "in": "cookie", "name": "hll_session"},
+			},
+		},
+		"security": []any{
+			schema{"basicAuth": []string{}, "session": []string{}},
+			schema{"bearerAuth": []string{}},
+		},
+	}
+}
+
+// responses documents the success response and the shared error response
+func (b *schemaBuilder) responses(doc routeDoc) schema {
+	errorResponse := schema{
+		"description": "Error",
+		"content":     schema{"application/json": schema{"schema": schema{"$ref": "#/components/schemas/Error"}}},
+	}
+
+	var success any = schema{"$ref": "#/components/schemas/RCONContent"}
+	if doc.Response != nil {
+		success = b.of(doc.Response)
+	}
+	contentType := doc.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	return schema{
+		"200":     schema{"description": "Success", "content": schema{contentType: schema{"schema": success}}},
+		"default": errorResponse,
+	}
+}
+
+// operationID derives an operation ID from the handler name, e.g. "KickPlayer"
+func operationID(handler string) string {
+	name := handler[strings.LastIndex(handler, ".")+1:]
+	return strings.TrimSuffix(name, "-fm")
+}
+
+// checkOpenAPICoverage logs routes that have no routeDocs entry
+func checkOpenAPICoverage(routes gin.RoutesInfo) {
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+		if _, ok := routeDocs[route.Method+" "+route.Path]; !ok {
+			slog.Warn("Route has no OpenAPI documentation", "method", route.Method, "path", route.Path)
+		}
+	}
+}
+
+// OpenAPI serves the generated OpenAPI document
+func (a *API) OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", a.openapi)
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"github.com/Sledro/hllrcon/audit"
+	"github.com/Sledro/hllrcon/auth"
+	"github.com/Sledro/hllrcon/history"
+	"github.com/Sledro/hllrcon/logparse"
+	"github.com/Sledro/hllrcon/logstream"
+	"github.com/Sledro/hllrcon/profile"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/schedule"
+	"github.com/Sledro/hllrcon/session"
+	"github.com/Sledro/hllrcon/watchlist"
+)
+
+// statusSchema documents {"status": "..."} acknowledgements
+var statusSchema = schema{"type": "object", "properties": schema{"status": schema{"type": "string"}}}
+
+// logEventSchema documents any parsed admin log event
+var logEventSchema = oneOf{
+	logparse.Kill{}, logparse.Chat{}, logparse.Connection{}, logparse.TeamSwitch{},
+	logparse.MatchStart{}, logparse.MatchEnded{}, logparse.Vote{}, logparse.Sanction{},
+	logparse.Message{}, logparse.Camera{}, logparse.Unknown{},
+}
+
+// rconPayloads are the typed RCON responses decoded by the rcon package, published as
+// components so clients can generate models for them
+var rconPayloads = []any{
+	rcon.Player{}, rcon.SessionInfo{}, rcon.LogEntry{}, rcon.CommandInfo{},
+	rcon.CommandReference{}, rcon.AdminUser{}, rcon.Ban{},
+}
+
+// routeDocs documents every route under /api/v2, keyed by "METHOD path".
+// checkOpenAPICoverage reports routes missing from this map at startup.
+var routeDocs = map[string]routeDoc{
+	// Service
+	"GET /health":              {Summary: "Liveness check", Tag: "Service", Response: statusSchema},
+	"GET /version":             {Summary: "Build information", Tag: "Service", Response: schema{"type": "object", "properties": schema{"version": schema{"type": "string"}, "git_commit": schema{"type": "string"}, "build_date": schema{"type": "string"}}}},
+	"GET /api/v2/openapi.json": {Summary: "This OpenAPI document", Tag: "Service", Response: schema{"type": "object"}},
+
+	// Connection management
+	"POST /api/v2/connect": {Summary: "Open an RCON session with credentials or a saved profile", Tag: "Connection", Request: connectRequest{},
+		Response: schema{"type": "object", "properties": schema{"status": schema{"type": "string"}, "session_id": schema{"type": "string"}, "host": schema{"type": "string"}, "port": schema{"type": "integer"}, "profile": schema{"type": "string"}}}},
+	"POST /api/v2/disconnect": {Summary: "Close the current session", Tag: "Connection", Response: statusSchema},
+	"GET /api/v2/connection/status": {Summary: "Current session status", Tag: "Connection",
+		Response: schema{"type": "object", "properties": schema{"connected": schema{"type": "boolean"}, "state": schema{"type": "string", "enum": []string{"disconnected", "connecting", "connected", "closed"}}, "host": schema{"type": "string"}, "port": schema{"type": "integer"}, "profile": schema{"type": "string"}, "connected_at": schema{"type": "string", "format": "date-time"}, "last_used": schema{"type": "string", "format": "date-time"}}, "required": []string{"connected"}}},
+	"GET /api/v2/connection/pool": {Summary: "Connection pool usage for this server and overall", Tag: "Connection",
+		Response: schema{"type": "object", "properties": schema{"server": typeOf[session.ServerStats](), "total": typeOf[session.PoolStats]()}}},
+
+	// App users, API keys and audit
+	"GET /api/v2/auth/me": {Summary: "The authenticated app user", Tag: "Access",
+		Response: schema{"type": "object", "properties": schema{"access_control": schema{"type": "boolean"}, "username": schema{"type": "string"}, "role": schema{"type": "string"}}}},
+	"GET /api/v2/users":              {Summary: "List app users", Tag: "Access", Response: schema{"type": "object", "properties": schema{"users": schema{"type": "array", "items": typeOf[auth.User]()}}}},
+	"POST /api/v2/users":             {Summary: "Create an app user", Tag: "Access", Request: userRequest{}, Response: auth.User{}},
+	"PUT /api/v2/users/:username":    {Summary: "Change an app user's role or password", Tag: "Access", Request: userRequest{}, Response: auth.User{}},
+	"DELETE /api/v2/users/:username": {Summary: "Delete an app user", Tag: "Access", Response: statusSchema},
+	"GET /api/v2/api-keys":           {Summary: "List API keys", Tag: "Access", Response: schema{"type": "object", "properties": schema{"api_keys": schema{"type": "array", "items": typeOf[auth.APIKey]()}}}},
+	"POST /api/v2/api-keys": {Summary: "Create an API key; the token is only returned once", Tag: "Access", Request: createAPIKeyRequest{},
+		Response: schema{"type": "object", "properties": schema{"api_key": typeOf[auth.APIKey](), "token": schema{"type": "string"}}}},
+	"DELETE /api/v2/api-keys/:id": {Summary: "Revoke an API key", Tag: "Access", Response: statusSchema},
+	"GET /api/v2/audit": {Summary: "Query the audit log", Tag: "Audit",
+		Query: []queryParam{
+			{Name: "user", Description: "App user or apikey:<name>"},
+			{Name: "server", Description: "host:port or profile name"},
+			{Name: "command", Description: "RCON command name"},
+			{Name: "since", Description: "RFC 3339 time or Unix seconds"},
+			{Name: "until", Description: "RFC 3339 time or Unix seconds"},
+			{Name: "limit", Description: "Most recent N entries (default 500 for JSON)"},
+			{Name: "format", Description: "Response format", Enum: []string{"json", "csv", "jsonl"}},
+		},
+		Response: schema{"type": "object", "properties": schema{"entries": schema{"type": "array", "items": typeOf[audit.Entry]()}}}},
+	"GET /api/v2/audit/verify": {Summary: "Verify the audit log hash chain", Tag: "Audit", Response: audit.VerifyResult{}},
+
+	// Saved server profiles
+	"GET /api/v2/profiles":          {Summary: "List saved server profiles", Tag: "Profiles", Response: schema{"type": "object", "properties": schema{"profiles": schema{"type": "array", "items": typeOf[profile.Profile]()}}}},
+	"POST /api/v2/profiles":         {Summary: "Save a server profile", Tag: "Profiles", Request: profileRequest{}, Response: profile.Profile{}},
+	"GET /api/v2/profiles/:name":    {Summary: "Get a saved server profile", Tag: "Profiles", Response: profile.Profile{}},
+	"PUT /api/v2/profiles/:name":    {Summary: "Update a saved server profile; omit password to keep it", Tag: "Profiles", Request: profileRequest{}, Response: profile.Profile{}},
+	"DELETE /api/v2/profiles/:name": {Summary: "Delete a saved server profile", Tag: "Profiles", Response: statusSchema},
+
+	// Server info
+	"GET /api/v2/server": {Summary: "Server information", Tag: "Server", Command: "GetServerInformation",
+		Query: []queryParam{
+			{Name: "type", Description: "Information type (default session)", Enum: rcon.ServerInfoTypes()},
+			{Name: "value", Description: "Type-specific argument, required for player"},
+		},
+		Response: oneOf{rcon.SessionInfo{}, rcon.PlayerList{}, rcon.Player{}, rcon.MapList{}, rcon.MapSequence{}, rcon.ServerConfig{}, rcon.VIPList{}, rcon.BannedWordList{}}},
+	"GET /api/v2/map-rotation":      {Summary: "Current map rotation", Tag: "Maps", Command: "GetServerInformation", Response: rcon.MapList{}},
+	"GET /api/v2/map-sequence":      {Summary: "Current map sequence", Tag: "Maps", Command: "GetServerInformation", Response: rcon.MapSequence{}},
+	"GET /api/v2/profanities":       {Summary: "Banned words", Tag: "Server", Command: "GetServerInformation", Response: rcon.BannedWordList{}},
+	"GET /api/v2/commands":          {Summary: "Commands the server exposes", Tag: "Server", Command: "GetDisplayableCommands"},
+	"GET /api/v2/command-reference": {Summary: "Parameter reference for a command", Tag: "Server", Command: "GetClientReferenceData", Query: []queryParam{{Name: "command", Description: "Command ID", Required: true}}},
+	"GET /api/v2/changelist":        {Summary: "Server build changelist", Tag: "Server", Command: "GetServerChangelist"},
+	"POST /api/v2/command":          {Summary: "Execute any command reported by GetDisplayableCommands; the body is validated against GetClientReferenceData", Tag: "Server", Request: commandRequest{}},
+	"POST /api/v2/batch":            {Summary: "Run several commands in order on one connection, with per-operation results and optional stop_on_error", Tag: "Server", Request: batchRequest{}, Response: batchResponse{}},
+	"GET /api/v2/maps":              {Summary: "All known map IDs, one per line", Tag: "Maps", Response: schema{"type": "string"}, ContentType: "text/plain"},
+
+	// Admin log
+	"GET /api/v2/logs": {Summary: "Recent admin log", Tag: "Logs", Command: "GetAdminLog",
+		Query: []queryParam{
+			{Name: "seconds", Description: "How far back to read (default 3600)"},
+			{Name: "filter", Description: "Server-side text filter (raw format)"},
+			{Name: "format", Description: "raw returns the RCON payload, events returns parsed events", Enum: []string{"raw", "events"}},
+			{Name: "type", Description: "Comma-separated event types (events format)"},
+			{Name: "player", Description: "Player name or ID (events format)"},
+		},
+		Response: oneOf{
+			schema{"$ref": "#/components/schemas/RCONContent"},
+			schema{"type": "object", "properties": schema{"events": schema{"type": "array", "items": logEventSchema}}},
+		}},
+	"GET /api/v2/logs/stream": {Summary: "Live admin log over Server-Sent Events", Tag: "Logs", ContentType: "text/event-stream",
+		Query: []queryParam{{Name: "cursor", Description: "Resume after this cursor (or use Last-Event-ID)"}}, Response: logstream.Entry{}},
+	"GET /api/v2/logs/ws": {Summary: "Live admin log over WebSocket", Tag: "Logs",
+		Query: []queryParam{{Name: "cursor", Description: "Resume after this cursor"}}, Response: logstream.Entry{}},
+
+	// Players
+	"GET /api/v2/players":              {Summary: "Online players", Tag: "Players", Command: "GetServerInformation", Response: rcon.PlayerList{}},
+	"GET /api/v2/players/:id":          {Summary: "A single online player", Tag: "Players", Command: "GetServerInformation", Response: rcon.Player{}},
+	"GET /api/v2/player-history/:id":   {Summary: "Recorded history of a player, online or not", Tag: "Players", Response: history.Record{}},
+	"POST /api/v2/players/:id/message": {Summary: "Message a player", Tag: "Players", Command: "MessagePlayer", Request: messageRequest{}},
+	"POST /api/v2/kick":                {Summary: "Kick a player", Tag: "Players", Command: "KickPlayer", Request: playerReasonRequest{}},
+	"POST /api/v2/punish":              {Summary: "Punish (kill) a player", Tag: "Players", Command: "PunishPlayer", Request: playerReasonRequest{}},
+	"POST /api/v2/force-team-switch":   {Summary: "Move a player to the other team", Tag: "Players", Command: "ForceTeamSwitch", Request: forceTeamSwitchRequest{}},
+	"POST /api/v2/remove-from-squad":   {Summary: "Remove a player from their squad", Tag: "Players", Command: "RemovePlayerFromPlatoon", Request: playerReasonRequest{}},
+	"POST /api/v2/disband-squad":       {Summary: "Disband a squad", Tag: "Players", Command: "DisbandPlatoon", Request: disbandSquadRequest{}},
+	"POST /api/v2/broadcast":           {Summary: "Broadcast a server message", Tag: "Players", Command: "ServerBroadcast", Request: messageRequest{}},
+	"POST /api/v2/welcome-message":     {Summary: "Set the welcome message", Tag: "Server", Command: "SetWelcomeMessage", Request: messageRequest{}},
+	"GET /api/v2/players/search": {Summary: "Find current and recorded players by ID or any name they have used", Tag: "Players",
+		Query: []queryParam{
+			{Name: "q", Description: "Player ID or name; case, clan tags, separators and look-alike characters are ignored and small typos tolerated", Required: true},
+			{Name: "limit", Description: "Maximum results, 1-100 (default 20)"},
+		},
+		Response: schema{"type": "object", "properties": schema{"players": schema{"type": "array", "items": typeOf[history.Match]()}}}},
+
+	// Watchlist
+	"GET /api/v2/watchlist":        {Summary: "Watched players", Tag: "Watchlist", Response: schema{"type": "object", "properties": schema{"entries": schema{"type": "array", "items": typeOf[watchlist.Entry]()}}}},
+	"POST /api/v2/watchlist":       {Summary: "Watch a player", Tag: "Watchlist", Request: watchlistRequest{}, Response: watchlist.Entry{}},
+	"GET /api/v2/watchlist/:id":    {Summary: "A watched player", Tag: "Watchlist", Response: watchlist.Entry{}},
+	"PUT /api/v2/watchlist/:id":    {Summary: "Change the note of a watched player", Tag: "Watchlist", Request: watchlistUpdateRequest{}, Response: watchlist.Entry{}},
+	"DELETE /api/v2/watchlist/:id": {Summary: "Stop watching a player", Tag: "Watchlist", Response: statusSchema},
+	"GET /api/v2/watchlist/alerts/stream": {Summary: "Watched players joining any connected server, as Server-Sent Events", Tag: "Watchlist", ContentType: "text/event-stream",
+		Response: watchlist.Alert{}},
+
+	// Scheduled broadcasts
+	"GET /api/v2/scheduled-broadcasts": {Summary: "Scheduled broadcasts with their next run, and the message placeholders", Tag: "Schedules",
+		Response: schema{"type": "object", "properties": schema{"broadcasts": schema{"type": "array", "items": typeOf[schedule.Broadcast]()}, "variables": schema{"type": "object", "additionalProperties": schema{"type": "string"}}}}},
+	"POST /api/v2/scheduled-broadcasts":       {Summary: "Schedule a broadcast or welcome message for a saved server", Tag: "Schedules", Request: scheduledBroadcastRequest{}, Response: schedule.Broadcast{}},
+	"GET /api/v2/scheduled-broadcasts/:id":    {Summary: "A scheduled broadcast", Tag: "Schedules", Response: schedule.Broadcast{}},
+	"PUT /api/v2/scheduled-broadcasts/:id":    {Summary: "Replace a scheduled broadcast", Tag: "Schedules", Request: scheduledBroadcastRequest{}, Response: schedule.Broadcast{}},
+	"DELETE /api/v2/scheduled-broadcasts/:id": {Summary: "Delete a scheduled broadcast and its history", Tag: "Schedules", Response: statusSchema},
+	"GET /api/v2/scheduled-broadcasts/:id/runs": {Summary: "Recent runs of a scheduled broadcast, newest first", Tag: "Schedules",
+		Response: schema{"type": "object", "properties": schema{"runs": schema{"type": "array", "items": typeOf[schedule.Run]()}}}},
+	"GET /api/v2/scheduled-tasks": {Summary: "Scheduled tasks with their next run", Tag: "Schedules",
+		Response: schema{"type": "object", "properties": schema{"tasks": schema{"type": "array", "items": typeOf[schedule.Task]()}}}},
+	"POST /api/v2/scheduled-tasks":       {Summary: "Schedule commands for a saved server; steps are validated against the server", Tag: "Schedules", Request: scheduledTaskRequest{}, Response: schedule.Task{}},
+	"GET /api/v2/scheduled-tasks/:id":    {Summary: "A scheduled task", Tag: "Schedules", Response: schedule.Task{}},
+	"PUT /api/v2/scheduled-tasks/:id":    {Summary: "Replace a scheduled task", Tag: "Schedules", Request: scheduledTaskRequest{}, Response: schedule.Task{}},
+	"DELETE /api/v2/scheduled-tasks/:id": {Summary: "Delete a scheduled task and its history", Tag: "Schedules", Response: statusSchema},
+	"GET /api/v2/scheduled-tasks/:id/runs": {Summary: "Recent runs of a scheduled task, newest first", Tag: "Schedules",
+		Response: schema{"type": "object", "properties": schema{"runs": schema{"type": "array", "items": typeOf[schedule.TaskRun]()}}}},
+
+	// VIPs
+	"GET /api/v2/vips":       {Summary: "VIP list", Tag: "VIPs", Command: "GetServerInformation", Response: rcon.VIPList{}},
+	"POST /api/v2/vips":      {Summary: "Add a VIP", Tag: "VIPs", Command: "AddVip", Request: addVIPRequest{}},
+	"DELETE /api/v2/vips":    {Summary: "Remove a VIP", Tag: "VIPs", Command: "RemoveVip", Request: playerIDRequest{}},
+	"POST /api/v2/vip-slots": {Summary: "Set reserved VIP slots", Tag: "VIPs", Command: "SetVipSlotCount", Request: vipSlotCountRequest{}},
+
+	// Admins
+	"GET /api/v2/admins":       {Summary: "Admin list", Tag: "Admins", Command: "GetAdminUsers"},
+	"GET /api/v2/admin-groups": {Summary: "Admin groups", Tag: "Admins", Command: "GetAdminGroups"},
+	"POST /api/v2/admins":      {Summary: "Add an admin", Tag: "Admins", Command: "AddAdmin", Request: addAdminRequest{}},
+	"DELETE /api/v2/admins":    {Summary: "Remove an admin", Tag: "Admins", Command: "RemoveAdmin", Request: playerIDRequest{}},
+
+	// Bans
+	"GET /api/v2/bans": {Summary: "Ban list", Tag: "Bans", Command: "GetTemporaryBans", Alternates: []string{"GetPermanentBans"},
+		Query: []queryParam{{Name: "type", Description: "Which ban list", Enum: []string{"temp", "perma"}, Required: true}}},
+	"POST /api/v2/temp-ban":    {Summary: "Temporarily ban a player", Tag: "Bans", Command: "TemporaryBanPlayer", Request: tempBanRequest{}},
+	"POST /api/v2/perma-ban":   {Summary: "Permanently ban a player", Tag: "Bans", Command: "PermanentBanPlayer", Request: permaBanRequest{}},
+	"DELETE /api/v2/temp-ban":  {Summary: "Lift a temporary ban", Tag: "Bans", Command: "RemoveTemporaryBan", Request: playerIDRequest{}},
+	"DELETE /api/v2/perma-ban": {Summary: "Lift a permanent ban", Tag: "Bans", Command: "RemovePermanentBan", Request: playerIDRequest{}},
+
+	// Maps
+	"POST /api/v2/change-map":       {Summary: "Change the current map", Tag: "Maps", Command: "ChangeMap", Request: mapNameRequest{}},
+	"POST /api/v2/map-rotation":     {Summary: "Add a map to the rotation", Tag: "Maps", Command: "AddMapToRotation", Request: mapIndexRequest{}},
+	"DELETE /api/v2/map-rotation":   {Summary: "Remove a map from the rotation", Tag: "Maps", Command: "RemoveMapFromRotation", Request: indexRequest{}},
+	"POST /api/v2/map-sequence":     {Summary: "Add a map to the sequence", Tag: "Maps", Command: "AddMapToSequence", Request: mapIndexRequest{}},
+	"DELETE /api/v2/map-sequence":   {Summary: "Remove a map from the sequence", Tag: "Maps", Command: "RemoveMapFromSequence", Request: indexRequest{}},
+	"PUT /api/v2/map-sequence/move": {Summary: "Move a map within the sequence", Tag: "Maps", Command: "MoveMapInSequence", Request: moveMapRequest{}},
+	"POST /api/v2/map-shuffle":      {Summary: "Toggle map shuffle", Tag: "Maps", Command: "SetMapShuffleEnabled", Request: enableRequest{}},
+	"POST /api/v2/sector-layout":    {Summary: "Set the sector layout", Tag: "Maps", Command: "SetSectorLayout", Request: sectorLayoutRequest{}},
+	"POST /api/v2/dynamic-weather":  {Summary: "Toggle dynamic weather for a map", Tag: "Maps", Command: "SetDynamicWeatherEnabled", Request: dynamicWeatherRequest{}},
+
+	// Server settings
+	"POST /api/v2/team-switch-cooldown":   {Summary: "Set the team switch cooldown", Tag: "Settings", Command: "SetTeamSwitchCooldown", Request: teamSwitchCooldownRequest{}},
+	"POST /api/v2/max-queued-players":     {Summary: "Set the maximum queue length", Tag: "Settings", Command: "SetMaxQueuedPlayers", Request: maxQueuedPlayersRequest{}},
+	"POST /api/v2/idle-kick-duration":     {Summary: "Set the idle kick timeout", Tag: "Settings", Command: "SetIdleKickDuration", Request: idleKickDurationRequest{}},
+	"POST /api/v2/high-ping-threshold":    {Summary: "Set the high ping threshold", Tag: "Settings", Command: "SetHighPingThreshold", Request: highPingThresholdRequest{}},
+	"POST /api/v2/auto-balance/enabled":   {Summary: "Toggle team auto balance", Tag: "Settings", Command: "SetAutoBalanceEnabled", Request: enableRequest{}},
+	"POST /api/v2/auto-balance/threshold": {Summary: "Set the auto balance threshold", Tag: "Settings", Command: "SetAutoBalanceThreshold", Request: autoBalanceThresholdRequest{}},
+	"POST /api/v2/vote-kick/enabled":      {Summary: "Toggle vote kick", Tag: "Settings", Command: "SetVoteKickEnabled", Request: enableRequest{}},
+	"POST /api/v2/vote-kick/threshold":    {Summary: "Set vote kick thresholds", Tag: "Settings", Command: "SetVoteKickThreshold", Request: voteKickThresholdRequest{}},
+	"POST /api/v2/vote-kick/reset":        {Summary: "Reset vote kick thresholds", Tag: "Settings", Command: "ResetVoteKickThreshold"},
+	"POST /api/v2/match-timer":            {Summary: "Set the match length for a game mode", Tag: "Settings", Command: "SetMatchTimer", Request: matchTimerRequest{}},
+	"DELETE /api/v2/match-timer":          {Summary: "Reset the match length for a game mode", Tag: "Settings", Command: "RemoveMatchTimer", Request: gameModeRequest{}},
+	"POST /api/v2/warmup-timer":           {Summary: "Set the warmup length for a game mode", Tag: "Settings", Command: "SetWarmupTimer", Request: warmupTimerRequest{}},
+	"DELETE /api/v2/warmup-timer":         {Summary: "Reset the warmup length for a game mode", Tag: "Settings", Command: "RemoveWarmupTimer", Request: gameModeRequest{}},
+	"POST /api/v2/profanities":            {Summary: "Add banned words", Tag: "Server", Command: "AddBannedWords", Request: bannedWordsRequest{}},
+	"DELETE /api/v2/profanities":          {Summary: "Remove banned words", Tag: "Server", Command: "RemoveBannedWords", Request: bannedWordsRequest{}},
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testRoutes registers the API on a fresh router and returns its /api/ routes
+func testRoutes(t *testing.T) map[string]bool {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewAPI(nil, "test", "", "", false, RCONConfig{}, nil, Stores{}).SetupRoutes(router)
+
+	routes := make(map[string]bool)
+	for _, route := range router.Routes() {
+		if strings.HasPrefix(route.Path, "/api/") {
+			routes[route.Method+" "+route.Path] = true
+		}
+	}
+	if len(routes) == 0 {
+		t.Fatal("no /api/ routes registered")
+	}
+	return routes
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	routes := testRoutes(t)
+	for route := range routes {
+		if _, ok := routeDocs[route]; !ok {
+			t.Errorf("%s has no routeDocs entry", route)
+		}
+	}
+	for route := range routeDocs {
+		if strings.Contains(route, " /api/") && !routes[route] {
+			t.Errorf("routeDocs entry %s matches no route", route)
+		}
+	}
+}
+
+func TestEveryRouteHasPermission(t *testing.T) {
+	routes := testRoutes(t)
+	for route := range routes {
+		if _, ok := routePermissions[route]; !ok {
+			t.Errorf("%s has no routePermissions entry", route)
+		}
+	}
+	for route := range routePermissions {
+		if !routes[route] {
+			t.Errorf("routePermissions entry %s matches no route", route)
+		}
+	}
+}
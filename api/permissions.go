@@ -0,0 +1,338 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Sledro/hllrcon/auth"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	userContextKey   = "hllrcon.user"
+	apiKeyContextKey = "hllrcon.apikey"
+)
+
+// routePermissions is the minimum role for each /api/v2 route, keyed by "METHOD path".
+// Routes missing from the map require the owner role.
+var routePermissions = map[string]auth.Role{
+	// Connection management
+	"GET /api/v2/openapi.json":       auth.RoleViewer,
+	"POST /api/v2/connect":           auth.RoleViewer,
+	"POST /api/v2/disconnect":        auth.RoleViewer,
+	"GET /api/v2/connection/status":  auth.RoleViewer,
+	"GET /api/v2/connection/pool":    auth.RoleViewer,
+	"GET /api/v2/auth/me":            auth.RoleViewer,
+	"GET /api/v2/profiles":           auth.RoleViewer,
+	"GET /api/v2/profiles/:name":     auth.RoleViewer,
+	"POST /api/v2/profiles":          auth.RoleAdmin,
+	"PUT /api/v2/profiles/:name":     auth.RoleAdmin,
+	"DELETE /api/v2/profiles/:name":  auth.RoleAdmin,
+	"GET /api/v2/audit":              auth.RoleAdmin,
+	"GET /api/v2/audit/verify":       auth.RoleAdmin,
+	"GET /api/v2/api-keys":           auth.RoleAdmin,
+	"POST /api/v2/api-keys":          auth.RoleAdmin,
+	"DELETE /api/v2/api-keys/:id":    auth.RoleAdmin,
+	"GET /api/v2/users":              auth.RoleOwner,
+	"POST /api/v2/users":             auth.RoleOwner,
+	"PUT /api/v2/users/:username":    auth.RoleOwner,
+	"DELETE /api/v2/users/:username": auth.RoleOwner,
+
+	// Read-only server information
+	"GET /api/v2/server":            auth.RoleViewer,
+	"GET /api/v2/map-rotation":      auth.RoleViewer,
+	"GET /api/v2/map-sequence":      auth.RoleViewer,
+	"GET /api/v2/logs":              auth.RoleViewer,
+	"GET /api/v2/logs/stream":       auth.RoleViewer,
+	"GET /api/v2/logs/ws":           auth.RoleViewer,
+	"GET /api/v2/profanities":       auth.RoleViewer,
+	"GET /api/v2/commands":          auth.RoleViewer,
+	"GET /api/v2/command-reference": auth.RoleViewer,
+	"GET /api/v2/changelist":        auth.RoleViewer,
+	"GET /api/v2/maps":              auth.RoleViewer,
+	"GET /api/v2/players":           auth.RoleViewer,
+	"GET /api/v2/players/:id":       auth.RoleViewer,
+	"GET /api/v2/vips":              auth.RoleViewer,
+	"GET /api/v2/admins":            auth.RoleViewer,
+	"GET /api/v2/admin-groups":      auth.RoleViewer,
+	"GET /api/v2/bans":              auth.RoleViewer,
+
+	// Player history
+	"GET /api/v2/player-history/:id": auth.RoleViewer,
+	"GET /api/v2/players/search":     auth.RoleViewer,
+
+	// Watchlist
+	"GET /api/v2/watchlist":               auth.RoleViewer,
+	"GET /api/v2/watchlist/:id":           auth.RoleViewer,
+	"GET /api/v2/watchlist/alerts/stream": auth.RoleViewer,
+	"POST /api/v2/watchlist":              auth.RoleModerator,
+	"PUT /api/v2/watchlist/:id":           auth.RoleModerator,
+	"DELETE /api/v2/watchlist/:id":        auth.RoleModerator,
+
+	// Scheduled broadcasts
+	"GET /api/v2/scheduled-broadcasts":          auth.RoleViewer,
+	"GET /api/v2/scheduled-broadcasts/:id":      auth.RoleViewer,
+	"GET /api/v2/scheduled-broadcasts/:id/runs": auth.RoleViewer,
+	"POST /api/v2/scheduled-broadcasts":         auth.RoleModerator,
+	"PUT /api/v2/scheduled-broadcasts/:id":      auth.RoleModerator,
+	"DELETE /api/v2/scheduled-broadcasts/:id":   auth.RoleModerator,
+
+	// Scheduled tasks; saving a task checks each step against its dedicated route's role
+	"GET /api/v2/scheduled-tasks":          auth.RoleViewer,
+	"GET /api/v2/scheduled-tasks/:id":      auth.RoleViewer,
+	"GET /api/v2/scheduled-tasks/:id/runs": auth.RoleViewer,
+	"POST /api/v2/scheduled-tasks":         auth.RoleModerator,
+	"PUT /api/v2/scheduled-tasks/:id":      auth.RoleModerator,
+	"DELETE /api/v2/scheduled-tasks/:id":   auth.RoleModerator,
+
+	// Messaging and in-match moderation
+	"POST /api/v2/broadcast":           auth.RoleModerator,
+	"POST /api/v2/welcome-message":     auth.RoleModerator,
+	"POST /api/v2/players/:id/message": auth.RoleModerator,
+	"POST /api/v2/punish":              auth.RoleModerator,
+	"POST /api/v2/kick":                auth.RoleModerator,
+	"POST /api/v2/force-team-switch":   auth.RoleModerator,
+	"POST /api/v2/remove-from-squad":   auth.RoleModerator,
+	"POST /api/v2/disband-squad":       auth.RoleModerator,
+
+	// Bans
+	"POST /api/v2/temp-ban":    auth.RoleAdmin,
+	"POST /api/v2/perma-ban":   auth.RoleAdmin,
+	"DELETE /api/v2/temp-ban":  auth.RoleAdmin,
+	"DELETE /api/v2/perma-ban": auth.RoleAdmin,
+
+	// VIPs
+	"POST /api/v2/vips":      auth.RoleAdmin,
+	"DELETE /api/v2/vips":    auth.RoleAdmin,
+	"POST /api/v2/vip-slots": auth.RoleAdmin,
+
+	// Maps
+	"POST /api/v2/change-map":       auth.RoleAdmin,
+	"POST /api/v2/map-rotation":     auth.RoleAdmin,
+	"DELETE /api/v2/map-rotation":   auth.RoleAdmin,
+	"POST /api/v2/map-sequence":     auth.RoleAdmin,
+	"DELETE /api/v2/map-sequence":   auth.RoleAdmin,
+	"PUT /api/v2/map-sequence/move": auth.RoleAdmin,
+	"POST /api/v2/map-shuffle":      auth.RoleAdmin,
+	"POST /api/v2/sector-layout":    auth.RoleAdmin,
+	"POST /api/v2/match-timer":      auth.RoleAdmin,
+	"DELETE /api/v2/match-timer":    auth.RoleAdmin,
+	"POST /api/v2/warmup-timer":     auth.RoleAdmin,
+	"DELETE /api/v2/warmup-timer":   auth.RoleAdmin,
+	"POST /api/v2/dynamic-weather":  auth.RoleAdmin,
+	"POST /api/v2/profanities":      auth.RoleAdmin,
+	"DELETE /api/v2/profanities":    auth.RoleAdmin,
+
+	// Server settings
+	"POST /api/v2/team-switch-cooldown":   auth.RoleAdmin,
+	"POST /api/v2/max-queued-players":     auth.RoleAdmin,
+	"POST /api/v2/idle-kick-duration":     auth.RoleAdmin,
+	"POST /api/v2/high-ping-threshold":    auth.RoleAdmin,
+	"POST /api/v2/auto-balance/enabled":   auth.RoleAdmin,
+	"POST /api/v2/auto-balance/threshold": auth.RoleAdmin,
+	"POST /api/v2/vote-kick/enabled":      auth.RoleAdmin,
+	"POST /api/v2/vote-kick/threshold":    auth.RoleAdmin,
+	"POST /api/v2/vote-kick/reset":        auth.RoleAdmin,
+
+	// Raw passthrough; ExecuteRawCommand also applies the dedicated route's role per command
+	"POST /api/v2/command": auth.RoleAdmin,
+	// Batches; ExecuteBatch checks each operation against its dedicated route's role
+	"POST /api/v2/batch": auth.RoleModerator,
+
+	// Admin groups
+	"POST /api/v2/admins":   auth.RoleOwner,
+	"DELETE /api/v2/admins": auth.RoleOwner,
+}
+
+// commandRoutes lists the dedicated routes that run each RCON command, keyed by command
+// ID. Commands sent through /command, /batch or a scheduled task are checked against
+// the roles of these routes.
+var commandRoutes = map[string][]string{
+	"GetServerInformation":     {"GET /api/v2/server", "GET /api/v2/map-rotation", "GET /api/v2/map-sequence", "GET /api/v2/profanities", "GET /api/v2/players", "GET /api/v2/players/:id", "GET /api/v2/vips"},
+	"GetDisplayableCommands":   {"GET /api/v2/commands"},
+	"GetClientReferenceData":   {"GET /api/v2/command-reference"},
+	"GetServerChangelist":      {"GET /api/v2/changelist"},
+	"GetAdminLog":              {"GET /api/v2/logs"},
+	"MessagePlayer":            {"POST /api/v2/players/:id/message"},
+	"KickPlayer":               {"POST /api/v2/kick"},
+	"PunishPlayer":             {"POST /api/v2/punish"},
+	"ForceTeamSwitch":          {"POST /api/v2/force-team-switch"},
+	"RemovePlayerFromPlatoon":  {"POST /api/v2/remove-from-squad"},
+	"DisbandPlatoon":           {"POST /api/v2/disband-squad"},
+	"ServerBroadcast":          {"POST /api/v2/broadcast"},
+	"SetWelcomeMessage":        {"POST /api/v2/welcome-message"},
+	"AddVip":                   {"POST /api/v2/vips"},
+	"RemoveVip":                {"DELETE /api/v2/vips"},
+	"SetVipSlotCount":          {"POST /api/v2/vip-slots"},
+	"GetAdminUsers":            {"GET /api/v2/admins"},
+	"GetAdminGroups":           {"GET /api/v2/admin-groups"},
+	"AddAdmin":                 {"POST /api/v2/admins"},
+	"RemoveAdmin":              {"DELETE /api/v2/admins"},
+	"GetTemporaryBans":         {"GET /api/v2/bans"},
+	"GetPermanentBans":         {"GET /api/v2/bans"},
+	"TemporaryBanPlayer":       {"POST /api/v2/temp-ban"},
+	"PermanentBanPlayer":       {"POST /api/v2/perma-ban"},
+	"RemoveTemporaryBan":       {"DELETE /api/v2/temp-ban"},
+	"RemovePermanentBan":       {"DELETE /api/v2/perma-ban"},
+	"ChangeMap":                {"POST /api/v2/change-map"},
+	"AddMapToRotation":         {"POST /api/v2/map-rotation"},
+	"RemoveMapFromRotation":    {"DELETE /api/v2/map-rotation"},
+	"AddMapToSequence":         {"POST /api/v2/map-sequence"},
+	"RemoveMapFromSequence":    {"DELETE /api/v2/map-sequence"},
+	"MoveMapInSequence":        {"PUT /api/v2/map-sequence/move"},
+	"SetMapShuffleEnabled":     {"POST /api/v2/map-shuffle"},
+	"SetSectorLayout":          {"POST /api/v2/sector-layout"},
+	"SetDynamicWeatherEnabled": {"POST /api/v2/dynamic-weather"},
+	"SetTeamSwitchCooldown":    {"POST /api/v2/team-switch-cooldown"},
+	"SetMaxQueuedPlayers":      {"POST /api/v2/max-queued-players"},
+	"SetIdleKickDuration":      {"POST /api/v2/idle-kick-duration"},
+	"SetHighPingThreshold":     {"POST /api/v2/high-ping-threshold"},
+	"SetAutoBalanceEnabled":    {"POST /api/v2/auto-balance/enabled"},
+	"SetAutoBalanceThreshold":  {"POST /api/v2/auto-balance/threshold"},
+	"SetVoteKickEnabled":       {"POST /api/v2/vote-kick/enabled"},
+	"SetVoteKickThreshold":     {"POST /api/v2/vote-kick/threshold"},
+	"ResetVoteKickThreshold":   {"POST /api/v2/vote-kick/reset"},
+	"SetMatchTimer":            {"POST /api/v2/match-timer"},
+	"RemoveMatchTimer":         {"DELETE /api/v2/match-timer"},
+	"SetWarmupTimer":           {"POST /api/v2/warmup-timer"},
+	"RemoveWarmupTimer":        {"DELETE /api/v2/warmup-timer"},
+	"AddBannedWords":           {"POST /api/v2/profanities"},
+	"RemoveBannedWords":        {"DELETE /api/v2/profanities"},
+}
+
+// requiredRole returns the minimum role for a matched route
+func requiredRole(method, fullPath string) auth.Role {
+	if !strings.HasPrefix(fullPath, "/api/") {
+		// Frontend assets and the version endpoint
+		return auth.RoleViewer
+	}
+	if role, ok := routePermissions[method+" "+fullPath]; ok {
+		return role
+	}
+	return auth.RoleOwner
+}
+
+// Authorize authenticates callers and enforces routePermissions. Requests with an
+// "Authorization: Bearer" API key are resolved through keys; all others use HTTP Basic
+// auth against users. A nil users store leaves non-key requests unrestricted.
+func Authorize(users *auth.UserStore, keys *auth.KeyStore, skipPaths []string) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := skip[c.Request.URL.Path]; ok {
+			c.Next()
+			return
+		}
+
+		var user auth.User
+		if token, ok := bearerToken(c); ok {
+			if keys == nil {
+				unauthorized(c)
+				return
+			}
+			key, err := keys.Authenticate(token)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			user = auth.User{Username: "apikey:" + key.Name, Role: key.Role}
+			c.Set(apiKeyContextKey, key)
+		} else {
+			if users == nil {
+				c.Next()
+				return
+			}
+			username, password, ok := c.Request.BasicAuth()
+			if !ok {
+				unauthorized(c)
+				return
+			}
+			var err error
+			if user, err = users.Authenticate(username, password); err != nil {
+				unauthorized(c)
+				return
+			}
+		}
+		c.Set(userContextKey, user)
+
+		required := requiredRole(c.Request.Method, c.FullPath())
+		if !user.Role.Allows(required) {
+			slog.Warn("Permission denied",
+				"user", user.Username,
+				"role", user.Role.String(),
+				"required", required.String(),
+				"method", c.Request.Method,
+				"path", c.FullPath(),
+			)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":    "insufficient permissions",
+				"required": required.String(),
+				"role":     user.Role.String(),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Basic realm="hllrcon", charset="UTF-8"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+}
+
+// currentUser returns the authenticated app user, if access control is enabled
+func currentUser(c *gin.Context) (auth.User, bool) {
+	v, ok := c.Get(userContextKey)
+	if !ok {
+		return auth.User{}, false
+	}
+	user, ok := v.(auth.User)
+	return user, ok
+}
+
+// currentAPIKey returns the API key the request authenticated with, if any
+func currentAPIKey(c *gin.Context) (auth.APIKey, bool) {
+	v, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return auth.APIKey{}, false
+	}
+	key, ok := v.(auth.APIKey)
+	return key, ok
+}
+
+// requireKeyProfile rejects an API key acting on a profile other than its own
+func requireKeyProfile(c *gin.Context, name string) bool {
+	if key, ok := currentAPIKey(c); ok && key.Profile != name {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API key is limited to profile " + key.Profile})
+		return false
+	}
+	return true
+}
+
+// checkRoutePermissions logs /api routes that have no entry in routePermissions
+func checkRoutePermissions(router *gin.Engine) {
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+		if _, ok := routePermissions[route.Method+" "+route.Path]; !ok {
+			slog.Warn("Route has no permission entry; only owners can use it", "method", route.Method, "path", route.Path)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Sledro/hllrcon/auth"
+)
+
+func TestCommandRoutesMatchRoutes(t *testing.T) {
+	for command, routes := range commandRoutes {
+		for _, route := range routes {
+			if _, ok := routePermissions[route]; !ok {
+				t.Errorf("%s: %s has no routePermissions entry", command, route)
+			}
+			if doc := routeDocs[route]; !slices.Contains(doc.commands(), command) {
+				t.Errorf("%s: %s is documented as running %q", command, route, doc.commands())
+			}
+		}
+	}
+	for route, doc := range routeDocs {
+		for _, command := range doc.commands() {
+			if !slices.Contains(commandRoutes[command], route) {
+				t.Errorf("%s runs %s but is missing from commandRoutes", route, command)
+			}
+		}
+	}
+}
+
+func TestCommandRole(t *testing.T) {
+	tests := []struct {
+		command string
+		want    auth.Role
+	}{
+		{"KickPlayer", auth.RoleAdmin},
+		{"AddAdmin", auth.RoleOwner},
+		{"SomeNewCommand", auth.RoleAdmin},
+	}
+	for _, tt := range tests {
+		if got := commandRole(tt.command); got != tt.want {
+			t.Errorf("commandRole(%s) = %s, want %s", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestRouteRoleOfBanLists(t *testing.T) {
+	for _, command := range []string{"GetTemporaryBans", "GetPermanentBans"} {
+		if role, ok := routeRole(command); !ok || role != auth.RoleViewer {
+			t.Errorf("routeRole(%s) = %s, %v; want viewer like GET /bans", command, role, ok)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Sledro/hllrcon/profile"
+	"github.com/gin-gonic/gin"
+)
+
+type profileRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Host        string `json:"host" binding:"required"`
+	Port        int    `json:"port" binding:"required,min=1,max=65535"`
+	Password    string `json:"password"`
+}
+
+// requireProfiles reports whether profile storage is configured
+func (a *API) requireProfiles(c *gin.Context) bool {
+	if a.profiles == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Server profiles are not enabled (set profiles.encryption_key)"})
+		return false
+	}
+	return true
+}
+
+// profileError maps store errors to HTTP responses
+func profileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, profile.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, profile.ErrExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		slog.Error("Profile store error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// ListProfiles returns all saved server profiles
+func (a *API) ListProfiles(c *gin.Context) {
+	if !a.requireProfiles(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"profiles": a.profiles.List()})
+}
+
+// GetProfile returns a single saved server profile
+func (a *API) GetProfile(c *gin.Context) {
+	if !a.requireProfiles(c) {
+		return
+	}
+	p, err := a.profiles.Get(c.Param("name"))
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// CreateProfile saves a new server profile
+func (a *API) CreateProfile(c *gin.Context) {
+	if !a.requireProfiles(c) {
+		return
+	}
+
+	var req profileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := profile.ValidateName(req.Name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password is required"})
+		return
+	}
+
+	p, err := a.profiles.Create(profile.Profile{
+		Name:        req.Name,
+		Description: req.Description,
+		Host:        req.Host,
+		Port:        req.Port,
+	}, req.Password)
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+
+	slog.Info("Profile created", "profile", p.Name, "client_ip", c.ClientIP())
+	c.JSON(http.StatusCreated, p)
+}
+
+// UpdateProfile replaces a saved server profile; omit password to keep the stored one
+func (a *API) UpdateProfile(c *gin.Context) {
+	if !a.requireProfiles(c) {
+		return
+	}
+
+	var req profileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	p, err := a.profiles.Update(c.Param("name"), profile.Profile{
+		Description: req.Description,
+		Host:        req.Host,
+		Port:        req.Port,
+	}, req.Password)
+	if err != nil {
+		profileError(c, err)
+		return
+	}
+
+	slog.Info("Profile updated", "profile", p.Name, "client_ip", c.ClientIP())
+	c.JSON(http.StatusOK, p)
+}
+
+// DeleteProfile removes a saved server profile
+func (a *API) DeleteProfile(c *gin.Context) {
+	if !a.requireProfiles(c) {
+		return
+	}
+
+	name := c.Param("name")
+	if err := a.profiles.Delete(name); err != nil {
+		profileError(c, err)
+		return
+	}
+
+	slog.Info("Profile deleted", "profile", name, "client_ip", c.ClientIP())
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
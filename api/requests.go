@@ -0,0 +1,230 @@
+package api
+
+import "encoding/json"
+
+// connectRequest connects with explicit credentials or a saved profile
+type connectRequest struct {
+	Profile  string `json:"profile"`
+	Host     string `json:"host" binding:"required_without=Profile"`
+	Port     int    `json:"port" binding:"required_without=Profile"`
+	Password string `json:"password" binding:"required_without=Profile"`
+}
+
+// messageRequest carries a broadcast, welcome or private message
+type messageRequest struct {
+	Message string `json:"message" binding:"required"`
+}
+
+// playerReasonRequest targets a player with an optional reason
+type playerReasonRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	Reason   string `json:"reason"`
+}
+
+// mapNameRequest names a map
+type mapNameRequest struct {
+	MapName string `json:"map_name" binding:"required"`
+}
+
+// addVIPRequest adds a VIP with an optional comment
+type addVIPRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	Comment  string `json:"comment"`
+}
+
+// playerIDRequest targets a single player
+type playerIDRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+}
+
+// tempBanRequest temporarily bans a player; duration is in hours
+type tempBanRequest struct {
+	PlayerID  string `json:"player_id" binding:"required"`
+	Duration  int    `json:"duration" binding:"required"` // hours
+	Reason    string `json:"reason"`
+	AdminName string `json:"admin_name"`
+}
+
+// permaBanRequest permanently bans a player
+type permaBanRequest struct {
+	PlayerID  string `json:"player_id" binding:"required"`
+	Reason    string `json:"reason"`
+	AdminName string `json:"admin_name"`
+}
+
+// addAdminRequest adds a player to an admin group
+type addAdminRequest struct {
+	PlayerID   string `json:"player_id" binding:"required"`
+	AdminGroup string `json:"admin_group" binding:"required"`
+	Comment    string `json:"comment"`
+}
+
+// forceTeamSwitchRequest moves a player to the other team; force_mode 0 waits for death, 1 is immediate
+type forceTeamSwitchRequest struct {
+	PlayerID  string `json:"player_id" binding:"required"`
+	ForceMode *int   `json:"force_mode" binding:"required"` // 0=on death, 1=immediately
+}
+
+// disbandSquadRequest disbands a squad by team and squad index
+type disbandSquadRequest struct {
+	TeamIndex  *int   `json:"team_index" binding:"required"`
+	SquadIndex *int   `json:"squad_index" binding:"required"`
+	Reason     string `json:"reason"`
+}
+
+// mapIndexRequest inserts a map at an index
+type mapIndexRequest struct {
+	MapName string `json:"map_name" binding:"required"`
+	Index   int    `json:"index"`
+}
+
+// indexRequest addresses an entry by index
+type indexRequest struct {
+	Index *int `json:"index" binding:"required"`
+}
+
+// bannedWordsRequest carries a comma-separated list of words
+type bannedWordsRequest struct {
+	BannedWords string `json:"banned_words" binding:"required"`
+}
+
+// sectorLayoutRequest sets the five sectors of the current map
+type sectorLayoutRequest struct {
+	Sector1 string `json:"sector_1" binding:"required"`
+	Sector2 string `json:"sector_2" binding:"required"`
+	Sector3 string `json:"sector_3" binding:"required"`
+	Sector4 string `json:"sector_4" binding:"required"`
+	Sector5 string `json:"sector_5" binding:"required"`
+}
+
+// moveMapRequest moves a map sequence entry
+type moveMapRequest struct {
+	CurrentIndex *int `json:"current_index" binding:"required"`
+	NewIndex     *int `json:"new_index" binding:"required"`
+}
+
+// enableRequest toggles a server setting
+type enableRequest struct {
+	Enable bool `json:"enable" binding:"required"`
+}
+
+// teamSwitchCooldownRequest sets the team switch cooldown in minutes
+type teamSwitchCooldownRequest struct {
+	TeamSwitchTimer int `json:"team_switch_timer" binding:"required"`
+}
+
+// maxQueuedPlayersRequest sets the queue length
+type maxQueuedPlayersRequest struct {
+	MaxQueuedPlayers int `json:"max_queued_players" binding:"required"`
+}
+
+// idleKickDurationRequest sets the idle kick timeout in minutes
+type idleKickDurationRequest struct {
+	IdleTimeoutMinutes int `json:"idle_timeout_minutes" binding:"required"`
+}
+
+// highPingThresholdRequest sets the high ping kick threshold in milliseconds
+type highPingThresholdRequest struct {
+	HighPingThresholdMs int `json:"high_ping_threshold_ms" binding:"required"`
+}
+
+// vipSlotCountRequest sets the number of reserved VIP slots
+type vipSlotCountRequest struct {
+	VipSlotCount int `json:"vip_slot_count" binding:"required"`
+}
+
+// voteKickThresholdRequest sets vote kick thresholds as "players,votes" pairs
+type voteKickThresholdRequest struct {
+	ThresholdValue string `json:"threshold_value" binding:"required"`
+}
+
+// autoBalanceThresholdRequest sets the allowed team size difference
+type autoBalanceThresholdRequest struct {
+	AutoBalanceThreshold int `json:"auto_balance_threshold" binding:"required"`
+}
+
+// matchTimerRequest sets the match length in minutes for a game mode
+type matchTimerRequest struct {
+	GameMode    string `json:"game_mode" binding:"required"`
+	MatchLength int    `json:"match_length" binding:"required"`
+}
+
+// gameModeRequest names a game mode
+type gameModeRequest struct {
+	GameMode string `json:"game_mode" binding:"required"`
+}
+
+// warmupTimerRequest sets the warmup length in minutes for a game mode
+type warmupTimerRequest struct {
+	GameMode     string `json:"game_mode" binding:"required"`
+	WarmupLength int    `json:"warmup_length" binding:"required"`
+}
+
+// dynamicWeatherRequest toggles dynamic weather on a map
+type dynamicWeatherRequest struct {
+	MapId  string `json:"map_id" binding:"required"`
+	Enable bool   `json:"enable" binding:"required"`
+}
+
+// createAPIKeyRequest issues an API key bound to a profile
+type createAPIKeyRequest struct {
+	Name          string `json:"name" binding:"required,max=64"`
+	Profile       string `json:"profile" binding:"required"`
+	Role          string `json:"role" binding:"required"`
+	ExpiresInDays int    `json:"expires_in_days" binding:"min=0"`
+}
+
+// commandRequest names any server command and its content body
+type commandRequest struct {
+	Name string          `json:"name" binding:"required"`
+	Body json.RawMessage `json:"body"`
+}
+
+// batchRequest lists commands to run in order on one connection
+type batchRequest struct {
+	Operations  []batchOperation `json:"operations" binding:"required,min=1,max=100,dive"`
+	StopOnError bool             `json:"stop_on_error"`
+}
+
+// batchOperation is one command of a batch, with a body as accepted by /command
+type batchOperation struct {
+	Name string          `json:"name" binding:"required"`
+	Body json.RawMessage `json:"body"`
+}
+
+// watchlistRequest adds a player to the watchlist
+type watchlistRequest struct {
+	PlayerID string `json:"player_id" binding:"required"`
+	Name     string `json:"name"`
+	Note     string `json:"note" binding:"required,max=500"`
+}
+
+// watchlistUpdateRequest changes the note of a watched player
+type watchlistUpdateRequest struct {
+	Name string `json:"name"`
+	Note string `json:"note" binding:"required,max=500"`
+}
+
+// scheduledBroadcastRequest defines a message sent to a saved server on a schedule
+type scheduledBroadcastRequest struct {
+	Name            string `json:"name" binding:"required,max=100"`
+	Profile         string `json:"profile" binding:"required"`
+	Action          string `json:"action" binding:"required,oneof=broadcast welcome_message"`
+	Message         string `json:"message" binding:"required,max=1000"`
+	Cron            string `json:"cron"`
+	Timezone        string `json:"timezone"`
+	IntervalSeconds int    `json:"interval_seconds" binding:"min=0"`
+	Enabled         *bool  `json:"enabled"` // Defaults to true
+}
+
+// scheduledTaskRequest defines commands run on a saved server on a cron schedule
+type scheduledTaskRequest struct {
+	Name       string           `json:"name" binding:"required,max=100"`
+	Profile    string           `json:"profile" binding:"required"`
+	Steps      []batchOperation `json:"steps" binding:"required,min=1,max=20,dive"`
+	Cron       string           `json:"cron" binding:"required"`
+	Timezone   string           `json:"timezone"`
+	MissedRuns string           `json:"missed_runs" binding:"omitempty,oneof=skip run_once"` // Defaults to skip
+	DryRun     bool             `json:"dry_run"`
+	Enabled    *bool            `json:"enabled"` // Defaults to true
+}
@@ -1,6 +1,11 @@
 package api
 
-import "github.com/gin-gonic/gin"
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
 
 // SetupRoutes configures all API routes
 func (a *API) SetupRoutes(router *gin.Engine) {
@@ -17,25 +22,80 @@ func (a *API) SetupRoutes(router *gin.Engine) {
 
 	api := router.Group("/api/v2")
 	{
+		api.GET("/openapi.json", a.OpenAPI)
+
 		// Connection management
 		api.POST("/connect", a.Connect)
 		api.POST("/disconnect", a.Disconnect)
 		api.GET("/connection/status", a.ConnectionStatus)
+		api.GET("/connection/pool", a.PoolStats)
+
+		// App users and roles
+		api.GET("/auth/me", a.CurrentUser)
+		api.GET("/users", a.ListUsers)
+		api.POST("/users", a.CreateUser)
+		api.PUT("/users/:username", a.UpdateUser)
+		api.DELETE("/users/:username", a.DeleteUser)
+
+		// API keys for machine clients
+		api.GET("/api-keys", a.ListAPIKeys)
+		api.POST("/api-keys", a.CreateAPIKey)
+		api.DELETE("/api-keys/:id", a.RevokeAPIKey)
+
+		// Audit log
+		api.GET("/audit", a.GetAuditLog)
+		api.GET("/audit/verify", a.VerifyAuditLog)
+
+		// Saved server profiles
+		api.GET("/profiles", a.ListProfiles)
+		api.POST("/profiles", a.CreateProfile)
+		api.GET("/profiles/:name", a.GetProfile)
+		api.PUT("/profiles/:name", a.UpdateProfile)
+		api.DELETE("/profiles/:name", a.DeleteProfile)
+
 		// Server info
 		api.GET("/server", a.GetServerInfo)
 		api.GET("/map-rotation", a.GetMapRotation)
 		api.GET("/map-sequence", a.GetMapSequence)
 		api.GET("/logs", a.GetAdminLog)
+		api.GET("/logs/stream", a.StreamAdminLog)
+		api.GET("/logs/ws", a.StreamAdminLogWS)
 		api.GET("/profanities", a.GetProfanities)
 		api.GET("/commands", a.GetDisplayableCommands)
 		api.GET("/command-reference", a.GetClientReferenceData)
 		api.GET("/changelist", a.GetServerChangelist)
 		api.GET("/maps", a.GetMapList)
+		api.POST("/command", a.ExecuteRawCommand)
+		api.POST("/batch", a.ExecuteBatch)
 
 		// Players
 		api.GET("/players", a.GetPlayers)
+		api.GET("/players/search", a.SearchPlayers)
 		api.GET("/players/:id", a.GetPlayer)
 		api.POST("/players/:id/message", a.MessagePlayer)
+		api.GET("/player-history/:id", a.GetPlayerHistory)
+
+		// Watchlist
+		api.GET("/watchlist", a.ListWatchlist)
+		api.POST("/watchlist", a.AddToWatchlist)
+		api.GET("/watchlist/alerts/stream", a.StreamWatchlistAlerts)
+		api.GET("/watchlist/:id", a.GetWatchlistEntry)
+		api.PUT("/watchlist/:id", a.UpdateWatchlistEntry)
+		api.DELETE("/watchlist/:id", a.RemoveFromWatchlist)
+
+		// Scheduled broadcasts
+		api.GET("/scheduled-broadcasts", a.ListScheduledBroadcasts)
+		api.POST("/scheduled-broadcasts", a.CreateScheduledBroadcast)
+		api.GET("/scheduled-broadcasts/:id", a.GetScheduledBroadcast)
+		api.PUT("/scheduled-broadcasts/:id", a.UpdateScheduledBroadcast)
+		api.DELETE("/scheduled-broadcasts/:id", a.DeleteScheduledBroadcast)
+		api.GET("/scheduled-broadcasts/:id/runs", a.GetScheduledBroadcastRuns)
+		api.GET("/scheduled-tasks", a.ListScheduledTasks)
+		api.POST("/scheduled-tasks", a.CreateScheduledTask)
+		api.GET("/scheduled-tasks/:id", a.GetScheduledTask)
+		api.PUT("/scheduled-tasks/:id", a.UpdateScheduledTask)
+		api.DELETE("/scheduled-tasks/:id", a.DeleteScheduledTask)
+		api.GET("/scheduled-tasks/:id/runs", a.GetScheduledTaskRuns)
 
 		// VIPs
 		api.GET("/vips", a.GetVIPs)
@@ -110,6 +170,18 @@ func (a *API) SetupRoutes(router *gin.Engine) {
 		api.DELETE("/profanities", a.RemoveProfanities)
 	}
 
+	if a.users != nil || a.keys != nil {
+		checkRoutePermissions(router)
+	}
+
+	// Generate the OpenAPI document from the registered routes
+	checkOpenAPICoverage(router.Routes())
+	spec, err := json.Marshal(buildOpenAPI(router.Routes(), a.version))
+	if err != nil {
+		slog.Error("Failed to generate OpenAPI document", "error", err)
+	}
+	a.openapi = spec
+
 	// Catch-all error handler for unmatched routes
 	router.NoRoute(func(c *gin.Context) {
 		path := c.Request.URL.Path
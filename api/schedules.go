@@ -0,0 +1,360 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/Sledro/hllrcon/profile"
+	"github.com/Sledro/hllrcon/schedule"
+	"github.com/gin-gonic/gin"
+)
+
+// requireBroadcasts reports whether scheduled broadcasts are configured
+func (a *API) requireBroadcasts(c *gin.Context) bool {
+	if a.broadcasts == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Scheduled broadcasts are not enabled (requires server profiles)"})
+		return false
+	}
+	return true
+}
+
+// broadcastError maps store errors to HTTP responses
+func broadcastError(c *gin.Context, err error) {
+	if errors.Is(err, schedule.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	slog.Error("Scheduled broadcast store error", "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// bindScheduledBroadcast validates a request into a broadcast definition
+func (a *API) bindScheduledBroadcast(c *gin.Context) (schedule.Broadcast, bool) {
+	var req scheduledBroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return schedule.Broadcast{}, false
+	}
+
+	b := schedule.Broadcast{
+		Name:            req.Name,
+		Profile:         req.Profile,
+		Action:          req.Action,
+		Message:         req.Message,
+		Cron:            req.Cron,
+		Timezone:        req.Timezone,
+		IntervalSeconds: req.IntervalSeconds,
+		Enabled:         req.Enabled == nil || *req.Enabled,
+	}
+	if err := b.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return schedule.Broadcast{}, false
+	}
+	if !requireKeyProfile(c, b.Profile) {
+		return schedule.Broadcast{}, false
+	}
+	_, err := a.profiles.Get(b.Profile)
+	if errors.Is(err, profile.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "profile not found"})
+		return schedule.Broadcast{}, false
+	}
+	if err != nil {
+		profileError(c, err)
+		return schedule.Broadcast{}, false
+	}
+	return b, true
+}
+
+// ListScheduledBroadcasts returns every scheduled broadcast with its next run
+func (a *API) ListScheduledBroadcasts(c *gin.Context) {
+	if !a.requireBroadcasts(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"broadcasts": a.broadcasts.List(), "variables": schedule.TemplateVars})
+}
+
+// GetScheduledBroadcast returns a single scheduled broadcast
+func (a *API) GetScheduledBroadcast(c *gin.Context) {
+	if !a.requireBroadcasts(c) {
+		return
+	}
+	b, err := a.broadcasts.Get(c.Param("id"))
+	if err != nil {
+		broadcastError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+// CreateScheduledBroadcast adds a scheduled broadcast; the author is the calling user
+func (a *API) CreateScheduledBroadcast(c *gin.Context) {
+	if !a.requireBroadcasts(c) {
+		return
+	}
+	b, ok := a.bindScheduledBroadcast(c)
+	if !ok {
+		return
+	}
+	if user, ok := currentUser(c); ok {
+		b.Author = user.Username
+	}
+
+	b, err := a.broadcasts.Create(b)
+	if err != nil {
+		broadcastError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, b)
+}
+
+// UpdateScheduledBroadcast replaces the definition of a scheduled broadcast
+func (a *API) UpdateScheduledBroadcast(c *gin.Context) {
+	if !a.requireBroadcasts(c) {
+		return
+	}
+	b, ok := a.bindScheduledBroadcast(c)
+	if !ok {
+		return
+	}
+
+	b, err := a.broadcasts.Update(c.Param("id"), b)
+	if err != nil {
+		broadcastError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+// DeleteScheduledBroadcast removes a scheduled broadcast and its run history
+func (a *API) DeleteScheduledBroadcast(c *gin.Context) {
+	if !a.requireBroadcasts(c) {
+		return
+	}
+	if err := a.broadcasts.Delete(c.Param("id")); err != nil {
+		broadcastError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// GetScheduledBroadcastRuns returns the recent runs of a scheduled broadcast, newest first
+func (a *API) GetScheduledBroadcastRuns(c *gin.Context) {
+	if !a.requireBroadcasts(c) {
+		return
+	}
+	runs, err := a.broadcasts.Runs(c.Param("id"))
+	if err != nil {
+		broadcastError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+// requireTasks reports whether scheduled tasks are configured
+func (a *API) requireTasks(c *gin.Context) bool {
+	if a.tasks == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Scheduled tasks are not enabled (requires server profiles)"})
+		return false
+	}
+	return true
+}
+
+// taskError maps store errors to HTTP responses
+func taskError(c *gin.Context, err error) {
+	if errors.Is(err, schedule.ErrTaskNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	slog.Error("Scheduled task store error", "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// bindScheduledTask validates a request into a task definition. Like a batch, every step
+// is checked against the caller's role and the command's reference data on the profile's
+// server, so saving a task needs the server to be reachable.
+func (a *API) bindScheduledTask(c *gin.Context) (schedule.Task, bool) {
+	var req scheduledTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return schedule.Task{}, false
+	}
+
+	t := schedule.Task{
+		Name:       req.Name,
+		Profile:    req.Profile,
+		Steps:      make([]schedule.Step, len(req.Steps)),
+		Cron:       req.Cron,
+		Timezone:   req.Timezone,
+		MissedRuns: req.MissedRuns,
+		DryRun:     req.DryRun,
+		Enabled:    req.Enabled == nil || *req.Enabled,
+	}
+	if t.MissedRuns == "" {
+		t.MissedRuns = schedule.MissedSkip
+	}
+	for i, op := range req.Steps {
+		t.Steps[i].Name = op.Name
+	}
+	if err := t.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return schedule.Task{}, false
+	}
+	if !requireKeyProfile(c, t.Profile) {
+		return schedule.Task{}, false
+	}
+
+	host, port, password, err := a.profiles.Credentials(t.Profile)
+	if errors.Is(err, profile.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "profile not found"})
+		return schedule.Task{}, false
+	}
+	if err != nil {
+		profileError(c, err)
+		return schedule.Task{}, false
+	}
+	client, release, err := a.sessionManager.Pool().Acquire(c.Request.Context(), host, port, password)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": "Failed to connect: " + err.Error()})
+		return schedule.Task{}, false
+	}
+	defer release()
+
+	user, hasUser := currentUser(c)
+	catalog := a.commandCatalogs.get(fmt.Sprintf("%s:%d", host, port))
+	for i, op := range req.Steps {
+		if handshakeCommands[op.Name] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("steps[%d]: %s is handled by /connect", i, op.Name)})
+			return schedule.Task{}, false
+		}
+
+		info, ref, err := catalog.lookup(c.Request.Context(), client, op.Name)
+		if err == errUnknownCommand {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("steps[%d]: unknown command %q: %s", i, op.Name, err)})
+			return schedule.Task{}, false
+		}
+		if err != nil {
+			a.commandError(c, op.Name, err)
+			return schedule.Task{}, false
+		}
+
+		if hasUser {
+			required, ok := routeRole(info.ID)
+			if !ok {
+				required = commandRole(info.ID)
+			}
+			if !user.Role.Allows(required) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error":    fmt.Sprintf("steps[%d]: insufficient permissions for %s", i, info.ID),
+					"required": required.String(),
+					"role":     user.Role.String(),
+				})
+				return schedule.Task{}, false
+			}
+		}
+
+		content, err := ref.ParseContent(op.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("steps[%d]: %s", i, err)})
+			return schedule.Task{}, false
+		}
+		body, err := json.Marshal(content)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("steps[%d]: %s", i, err)})
+			return schedule.Task{}, false
+		}
+		t.Steps[i] = schedule.Step{Name: info.ID, Body: body}
+	}
+	return t, true
+}
+
+// ListScheduledTasks returns every scheduled task with its next run
+func (a *API) ListScheduledTasks(c *gin.Context) {
+	if !a.requireTasks(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tasks": a.tasks.List()})
+}
+
+// GetScheduledTask returns a single scheduled task
+func (a *API) GetScheduledTask(c *gin.Context) {
+	if !a.requireTasks(c) {
+		return
+	}
+	t, err := a.tasks.Get(c.Param("id"))
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// CreateScheduledTask adds a scheduled task; the author is the calling user
+func (a *API) CreateScheduledTask(c *gin.Context) {
+	if !a.requireTasks(c) {
+		return
+	}
+	t, ok := a.bindScheduledTask(c)
+	if !ok {
+		return
+	}
+	if user, ok := currentUser(c); ok {
+		t.Author = user.Username
+	}
+
+	t, err := a.tasks.Create(t)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// UpdateScheduledTask replaces the definition of a scheduled task
+func (a *API) UpdateScheduledTask(c *gin.Context) {
+	if !a.requireTasks(c) {
+		return
+	}
+	if _, err := a.tasks.Get(c.Param("id")); err != nil {
+		taskError(c, err)
+		return
+	}
+	t, ok := a.bindScheduledTask(c)
+	if !ok {
+		return
+	}
+
+	t, err := a.tasks.Update(c.Param("id"), t)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// DeleteScheduledTask removes a scheduled task and its run history
+func (a *API) DeleteScheduledTask(c *gin.Context) {
+	if !a.requireTasks(c) {
+		return
+	}
+	if err := a.tasks.Delete(c.Param("id")); err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// GetScheduledTaskRuns returns the recent runs of a scheduled task, newest first
+func (a *API) GetScheduledTaskRuns(c *gin.Context) {
+	if !a.requireTasks(c) {
+		return
+	}
+	runs, err := a.tasks.Runs(c.Param("id"))
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/Sledro/hllrcon/auth"
+	"github.com/Sledro/hllrcon/profile"
+	"github.com/gin-gonic/gin"
+)
+
+// newProfilesAPI serves the profiles main and other
+func newProfilesAPI(t *testing.T) *API {
+	t.Helper()
+	cipher, err := profile.NewCipher("test encryption key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := profile.Open(filepath.Join(t.TempDir(), "profiles.json"), cipher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"main", "other"} {
+		if _, err := profiles.Create(profile.Profile{Name: name, Host: "127.0.0.1", Port: 7779}, "secret"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &API{profiles: profiles}
+}
+
+// scheduleContext is a request with body, authenticated with key unless it has no profile
+func scheduleContext(body string, key auth.APIKey) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if key.Profile != "" {
+		c.Set(apiKeyContextKey, key)
+	}
+	return c, w
+}
+
+func TestBindScheduledBroadcastProfile(t *testing.T) {
+	a := newProfilesAPI(t)
+	mainKey := auth.APIKey{Name: "bot", Profile: "main", Role: auth.RoleAdmin}
+	tests := []struct {
+		name    string
+		profile string
+		key     auth.APIKey
+		want    int
+	}{
+		{"key on its own profile", "main", mainKey, http.StatusOK},
+		{"key on another profile", "other", mainKey, http.StatusForbidden},
+		{"key on a missing profile", "missing", mainKey, http.StatusForbidden},
+		{"user on any profile", "other", auth.APIKey{}, http.StatusOK},
+		{"user on a missing profile", "missing", auth.APIKey{}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := scheduleContext(`{"name":"rules","profile":"`+tt.profile+`","action":"broadcast","message":"hi","interval_seconds":600}`, tt.key)
+			_, ok := a.bindScheduledBroadcast(c)
+			if got := w.Code; ok != (tt.want == http.StatusOK) || (!ok && got != tt.want) {
+				t.Errorf("bind = %v with status %d, want %d: %s", ok, got, tt.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestBindScheduledTaskProfile(t *testing.T) {
+	a := newProfilesAPI(t)
+	tests := []struct {
+		name    string
+		profile string
+		key     auth.APIKey
+		want    int
+	}{
+		{"key on another profile", "other", auth.APIKey{Name: "bot", Profile: "main", Role: auth.RoleAdmin}, http.StatusForbidden},
+		{"user on a missing profile", "missing", auth.APIKey{}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := scheduleContext(`{"name":"restart","profile":"`+tt.profile+`","cron":"0 4 * * *","steps":[{"name":"GetServerConfig"}]}`, tt.key)
+			if _, ok := a.bindScheduledTask(c); ok || w.Code != tt.want {
+				t.Errorf("bind = %v with status %d, want %d: %s", ok, w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Sledro/hllrcon/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type userRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role" binding:"required"`
+}
+
+// requireUsers reports whether app users are configured
+func (a *API) requireUsers(c *gin.Context) bool {
+	if a.users == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "App users are not enabled (set security.app_password)"})
+		return false
+	}
+	return true
+}
+
+// userError maps user store errors to HTTP responses
+func userError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, auth.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, auth.ErrUserExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, auth.ErrBuiltinUser):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		slog.Error("User store error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// CurrentUser returns the authenticated app user and their role
+func (a *API) CurrentUser(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusOK, gin.H{"access_control": false})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"access_control": true,
+		"username":       user.Username,
+		"role":           user.Role,
+	})
+}
+
+// ListUsers returns all app users
+func (a *API) ListUsers(c *gin.Context) {
+	if !a.requireUsers(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"users": a.users.List()})
+}
+
+// CreateUser adds an app user
+func (a *API) CreateUser(c *gin.Context) {
+	if !a.requireUsers(c) {
+		return
+	}
+
+	var req userRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := auth.ValidateUsername(req.Username); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	role, err := auth.ParseRole(req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Password) < 8 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password must be at least 8 characters"})
+		return
+	}
+
+	user, err := a.users.Create(req.Username, req.Password, role)
+	if err != nil {
+		userError(c, err)
+		return
+	}
+
+	slog.Info("User created", "username", user.Username, "role", user.Role.String(), "client_ip", c.ClientIP())
+	c.JSON(http.StatusCreated, user)
+}
+
+// UpdateUser changes an app user's role and optionally their password
+func (a *API) UpdateUser(c *gin.Context) {
+	if !a.requireUsers(c) {
+		return
+	}
+
+	var req userRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	role, err := auth.ParseRole(req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Password != "" && len(req.Password) < 8 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password must be at least 8 characters"})
+		return
+	}
+
+	user, err := a.users.Update(c.Param("username"), req.Password, role)
+	if err != nil {
+		userError(c, err)
+		return
+	}
+
+	slog.Info("User updated", "username", user.Username, "role", user.Role.String(), "client_ip", c.ClientIP())
+	c.JSON(http.StatusOK, user)
+}
+
+// DeleteUser removes an app user
+func (a *API) DeleteUser(c *gin.Context) {
+	if !a.requireUsers(c) {
+		return
+	}
+
+	username := c.Param("username")
+	if err := a.users.Delete(username); err != nil {
+		userError(c, err)
+		return
+	}
+
+	slog.Info("User deleted", "username", username, "client_ip", c.ClientIP())
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
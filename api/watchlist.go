@@ -0,0 +1,145 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Sledro/hllrcon/watchlist"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// requireWatchlist reports whether the watchlist is configured
+func (a *API) requireWatchlist(c *gin.Context) bool {
+	if a.watchlist == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Watchlist is not enabled"})
+		return false
+	}
+	return true
+}
+
+// watchlistError maps store errors to HTTP responses
+func watchlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, watchlist.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, watchlist.ErrExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		slog.Error("Watchlist store error", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// ListWatchlist returns every watched player
+func (a *API) ListWatchlist(c *gin.Context) {
+	if !a.requireWatchlist(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": a.watchlist.List()})
+}
+
+// GetWatchlistEntry returns a single watched player
+func (a *API) GetWatchlistEntry(c *gin.Context) {
+	if !a.requireWatchlist(c) {
+		return
+	}
+	entry, err := a.watchlist.Get(c.Param("id"))
+	if err != nil {
+		watchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// AddToWatchlist flags a player; the author is the calling user or API key
+func (a *API) AddToWatchlist(c *gin.Context) {
+	if !a.requireWatchlist(c) {
+		return
+	}
+
+	var req watchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry := watchlist.Entry{PlayerID: req.PlayerID, Name: req.Name, Note: req.Note}
+	if user, ok := currentUser(c); ok {
+		entry.Author = user.Username
+	}
+
+	entry, err := a.watchlist.Create(entry)
+	if err != nil {
+		watchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// UpdateWatchlistEntry changes the note of a watched player
+func (a *API) UpdateWatchlistEntry(c *gin.Context) {
+	if !a.requireWatchlist(c) {
+		return
+	}
+
+	var req watchlistUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := a.watchlist.Update(c.Param("id"), req.Name, req.Note)
+	if err != nil {
+		watchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// RemoveFromWatchlist stops watching a player
+func (a *API) RemoveFromWatchlist(c *gin.Context) {
+	if !a.requireWatchlist(c) {
+		return
+	}
+	if err := a.watchlist.Delete(c.Param("id")); err != nil {
+		watchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// StreamWatchlistAlerts streams an "alert" Server-Sent Event whenever a watched player
+// joins any connected server
+func (a *API) StreamWatchlistAlerts(c *gin.Context) {
+	if !a.requireWatchlist(c) {
+		return
+	}
+	alerts, unsubscribe := a.watchlist.Subscribe()
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case alert := <-alerts:
+			c.Render(-1, sse.Event{Event: "alert", Data: alert})
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
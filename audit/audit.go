@@ -0,0 +1,345 @@
+// Package audit keeps an append-only, hash-chained record of state-changing RCON commands.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/internal/fsutil"
+)
+
+// genesisHash is the previous hash of the first entry
+const genesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// redacted replaces secret values in recorded bodies
+const redacted = "[REDACTED]"
+
+// Entry is one audited command
+type Entry struct {
+	Seq           uint64          `json:"seq"`
+	Time          time.Time       `json:"time"`
+	User          string          `json:"user,omitempty"`
+	ClientIP      string          `json:"client_ip,omitempty"`
+	SessionID     string          `json:"session_id,omitempty"`
+	Server        string          `json:"server"`
+	Profile       string          `json:"profile,omitempty"`
+	Command       string          `json:"command"`
+	Body          json.RawMessage `json:"body,omitempty"`
+	StatusCode    int             `json:"status_code"`
+	StatusMessage string          `json:"status_message,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	PrevHash      string          `json:"prev_hash"`
+	Hash          string          `json:"hash"`
+}
+
+// computeHash hashes the entry with its Hash field cleared
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// anchor is the last appended position, kept in a file beside the log so that
+// entries removed from the end are detected
+type anchor struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// Log appends entries to a JSONL file, chaining each to the hash of the previous one
+type Log struct {
+	path       string
+	anchorPath string
+
+	mu       sync.Mutex
+	file     *os.File
+	seq      uint64
+	lastHash string
+	anchor   anchor
+}
+
+// Open opens or creates the audit log at path and continues its chain from the last
+// entry. An entry cut short by a crash mid-write is dropped. Call Verify to check the
+// existing entries for tampering.
+func Open(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	if err := repairTrailingLine(path); err != nil {
+		return nil, err
+	}
+
+	l := &Log{path: path, anchorPath: path + ".anchor", lastHash: genesisHash}
+	data, err := os.ReadFile(l.anchorPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read audit anchor: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &l.anchor); err != nil {
+			return nil, fmt.Errorf("failed to parse audit anchor: %w", err)
+		}
+	}
+
+	err = l.Scan(func(e Entry) bool {
+		l.seq = e.Seq
+		l.lastHash = e.Hash
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file = file
+	return l, nil
+}
+
+// repairTrailingLine truncates an entry that was cut short, e.g. by a crash mid-write.
+// Append syncs before it returns, so such an entry was never acknowledged.
+func repairTrailingLine(path string) error {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Find the end of the last complete line
+	size := info.Size()
+	end := size
+	buf := make([]byte, 4096)
+	for end > 0 {
+		n := min(int64(len(buf)), end)
+		if _, err := file.ReadAt(buf[:n], end-n); err != nil {
+			return fmt.Errorf("failed to read audit log: %w", err)
+		}
+		if i := bytes.LastIndexByte(buf[:n], '\n'); i >= 0 {
+			end += int64(i) + 1 - n
+			break
+		}
+		end -= n
+	}
+	if end == size {
+		return nil
+	}
+
+	if err := file.Truncate(end); err != nil {
+		return fmt.Errorf("failed to repair audit log: %w", err)
+	}
+	slog.Warn("Dropped an incomplete entry from the end of the audit log", "path", path, "bytes", size-end)
+	return file.Sync()
+}
+
+// Append records an entry, filling in Seq, Time (if zero) and the hash chain
+func (l *Log) Append(e Entry) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	e.Seq = l.seq + 1
+	e.PrevHash = l.lastHash
+
+	hash, err := e.computeHash()
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to hash audit entry: %w", err)
+	}
+	e.Hash = hash
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return Entry{}, fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return Entry{}, fmt.Errorf("failed to sync audit log: %w", err)
+	}
+
+	l.seq = e.Seq
+	l.lastHash = e.Hash
+	l.anchor = anchor{Seq: e.Seq, Hash: e.Hash}
+	data, err := json.Marshal(l.anchor)
+	if err != nil {
+		return e, fmt.Errorf("failed to encode audit anchor: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(l.anchorPath, data); err != nil {
+		return e, fmt.Errorf("failed to write audit anchor: %w", err)
+	}
+	return e, nil
+}
+
+// Close closes the underlying file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Scan calls fn for each entry in order until fn returns false
+func (l *Log) Scan(fn func(Entry) bool) error {
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// A trailing line without a newline is an append still in progress
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read audit log: %w", err)
+		}
+
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("corrupt audit entry: %w", err)
+		}
+		if !fn(e) {
+			return nil
+		}
+	}
+}
+
+// VerifyResult reports the outcome of checking the hash chain
+type VerifyResult struct {
+	Valid    bool   `json:"valid"`
+	Entries  uint64 `json:"entries"`
+	LastHash string `json:"last_hash,omitempty"`
+	BrokenAt uint64 `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Verify walks the log and checks sequence numbers, the hash chain and that the log
+// still reaches the last anchored entry
+func (l *Log) Verify() VerifyResult {
+	l.mu.Lock()
+	anchored := l.anchor
+	l.mu.Unlock()
+
+	result := VerifyResult{Valid: true}
+	prev := genesisHash
+
+	err := l.Scan(func(e Entry) bool {
+		expectedSeq := result.Entries + 1
+		fail := func(reason string) bool {
+			result.Valid = false
+			result.BrokenAt = expectedSeq
+			result.Reason = reason
+			return false
+		}
+
+		if e.Seq != expectedSeq {
+			return fail(fmt.Sprintf("expected seq %d, found %d", expectedSeq, e.Seq))
+		}
+		if e.PrevHash != prev {
+			return fail("previous hash does not match")
+		}
+		hash, err := e.computeHash()
+		if err != nil || hash != e.Hash {
+			return fail("entry hash does not match its contents")
+		}
+		if e.Seq == anchored.Seq && e.Hash != anchored.Hash {
+			return fail("entry hash does not match the anchor")
+		}
+
+		prev = e.Hash
+		result.Entries = e.Seq
+		result.LastHash = e.Hash
+		return true
+	})
+	if err != nil {
+		result.Valid = false
+		result.BrokenAt = result.Entries + 1
+		result.Reason = err.Error()
+	}
+	if result.Valid && result.Entries < anchored.Seq {
+		result.Valid = false
+		result.BrokenAt = result.Entries + 1
+		result.Reason = fmt.Sprintf("log ends at entry %d but the anchor records entry %d; entries were removed from the end", result.Entries, anchored.Seq)
+	}
+	return result
+}
+
+// RedactBody encodes a command body for the audit log with secret values replaced
+func RedactBody(body any) json.RawMessage {
+	if body == nil {
+		return nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil
+	}
+
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return data
+	}
+	redacted, err := json.Marshal(redact(generic))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// redact walks decoded JSON and replaces values of password-like keys
+func redact(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if isSecretKey(k) {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redact(val)
+		}
+		return t
+	case []any:
+		for i, val := range t {
+			t[i] = redact(val)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func isSecretKey(key string) bool {
+	k := strings.ToLower(key)
+	return strings.Contains(k, "password") || strings.Contains(k, "token") || strings.Contains(k, "secret")
+}
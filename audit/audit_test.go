@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestLog appends n entries to a new log and closes it
+func writeTestLog(t *testing.T, n int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range n {
+		if _, err := l.Append(Entry{Server: "10.0.0.1:7779", Command: "ServerBroadcast"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func reopen(t *testing.T, path string) *Log {
+	t.Helper()
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func TestVerify(t *testing.T) {
+	path := writeTestLog(t, 3)
+	if result := reopen(t, path).Verify(); !result.Valid || result.Entries != 3 {
+		t.Fatalf("Verify = %+v", result)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := bytes.Replace(data, []byte("ServerBroadcast"), []byte("ServerBroadcasX"), 1)
+	if err := os.WriteFile(path, edited, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if result := reopen(t, path).Verify(); result.Valid || result.BrokenAt != 1 {
+		t.Errorf("edited entry: %+v", result)
+	}
+}
+
+func TestVerifyDetectsTruncation(t *testing.T) {
+	path := writeTestLog(t, 3)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	if err := os.WriteFile(path, bytes.Join(lines[:2], nil), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result := reopen(t, path).Verify()
+	if result.Valid || result.BrokenAt != 3 || !strings.Contains(result.Reason, "removed from the end") {
+		t.Errorf("truncated log: %+v", result)
+	}
+}
+
+func TestOpenDropsIncompleteEntry(t *testing.T) {
+	path := writeTestLog(t, 2)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	partial := append(append([]byte(nil), data...), []byte(`{"seq":3,"time":"2026-10`)...)
+	if err := os.WriteFile(path, partial, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	l := reopen(t, path)
+	if result := l.Verify(); !result.Valid || result.Entries != 2 {
+		t.Fatalf("Verify after repair = %+v", result)
+	}
+	e, err := l.Append(Entry{Server: "10.0.0.1:7779", Command: "KickPlayer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Seq != 3 {
+		t.Errorf("seq after repair = %d, want 3", e.Seq)
+	}
+	if result := l.Verify(); !result.Valid || result.Entries != 3 {
+		t.Errorf("Verify after append = %+v", result)
+	}
+}
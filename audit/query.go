@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query selects audit entries; zero fields match everything
+type Query struct {
+	User    string
+	Server  string
+	Command string
+	Since   time.Time
+	Until   time.Time
+	Limit   int // Most recent N matches; 0 means all
+}
+
+// Match reports whether an entry satisfies the query filters
+func (q Query) Match(e Entry) bool {
+	if q.User != "" && !strings.EqualFold(e.User, q.User) {
+		return false
+	}
+	if q.Server != "" && e.Server != q.Server && e.Profile != q.Server {
+		return false
+	}
+	if q.Command != "" && !strings.EqualFold(e.Command, q.Command) {
+		return false
+	}
+	if !q.Since.IsZero() && e.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && e.Time.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Query returns matching entries in log order
+func (l *Log) Query(q Query) ([]Entry, error) {
+	var entries []Entry
+	err := l.Scan(func(e Entry) bool {
+		if !q.Match(e) {
+			return true
+		}
+		entries = append(entries, e)
+		if q.Limit > 0 && len(entries) > 2*q.Limit {
+			// Keep memory bounded while still returning the newest matches
+			entries = append(entries[:0], entries[len(entries)-q.Limit:]...)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if q.Limit > 0 && len(entries) > q.Limit {
+		entries = entries[len(entries)-q.Limit:]
+	}
+	return entries, nil
+}
+
+// csvHeader lists the CSV export columns
+var csvHeader = []string{
+	"seq", "time", "user", "client_ip", "session_id", "server", "profile",
+	"command", "body", "status_code", "status_message", "error", "prev_hash", "hash",
+}
+
+// WriteCSV exports entries as CSV with a header row
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			strconv.FormatUint(e.Seq, 10),
+			e.Time.Format(time.RFC3339Nano),
+			e.User,
+			e.ClientIP,
+			e.SessionID,
+			e.Server,
+			e.Profile,
+			e.Command,
+			string(e.Body),
+			strconv.Itoa(e.StatusCode),
+			e.StatusMessage,
+			e.Error,
+			e.PrevHash,
+			e.Hash,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSONL exports entries one JSON object per line, in the same form as the log file
+func WriteJSONL(w io.Writer, entries []Entry) error {
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/internal/fsutil"
+)
+
+// keyPrefix marks hllrcon API keys so they are recognisable in config files and secret scanners
+const keyPrefix = "hllrcon_"
+
+var (
+	ErrKeyNotFound = errors.New("api key not found")
+	ErrInvalidKey  = errors.New("invalid or revoked api key")
+)
+
+// APIKey is a long-lived credential for machine clients, bound to one server profile
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Role       Role       `json:"role"`
+	Profile    string     `json:"profile"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// Expired reports whether the key is past its expiry time
+func (k APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && now.After(*k.ExpiresAt)
+}
+
+// keyRecord is the on-disk form of a key; only a hash of the secret is kept
+type keyRecord struct {
+	APIKey
+	SecretHash string `json:"secret_hash"`
+}
+
+// KeyStore holds API keys in a JSON file
+type KeyStore struct {
+	path string
+
+	mu   sync.RWMutex
+	keys map[string]keyRecord
+}
+
+// OpenKeyStore loads API keys from path; the file is created on first write
+func OpenKeyStore(path string) (*KeyStore, error) {
+	s := &KeyStore{
+		path: path,
+		keys: make(map[string]keyRecord),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read api keys: %w", err)
+	}
+
+	var records []keyRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse api keys: %w", err)
+	}
+	for _, r := range records {
+		s.keys[r.ID] = r
+	}
+	return s, nil
+}
+
+// Create issues a new key and returns it with the plaintext token, which is not stored
+func (s *KeyStore) Create(key APIKey) (APIKey, string, error) {
+	id, err := randomString(6)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+	secret, err := randomString(32)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	key.ID = id
+	key.CreatedAt = time.Now().UTC()
+	key.LastUsedAt = nil
+	record := keyRecord{APIKey: key, SecretHash: hashSecret(secret)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[id] = record
+	if err := s.saveLocked(); err != nil {
+		delete(s.keys, id)
+		return APIKey{}, "", err
+	}
+	return key, keyPrefix + id + "_" + secret, nil
+}
+
+// Authenticate resolves a bearer token to its key
+func (s *KeyStore) Authenticate(token string) (APIKey, error) {
+	id, secret, ok := strings.Cut(strings.TrimPrefix(token, keyPrefix), "_")
+	if !ok || !strings.HasPrefix(token, keyPrefix) {
+		return APIKey{}, ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.keys[id]
+	if !ok {
+		return APIKey{}, ErrInvalidKey
+	}
+	if subtle.ConstantTimeCompare([]byte(record.SecretHash), []byte(hashSecret(secret))) != 1 {
+		return APIKey{}, ErrInvalidKey
+	}
+	now := time.Now().UTC()
+	if record.Expired(now) {
+		return APIKey{}, ErrInvalidKey
+	}
+
+	// Tracked in memory and persisted with the next write, to avoid a disk write per request
+	record.LastUsedAt = &now
+	s.keys[id] = record
+	return record.APIKey, nil
+}
+
+// List returns all keys sorted by creation time
+func (s *KeyStore) List() []APIKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]APIKey, 0, len(s.keys))
+	for _, r := range s.keys {
+		keys = append(keys, r.APIKey)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys
+}
+
+// Has reports whether a key with id exists
+func (s *KeyStore) Has(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.keys[id]
+	return ok
+}
+
+// Count returns the number of stored keys
+func (s *KeyStore) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.keys)
+}
+
+// Revoke deletes a key so it can no longer authenticate
+func (s *KeyStore) Revoke(id string) (APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.keys[id]
+	if !ok {
+		return APIKey{}, ErrKeyNotFound
+	}
+	delete(s.keys, id)
+
+	if err := s.saveLocked(); err != nil {
+		s.keys[id] = old
+		return APIKey{}, err
+	}
+	return old.APIKey, nil
+}
+
+// saveLocked atomically rewrites the key file (caller must hold write lock)
+func (s *KeyStore) saveLocked() error {
+	records := make([]keyRecord, 0, len(s.keys))
+	for _, r := range s.keys {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode api keys: %w", err)
+	}
+	return fsutil.WriteFileAtomic(s.path, data)
+}
+
+// hashSecret returns the hex SHA-256 of a key secret. Secrets are 256 random bits,
+// so a fast hash is sufficient.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomString returns n random bytes encoded as unpadded URL-safe base64 without '_'
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return strings.ReplaceAll(base64.RawURLEncoding.EncodeToString(b), "_", "-"), nil
+}
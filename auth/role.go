@@ -0,0 +1,67 @@
+// Package auth defines app users, their roles and how they are stored.
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Role grants access to API routes; each role includes everything below it
+type Role int
+
+const (
+	RoleViewer Role = iota + 1
+	RoleModerator
+	RoleAdmin
+	RoleOwner
+)
+
+// Roles lists every role from least to most privileged
+var Roles = []Role{RoleViewer, RoleModerator, RoleAdmin, RoleOwner}
+
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleModerator:
+		return "moderator"
+	case RoleAdmin:
+		return "admin"
+	case RoleOwner:
+		return "owner"
+	default:
+		return "unknown"
+	}
+}
+
+// Allows reports whether r grants at least the privileges of required
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+// ParseRole converts a role name to a Role
+func ParseRole(name string) (Role, error) {
+	for _, r := range Roles {
+		if r.String() == name {
+			return r, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown role %q (expected viewer, moderator, admin or owner)", name)
+}
+
+func (r Role) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Role) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	parsed, err := ParseRole(name)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
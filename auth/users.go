@@ -0,0 +1,304 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/internal/fsutil"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserExists         = errors.New("user already exists")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrBuiltinUser        = errors.New("the configured app user cannot be modified through the API")
+)
+
+var usernameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// User is an app user. The password hash is never serialised over the API.
+type User struct {
+	Username  string    `json:"username"`
+	Role      Role      `json:"role"`
+	Builtin   bool      `json:"builtin,omitempty"` // Defined in config rather than the user store
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// userRecord is the on-disk form of a user
+type userRecord struct {
+	User
+	PasswordHash string `json:"password_hash"`
+}
+
+// UserStore holds app users in a JSON file plus an optional builtin owner from config
+type UserStore struct {
+	path string
+
+	mu      sync.RWMutex
+	users   map[string]userRecord
+	builtin *userRecord
+	// verified caches an HMAC of the last password that passed bcrypt per user, keyed
+	// with cacheKey so the cache is useless outside this process
+	verified map[string][sha256.Size]byte
+	cacheKey []byte
+}
+
+// OpenUserStore loads users from path; the file is created on first write
+func OpenUserStore(path string) (*UserStore, error) {
+	s := &UserStore{
+		path:     path,
+		users:    make(map[string]userRecord),
+		verified: make(map[string][sha256.Size]byte),
+		cacheKey: make([]byte, 32),
+	}
+	if _, err := rand.Read(s.cacheKey); err != nil {
+		return nil, fmt.Errorf("failed to generate cache key: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+
+	var records []userRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse users: %w", err)
+	}
+	for _, r := range records {
+		s.users[r.Username] = r
+	}
+	return s, nil
+}
+
+// SetBuiltinOwner registers the app user from config as an owner that is not persisted
+func (s *UserStore) SetBuiltinOwner(username, password string) error {
+	if strings.TrimSpace(username) == "" {
+		username = "admin"
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	s.builtin = &userRecord{
+		User:         User{Username: username, Role: RoleOwner, Builtin: true, CreatedAt: now, UpdatedAt: now},
+		PasswordHash: string(hash),
+	}
+	delete(s.verified, username)
+	return nil
+}
+
+// Enabled reports whether any user can log in
+func (s *UserStore) Enabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.builtin != nil || len(s.users) > 0
+}
+
+// lookupLocked finds a user, preferring the builtin owner (caller must hold lock)
+func (s *UserStore) lookupLocked(username string) (userRecord, bool) {
+	if s.builtin != nil && s.builtin.Username == username {
+		return *s.builtin, true
+	}
+	r, ok := s.users[username]
+	return r, ok
+}
+
+// Authenticate checks a username and password and returns the user
+func (s *UserStore) Authenticate(username, password string) (User, error) {
+	digest := s.digest(username, password)
+
+	s.mu.RLock()
+	r, ok := s.lookupLocked(username)
+	cached, hit := s.verified[username]
+	s.mu.RUnlock()
+
+	if !ok {
+		// Spend comparable time so unknown usernames aren't distinguishable
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return User{}, ErrInvalidCredentials
+	}
+	if hit && subtle.ConstantTimeCompare(cached[:], digest[:]) == 1 {
+		return r.User, nil
+	}
+	if bcrypt.CompareHashAndPassword([]byte(r.PasswordHash), []byte(password)) != nil {
+		return User{}, ErrInvalidCredentials
+	}
+
+	s.mu.Lock()
+	s.verified[username] = digest
+	s.mu.Unlock()
+	return r.User, nil
+}
+
+// digest is the verified-cache entry for a username and password
+func (s *UserStore) digest(username, password string) [sha256.Size]byte {
+	mac := hmac.New(sha256.New, s.cacheKey)
+	mac.Write([]byte(username))
+	mac.Write([]byte{0})
+	mac.Write([]byte(password))
+	var sum [sha256.Size]byte
+	mac.Sum(sum[:0])
+	return sum
+}
+
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("hllrcon"), bcrypt.DefaultCost)
+
+// ValidateUsername checks a username is a short slug
+func ValidateUsername(username string) error {
+	if !usernameRe.MatchString(username) {
+		return fmt.Errorf("username must be 1-64 letters, digits, '.', '-' or '_'")
+	}
+	return nil
+}
+
+// List returns all users sorted by name, including the builtin owner
+func (s *UserStore) List() []User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]User, 0, len(s.users)+1)
+	if s.builtin != nil {
+		users = append(users, s.builtin.User)
+	}
+	for _, r := range s.users {
+		users = append(users, r.User)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users
+}
+
+// Get returns a single user
+func (s *UserStore) Get(username string) (User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.lookupLocked(username)
+	if !ok {
+		return User{}, ErrUserNotFound
+	}
+	return r.User, nil
+}
+
+// Create adds a user with the given password and role
+func (s *UserStore) Create(username, password string, role Role) (User, error) {
+	if err := ValidateUsername(username); err != nil {
+		return User{}, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lookupLocked(username); ok {
+		return User{}, ErrUserExists
+	}
+	now := time.Now().UTC()
+	r := userRecord{
+		User:         User{Username: username, Role: role, CreatedAt: now, UpdatedAt: now},
+		PasswordHash: string(hash),
+	}
+	s.users[username] = r
+
+	if err := s.saveLocked(); err != nil {
+		delete(s.users, username)
+		return User{}, err
+	}
+	return r.User, nil
+}
+
+// Update changes a user's role and, if password is non-empty, their password
+func (s *UserStore) Update(username, password string, role Role) (User, error) {
+	var hash []byte
+	if password != "" {
+		var err error
+		if hash, err = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost); err != nil {
+			return User{}, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.builtin != nil && s.builtin.Username == username {
+		return User{}, ErrBuiltinUser
+	}
+	old, ok := s.users[username]
+	if !ok {
+		return User{}, ErrUserNotFound
+	}
+	r := old
+	r.Role = role
+	r.UpdatedAt = time.Now().UTC()
+	if hash != nil {
+		r.PasswordHash = string(hash)
+	}
+	s.users[username] = r
+	delete(s.verified, username)
+
+	if err := s.saveLocked(); err != nil {
+		s.users[username] = old
+		return User{}, err
+	}
+	return r.User, nil
+}
+
+// Delete removes a user
+func (s *UserStore) Delete(username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.builtin != nil && s.builtin.Username == username {
+		return ErrBuiltinUser
+	}
+	old, ok := s.users[username]
+	if !ok {
+		return ErrUserNotFound
+	}
+	delete(s.users, username)
+	delete(s.verified, username)
+
+	if err := s.saveLocked(); err != nil {
+		s.users[username] = old
+		return err
+	}
+	return nil
+}
+
+// saveLocked atomically rewrites the user file (caller must hold write lock)
+func (s *UserStore) saveLocked() error {
+	records := make([]userRecord, 0, len(s.users))
+	for _, r := range s.users {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Username < records[j].Username })
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode users: %w", err)
+	}
+	return fsutil.WriteFileAtomic(s.path, data)
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuthenticateCache(t *testing.T) {
+	s, err := OpenUserStore(filepath.Join(t.TempDir(), "users.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("alice", "first-password", RoleViewer); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if _, err := s.Authenticate("alice", "first-password"); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	cached, ok := s.verified["alice"]
+	if !ok {
+		t.Fatal("successful login was not cached")
+	}
+	if cached == sha256.Sum256([]byte("first-password")) {
+		t.Error("cache holds a plain SHA-256 of the password")
+	}
+
+	other, err := OpenUserStore(filepath.Join(t.TempDir(), "users.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.digest("alice", "first-password") == cached {
+		t.Error("cache digests are the same across stores")
+	}
+
+	if _, err := s.Authenticate("alice", "wrong"); err != ErrInvalidCredentials {
+		t.Errorf("wrong password with a warm cache: %v", err)
+	}
+	if _, err := s.Update("alice", "second-password", RoleViewer); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := s.Authenticate("alice", "first-password"); err != ErrInvalidCredentials {
+		t.Errorf("old password after a change: %v", err)
+	}
+	if _, err := s.Authenticate("alice", "second-password"); err != nil {
+		t.Errorf("new password: %v", err)
+	}
+}
@@ -11,8 +11,19 @@ import (
 	"time"
 
 	"github.com/Sledro/hllrcon/api"
+	"github.com/Sledro/hllrcon/audit"
+	"github.com/Sledro/hllrcon/auth"
 	"github.com/Sledro/hllrcon/config"
+	"github.com/Sledro/hllrcon/history"
+	"github.com/Sledro/hllrcon/logstream"
+	"github.com/Sledro/hllrcon/metrics"
+	"github.com/Sledro/hllrcon/observe"
+	"github.com/Sledro/hllrcon/profile"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/schedule"
 	"github.com/Sledro/hllrcon/session"
+	"github.com/Sledro/hllrcon/tracing"
+	"github.com/Sledro/hllrcon/watchlist"
 	"github.com/gin-gonic/gin"
 	"github.com/lmittmann/tint"
 )
@@ -54,12 +65,36 @@ func main() {
 		"app_access_control_enabled", cfg.Security.AppPassword != "",
 	)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		SampleRatio: cfg.Tracing.SampleRatio,
+		ServiceName: "hllrcon",
+		Version:     Version,
+	})
+	if err != nil {
+		slog.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	if cfg.Tracing.Exporter != "" {
+		slog.Info("Tracing enabled", "exporter", cfg.Tracing.Exporter, "sample_ratio", cfg.Tracing.SampleRatio)
+	}
+
 	// Setup Gin
 	if cfg.Log.Level != "debug" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	router := gin.New()
 	router.Use(ginLogger(), gin.Recovery())
+	if cfg.Tracing.Exporter != "" {
+		router.Use(tracing.Middleware())
+	}
+
+	var metricsCollector *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		metricsCollector = metrics.New()
+		router.Use(metricsCollector.Middleware())
+	}
 
 	// Apply security middleware
 	if cfg.Security.EnableSecurityHeaders {
@@ -73,25 +108,215 @@ func main() {
 		router.Use(api.CORS(cfg.Security.AllowedOrigins))
 	}
 
-	// Apply app-level password access control if configured
+	// Saved server profiles are enabled once an encryption key is configured
+	var profiles *profile.Store
+	if cfg.Profiles.EncryptionKey != "" {
+		cipher, err := profile.NewCipher(cfg.Profiles.EncryptionKey)
+		if err != nil {
+			slog.Error("Invalid profile encryption key", "error", err)
+			os.Exit(1)
+		}
+		profiles, err = profile.Open(cfg.Profiles.Path, cipher)
+		if err != nil {
+			slog.Error("Failed to open profile store", "path", cfg.Profiles.Path, "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Server profiles enabled", "path", cfg.Profiles.Path, "count", len(profiles.List()))
+	}
+
+	// API keys are bound to profiles, so they are only available alongside them
+	var apiKeys *auth.KeyStore
+	if profiles != nil {
+		apiKeys, err = auth.OpenKeyStore(cfg.Security.APIKeysPath)
+		if err != nil {
+			slog.Error("Failed to open API key store", "path", cfg.Security.APIKeysPath, "error", err)
+			os.Exit(1)
+		}
+		slog.Info("API keys enabled", "path", cfg.Security.APIKeysPath, "count", apiKeys.Count())
+	}
+
+	// Apply role-based access control if an app password or stored users are configured
+	users, err := auth.OpenUserStore(cfg.Security.UsersPath)
+	if err != nil {
+		slog.Error("Failed to open user store", "path", cfg.Security.UsersPath, "error", err)
+		os.Exit(1)
+	}
 	if cfg.Security.AppPassword != "" {
-		slog.Info("App password access control enabled", "username", cfg.Security.AppUsername)
-		router.Use(api.PasswordAccessControl(cfg.Security.AppUsername, cfg.Security.AppPassword, []string{"/health"}))
+		if err := users.SetBuiltinOwner(cfg.Security.AppUsername, cfg.Security.AppPassword); err != nil {
+			slog.Error("Failed to configure app user", "error", err)
+			os.Exit(1)
+		}
+	}
+	if users.Enabled() {
+		slog.Info("App access control enabled", "username", cfg.Security.AppUsername, "users", len(users.List()))
+	} else {
+		users = nil
+		if profiles != nil {
+			slog.Warn("Server profiles are enabled without app access control; anyone reaching the UI can connect with them")
+		}
+	}
+	if users != nil || apiKeys != nil {
+		router.Use(api.Authorize(users, apiKeys, []string{"/health"}))
 	}
 
 	// Initialize session manager and API
 	slog.Info("Initializing Web UI")
-	sessionMgr := session.NewManager(time.Duration(cfg.Session.TimeoutMinutes) * time.Minute)
-
 	rconConfig := api.RCONConfig{
 		DialTimeout:     cfg.RCON.DialTimeoutSeconds,
 		MaxRequestSize:  cfg.RCON.MaxRequestSize,
 		MaxResponseSize: cfg.RCON.MaxResponseSize,
+		Timeouts:        cfg.RCON.GetTimeouts(),
+		Reconnect:       cfg.RCON.GetReconnectPolicy(),
+		MaxInFlight:     cfg.RCON.PipelineMaxInFlight,
+	}
+	if metricsCollector != nil {
+		rconConfig.Observer = metricsCollector
+	}
+	if cfg.RCON.RecordPath != "" {
+		recorder, err := rcon.OpenRecorder(cfg.RCON.RecordPath)
+		if err != nil {
+			slog.Error("Failed to open RCON cassette", "path", cfg.RCON.RecordPath, "error", err)
+			os.Exit(1)
+		}
+		defer recorder.Close()
+		rconConfig.Recorder = recorder
+		slog.Warn("Recording all RCON traffic; credentials are redacted but player data is not", "path", cfg.RCON.RecordPath)
+	}
+
+	pool := session.NewPool(rconConfig.NewClient, session.PoolConfig{
+		MaxConnsPerServer:   cfg.RCON.PoolMaxConnections,
+		IdleTimeout:         time.Duration(cfg.RCON.PoolIdleTimeoutSecs) * time.Second,
+		HealthCheckInterval: time.Duration(cfg.RCON.PoolHealthCheckSecs) * time.Second,
+	})
+	defer pool.Close()
+	sessionMgr := session.NewManager(time.Duration(cfg.Session.TimeoutMinutes)*time.Minute, pool)
+	logHub := logstream.NewHub(logstream.Config{
+		PollInterval:   time.Duration(cfg.LogStream.PollIntervalSeconds) * time.Second,
+		InitialBacklog: time.Duration(cfg.LogStream.InitialBacklogSeconds) * time.Second,
+		BufferSize:     cfg.LogStream.BufferSize,
+		IdleShutdown:   time.Duration(cfg.LogStream.IdleShutdownSeconds) * time.Second,
+	})
+	defer logHub.Close()
+
+	// Features that watch connected servers share one feed, so each server is sampled and
+	// its log followed once every observe.sample_seconds
+	feed := observe.NewFeed(pool, logHub)
+	var feeding bool
+	addToFeed := func(c observe.Consumer) {
+		feed.Add(c)
+		feeding = true
 	}
-	apiHandler := api.NewAPI(sessionMgr, Version, GitCommit, BuildDate, cfg.Session.SecureCookie, rconConfig)
+
+	if metricsCollector != nil {
+		metricsCollector.WatchSessions(sessionMgr)
+		if cfg.Metrics.Game {
+			addToFeed(metricsCollector.GameConsumer())
+		}
+	}
+
+	var auditLog *audit.Log
+	if cfg.Audit.Enabled {
+		auditLog, err = audit.Open(cfg.Audit.Path)
+		if err != nil {
+			slog.Error("Failed to open audit log", "path", cfg.Audit.Path, "error", err)
+			os.Exit(1)
+		}
+		defer auditLog.Close()
+		// Appending to a broken chain would bury the break, so refuse to start until an
+		// operator has looked at the file and moved it aside
+		result := auditLog.Verify()
+		if !result.Valid {
+			slog.Error("Audit log failed verification; entries may have been altered or removed. Move it and its .anchor file aside to start a new log",
+				"path", cfg.Audit.Path, "broken_at", result.BrokenAt, "reason", result.Reason)
+			os.Exit(1)
+		}
+		slog.Info("Audit log enabled", "path", cfg.Audit.Path, "entries", result.Entries)
+	}
+
+	var playerHistory *history.Store
+	if cfg.History.Enabled {
+		playerHistory, err = history.Open(cfg.History.Path)
+		if err != nil {
+			slog.Error("Failed to open player history", "path", cfg.History.Path, "error", err)
+			os.Exit(1)
+		}
+		defer playerHistory.Close()
+		addToFeed(history.NewCollector(playerHistory).Consumer())
+		slog.Info("Player history enabled", "path", cfg.History.Path)
+	}
+
+	var watched *watchlist.Store
+	if cfg.Watchlist.Enabled {
+		watched, err = watchlist.Open(cfg.Watchlist.Path)
+		if err != nil {
+			slog.Error("Failed to open watchlist", "path", cfg.Watchlist.Path, "error", err)
+			os.Exit(1)
+		}
+		monitor := watchlist.NewMonitor(watched, pool, watchlist.Config{
+			WebhookURL:    cfg.Watchlist.WebhookURL,
+			MessageAdmins: cfg.Watchlist.MessageAdmins,
+		})
+		addToFeed(monitor.Consumer())
+		slog.Info("Watchlist enabled", "path", cfg.Watchlist.Path, "webhook", cfg.Watchlist.WebhookURL != "")
+	}
+
+	if feeding {
+		feedCtx, stopFeed := context.WithCancel(context.Background())
+		defer stopFeed()
+		go feed.Run(feedCtx, time.Duration(cfg.Observe.SampleSeconds)*time.Second)
+	}
+
+	// Scheduled broadcasts and tasks connect through saved profiles, so they need them enabled
+	var broadcasts *schedule.BroadcastStore
+	if cfg.Broadcasts.Enabled && profiles != nil {
+		broadcasts, err = schedule.OpenBroadcasts(cfg.Broadcasts.Path)
+		if err != nil {
+			slog.Error("Failed to open scheduled broadcasts", "path", cfg.Broadcasts.Path, "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Scheduled broadcasts enabled", "path", cfg.Broadcasts.Path, "count", len(broadcasts.List()))
+	}
+	var tasks *schedule.TaskStore
+	if cfg.Tasks.Enabled && profiles != nil {
+		tasks, err = schedule.OpenTasks(cfg.Tasks.Path)
+		if err != nil {
+			slog.Error("Failed to open scheduled tasks", "path", cfg.Tasks.Path, "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Scheduled tasks enabled", "path", cfg.Tasks.Path, "timezone", cfg.Tasks.Timezone, "count", len(tasks.List()))
+	}
+	if broadcasts != nil || tasks != nil {
+		location, err := time.LoadLocation(cfg.Tasks.Timezone)
+		if err != nil {
+			slog.Error("Invalid scheduler timezone", "timezone", cfg.Tasks.Timezone, "error", err)
+			os.Exit(1)
+		}
+		runner := schedule.NewRunner(broadcasts, tasks, profiles, pool, schedule.Config{
+			Audit:    auditLog,
+			Location: location,
+		})
+		scheduleCtx, stopSchedules := context.WithCancel(context.Background())
+		defer stopSchedules()
+		go runner.Run(scheduleCtx)
+	}
+
+	apiHandler := api.NewAPI(sessionMgr, Version, GitCommit, BuildDate, cfg.Session.SecureCookie, rconConfig, logHub, api.Stores{
+		Profiles:   profiles,
+		Users:      users,
+		Audit:      auditLog,
+		APIKeys:    apiKeys,
+		History:    playerHistory,
+		Watchlist:  watched,
+		Broadcasts: broadcasts,
+		Tasks:      tasks,
+	})
 
 	// Setup API routes
 	apiHandler.SetupRoutes(router)
+	if metricsCollector != nil {
+		router.GET(cfg.Metrics.Path, metricsCollector.Handler())
+		slog.Info("Prometheus metrics enabled", "path", cfg.Metrics.Path)
+	}
 
 	// Create HTTP server
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -123,6 +348,9 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		slog.Error("Server forced to shutdown", "error", err)
 	}
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
 
 	slog.Info("Server stopped")
 }
@@ -5,17 +5,29 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/Sledro/hllrcon/rcon"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server     ServerConfig   `mapstructure:"server"`
-	Log        LogConfig      `mapstructure:"log"`
-	Session    SessionConfig  `mapstructure:"session"`
-	Security   SecurityConfig `mapstructure:"security"`
-	RCON       RCONConfig     `mapstructure:"rcon"`
-	ConfigFile string         // Path to loaded config file (empty if using defaults)
+	Server     ServerConfig     `mapstructure:"server"`
+	Log        LogConfig        `mapstructure:"log"`
+	Session    SessionConfig    `mapstructure:"session"`
+	Security   SecurityConfig   `mapstructure:"security"`
+	RCON       RCONConfig       `mapstructure:"rcon"`
+	LogStream  LogStreamConfig  `mapstructure:"logstream"`
+	Profiles   ProfilesConfig   `mapstructure:"profiles"`
+	Audit      AuditConfig      `mapstructure:"audit"`
+	Metrics    MetricsConfig    `mapstructure:"metrics"`
+	Tracing    TracingConfig    `mapstructure:"tracing"`
+	Observe    ObserveConfig    `mapstructure:"observe"`
+	History    HistoryConfig    `mapstructure:"history"`
+	Watchlist  WatchlistConfig  `mapstructure:"watchlist"`
+	Broadcasts BroadcastsConfig `mapstructure:"broadcasts"`
+	Tasks      TasksConfig      `mapstructure:"tasks"`
+	ConfigFile string           // Path to loaded config file (empty if using defaults)
 }
 
 type ServerConfig struct {
@@ -39,12 +51,80 @@ type SecurityConfig struct {
 	EnableSecurityHeaders bool     `mapstructure:"enable_security_headers"`
 	AppUsername           string   `mapstructure:"app_username"`
 	AppPassword           string   `mapstructure:"app_password"`
+	UsersPath             string   `mapstructure:"users_path"`    // JSON file holding additional app users and their roles
+	APIKeysPath           string   `mapstructure:"api_keys_path"` // JSON file holding hashed API keys (requires profiles)
 }
 
 type RCONConfig struct {
-	DialTimeoutSeconds int `mapstructure:"dial_timeout_seconds"`
-	MaxRequestSize     int `mapstructure:"max_request_size"`  // Max request size in bytes
-	MaxResponseSize    int `mapstructure:"max_response_size"` // Max response size in bytes
+	DialTimeoutSeconds    int            `mapstructure:"dial_timeout_seconds"`
+	MaxRequestSize        int            `mapstructure:"max_request_size"`        // Max request size in bytes
+	MaxResponseSize       int            `mapstructure:"max_response_size"`       // Max response size in bytes
+	CommandTimeoutSeconds int            `mapstructure:"command_timeout_seconds"` // Default deadline per command
+	CommandTimeouts       map[string]int `mapstructure:"command_timeouts"`        // Per-command deadline overrides in seconds
+	ReconnectAttempts     int            `mapstructure:"reconnect_attempts"`      // Handshake attempts after a lost connection (0 disables)
+	ReconnectBackoffMs    int            `mapstructure:"reconnect_backoff_ms"`    // Initial backoff between attempts, doubled each time
+	ReconnectMaxBackoffMs int            `mapstructure:"reconnect_max_backoff_ms"`
+	PoolMaxConnections    int            `mapstructure:"pool_max_connections"`      // Shared connections per server and credential
+	PoolIdleTimeoutSecs   int            `mapstructure:"pool_idle_timeout_seconds"` // Close unused connections after this long
+	PoolHealthCheckSecs   int            `mapstructure:"pool_health_check_seconds"` // Probe interval for idle connections
+	RecordPath            string         `mapstructure:"record_path"`               // JSONL cassette of every exchange, for debugging (empty disables)
+	PipelineMaxInFlight   int            `mapstructure:"pipeline_max_in_flight"`    // Requests outstanding per connection (below 2 keeps exchanges serial)
+}
+
+type LogStreamConfig struct {
+	PollIntervalSeconds   int `mapstructure:"poll_interval_seconds"`   // How often GetAdminLog is polled per server
+	InitialBacklogSeconds int `mapstructure:"initial_backlog_seconds"` // History fetched when a server is first followed
+	BufferSize            int `mapstructure:"buffer_size"`             // Entries kept for resuming clients
+	IdleShutdownSeconds   int `mapstructure:"idle_shutdown_seconds"`   // Stop polling once nobody has listened for this long
+}
+
+type ProfilesConfig struct {
+	Path          string `mapstructure:"path"`           // JSON file holding saved server profiles
+	EncryptionKey string `mapstructure:"encryption_key"` // Secret used to encrypt stored RCON passwords; profiles are disabled when empty
+}
+
+type AuditConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"` // Append-only JSONL file of state-changing commands
+}
+
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"` // Prometheus scrape endpoint
+	Game    bool   `mapstructure:"game"` // Sample connected servers' session info on the observation feed
+}
+
+type TracingConfig struct {
+	Exporter    string  `mapstructure:"exporter"`     // "otlp", "stdout", or empty to disable
+	Endpoint    string  `mapstructure:"endpoint"`     // OTLP/HTTP URL; empty uses OTEL_EXPORTER_OTLP_ENDPOINT
+	SampleRatio float64 `mapstructure:"sample_ratio"` // Fraction of new traces recorded
+}
+
+type ObserveConfig struct {
+	SampleSeconds int `mapstructure:"sample_seconds"` // How often connected servers are sampled for history, the watchlist and game metrics
+}
+
+type HistoryConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"` // bbolt database of player records
+}
+
+type WatchlistConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Path          string `mapstructure:"path"`           // JSON file of watched players
+	WebhookURL    string `mapstructure:"webhook_url"`    // Receives each alert as JSON (empty disables)
+	MessageAdmins bool   `mapstructure:"message_admins"` // Message online server admins in game
+}
+
+type BroadcastsConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // Requires profiles
+	Path    string `mapstructure:"path"`    // JSON file of scheduled broadcasts and their run history
+}
+
+type TasksConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`  // Requires profiles
+	Path     string `mapstructure:"path"`     // JSON file of scheduled tasks and their run history
+	Timezone string `mapstructure:"timezone"` // IANA timezone of broadcast and task cron expressions without their own
 }
 
 // Load reads configuration from config file and environment variables
@@ -67,11 +147,71 @@ func Load(configPath string) (*Config, error) {
 	v.SetDefault("security.enable_security_headers", true)
 	v.SetDefault("security.app_username", "admin")
 	v.SetDefault("security.app_password", "")
+	v.SetDefault("security.users_path", "data/users.json")
+	v.SetDefault("security.api_keys_path", "data/api_keys.json")
 
 	// RCON defaults
 	v.SetDefault("rcon.dial_timeout_seconds", 10)
 	v.SetDefault("rcon.max_request_size", 1048576)   // 1MB
 	v.SetDefault("rcon.max_response_size", 10485760) // 10MB
+	v.SetDefault("rcon.command_timeout_seconds", 10)
+	v.SetDefault("rcon.command_timeouts", map[string]int{
+		"GetAdminLog": 30,
+	})
+	v.SetDefault("rcon.reconnect_attempts", 3)
+	v.SetDefault("rcon.reconnect_backoff_ms", 500)
+	v.SetDefault("rcon.reconnect_max_backoff_ms", 10000)
+	v.SetDefault("rcon.pool_max_connections", 2)
+	v.SetDefault("rcon.pool_idle_timeout_seconds", 300)
+	v.SetDefault("rcon.pool_health_check_seconds", 60)
+	v.SetDefault("rcon.record_path", "")
+	v.SetDefault("rcon.pipeline_max_in_flight", 0)
+
+	// Log stream defaults
+	v.SetDefault("logstream.poll_interval_seconds", 2)
+	v.SetDefault("logstream.initial_backlog_seconds", 300)
+	v.SetDefault("logstream.buffer_size", 1000)
+	v.SetDefault("logstream.idle_shutdown_seconds", 60)
+
+	// Profile defaults
+	v.SetDefault("profiles.path", "data/profiles.json")
+	v.SetDefault("profiles.encryption_key", "")
+
+	// Audit defaults
+	v.SetDefault("audit.enabled", false)
+	v.SetDefault("audit.path", "data/audit.jsonl")
+
+	// Metrics defaults
+	v.SetDefault("metrics.enabled", false)
+	v.SetDefault("metrics.path", "/metrics")
+	v.SetDefault("metrics.game", true)
+
+	// Tracing defaults
+	v.SetDefault("tracing.exporter", "")
+	v.SetDefault("tracing.endpoint", "")
+	v.SetDefault("tracing.sample_ratio", 1.0)
+
+	// Observation feed defaults
+	v.SetDefault("observe.sample_seconds", 30)
+
+	// Player history defaults
+	v.SetDefault("history.enabled", false)
+	v.SetDefault("history.path", "data/history.db")
+
+	// Watchlist defaults
+	v.SetDefault("watchlist.enabled", false)
+	v.SetDefault("watchlist.path", "data/watchlist.json")
+	v.SetDefault("watchlist.webhook_url", "")
+	v.SetDefault("watchlist.message_admins", false)
+
+	// Scheduled broadcast defaults
+	v.SetDefault("broadcasts.enabled", false)
+	v.SetDefault("broadcasts.path", "data/broadcasts.json")
+
+	// Scheduled task defaults
+	v.SetDefault("tasks.enabled", false)
+	v.SetDefault("tasks.path", "data/tasks.json")
+	v.SetDefault("tasks.timezone", "UTC")
 
 	// Config file
 	if configPath != "" {
@@ -132,6 +272,27 @@ func (c *LogConfig) GetLogLevel() slog.Level {
 	}
 }
 
+// GetTimeouts converts the configured command timeouts to rcon.Timeouts
+func (c *RCONConfig) GetTimeouts() rcon.Timeouts {
+	commands := make(map[string]time.Duration, len(c.CommandTimeouts))
+	for name, seconds := range c.CommandTimeouts {
+		commands[name] = time.Duration(seconds) * time.Second
+	}
+	return rcon.Timeouts{
+		Default:  time.Duration(c.CommandTimeoutSeconds) * time.Second,
+		Commands: commands,
+	}
+}
+
+// GetReconnectPolicy converts the configured reconnect settings to rcon.ReconnectPolicy
+func (c *RCONConfig) GetReconnectPolicy() rcon.ReconnectPolicy {
+	return rcon.ReconnectPolicy{
+		MaxAttempts:    c.ReconnectAttempts,
+		InitialBackoff: time.Duration(c.ReconnectBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(c.ReconnectMaxBackoffMs) * time.Millisecond,
+	}
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	// No validation needed for web UI mode
@@ -78,7 +78,9 @@ export class HllRconClient {
       Accept: "application/json"
     };
 
-    if (config.hllrconAppPassword) {
+    if (config.hllrconApiKey) {
+      headers.Authorization = `Bearer ${config.hllrconApiKey}`;
+    } else if (config.hllrconAppPassword) {
       headers.Authorization = toBasicAuthHeader(config.hllrconAppUsername, config.hllrconAppPassword);
     }
 
@@ -131,6 +133,11 @@ export class HllRconClient {
   }
 
   async ensureConnected(): Promise<void> {
+    if (config.hllrconApiKey) {
+      // The API key resolves its own RCON connection on every request
+      return;
+    }
+
     try {
       const status = await this.request<{ connected?: boolean }>("GET", "/api/v2/connection/status");
       if (!status.connected) {
@@ -1,5 +1,7 @@
 import path from "node:path";
 
+const hllrconApiKey = process.env.HLLRCON_API_KEY ?? "";
+
 function required(name: string): string {
   const value = process.env[name];
   if (!value) {
@@ -15,9 +17,11 @@ export const config = {
   hllrconBaseUrl: required("HLLRCON_BASE_URL").replace(/\/+$/, ""),
   hllrconAppUsername: process.env.HLLRCON_APP_USERNAME ?? "",
   hllrconAppPassword: process.env.HLLRCON_APP_PASSWORD ?? "",
-  hllServerHost: required("HLL_SERVER_HOST"),
+  // An API key is bound to a server profile, so the server credentials are only needed without one
+  hllrconApiKey,
+  hllServerHost: hllrconApiKey ? process.env.HLL_SERVER_HOST ?? "" : required("HLL_SERVER_HOST"),
   hllServerPort: Number(process.env.HLL_SERVER_PORT ?? "28015"),
-  hllServerPassword: required("HLL_SERVER_PASSWORD"),
+  hllServerPassword: hllrconApiKey ? process.env.HLL_SERVER_PASSWORD ?? "" : required("HLL_SERVER_PASSWORD"),
   categoryName: process.env.HLL_DISCORD_CATEGORY_NAME ?? "HLL Control",
   defaultAdminName: process.env.HLL_DEFAULT_ADMIN_NAME ?? "Discord Bot",
   stateFile: path.resolve(process.env.HLL_STATE_FILE ?? "./data/state.json"),
@@ -199,8 +199,8 @@ export function createAdminManagementMessage(admins: unknown, adminGroups: unkno
 
 export function createServerInfoMessage(snapshot: OverviewSnapshot): MessageEditOptions {
   const serverInfo = snapshot.serverInfo ?? {};
-  const serverName = firstNonEmptyRecordValue(serverInfo, ["ServerName", "name", "Name"]) ?? "HLL Server";
-  const currentMap = firstNonEmptyRecordValue(serverInfo, ["Map", "map", "CurrentMap"]) ?? "Unknown";
+  const serverName = firstNonEmptyRecordValue(serverInfo, ["server_name", "ServerName", "name", "Name"]) ?? "HLL Server";
+  const currentMap = firstNonEmptyRecordValue(serverInfo, ["map_name", "Map", "map", "CurrentMap"]) ?? "Unknown";
   const gameMode = firstNonEmptyRecordValue(serverInfo, ["GameMode", "game_mode", "Mode"]) ?? "Unknown";
   const sessionLabel = currentMap === "Unknown" && gameMode === "Unknown" ? "Use the buttons below to inspect the live session." : `${currentMap} • ${gameMode}`;
   const embed = panelBase("Server Info", "Read-only server context and operational visibility.", 0x3498db)
@@ -190,7 +190,7 @@ export class PlayersComponent {
         // Sort players by team, then by score
         const sortedPlayers = [...players].sort((a, b) => {
             if (a.team !== b.team) return a.team - b.team;
-            return this.getTotalScore(b.scoreData) - this.getTotalScore(a.scoreData);
+            return this.getTotalScore(b.score_data) - this.getTotalScore(a.score_data);
         });
 
         sortedPlayers.forEach((player, index) => {
@@ -207,7 +207,7 @@ export class PlayersComponent {
                     <td class="col-name">
                         <div class="player-name-cell">
                             <span class="player-name">${this.escapeHTML(player.name)}</span>
-                            ${player.clanTag ? `<span class="clan-tag">[${this.escapeHTML(player.clanTag)}]</span>` : ''}
+                            ${player.clan_tag ? `<span class="clan-tag">[${this.escapeHTML(player.clan_tag)}]</span>` : ''}
                         </div>
                     </td>
                     <td class="col-team">
@@ -221,7 +221,7 @@ export class PlayersComponent {
                         <span class="kdr-display" data-kdr="${this.getKDRCategory(kdr)}">${kdr}</span>
                     </td>
                     <td class="col-score">
-                        <span class="score-display" data-score="${this.getScoreCategory(this.getTotalScore(player.scoreData))}">${this.getTotalScore(player.scoreData)}</span>
+                        <span class="score-display" data-score="${this.getScoreCategory(this.getTotalScore(player.score_data))}">${this.getTotalScore(player.score_data)}</span>
                     </td>
                     <td class="col-role">
                         <span class="role-display" data-role="${player.role}">${roleName}</span>
@@ -239,22 +239,22 @@ export class PlayersComponent {
                                     <path d="M12 4.5C7 4.5 2.73 7.61 1 12c1.73 4.39 6 7.5 11 7.5s9.27-3.11 11-7.5c-1.73-4.39-6-7.5-11-7.5zM12 17c-2.76 0-5-2.24-5-5s2.24-5 5-5 5 2.24 5 5-2.24 5-5 5zm0-8c-1.66 0-3 1.34-3 3s1.34 3 3 3 3-1.34 3-3-1.34-3-3-3z"/>
                                 </svg>
                             </button>
-                            <button class="action-btn message-btn" onclick="messagePlayer('${player.iD}', '${this.escapeHTML(player.name)}')" title="Message Player">
+                            <button class="action-btn message-btn" onclick="messagePlayer('${player.id}', '${this.escapeHTML(player.name)}')" title="Message Player">
                                 <svg width="12" height="12" viewBox="0 0 24 24" fill="currentColor">
                                     <path d="M20 2H4c-1.1 0-1.99.9-1.99 2L2 22l4-4h14c1.1 0 2-.9 2-2V4c0-1.1-.9-2-2-2zm-2 12H6v-2h12v2zm0-3H6V9h12v2zm0-3H6V6h12v2z"/>
                                 </svg>
                             </button>
-                            <button class="action-btn punish-btn" onclick="punishPlayer('${player.iD}', '${this.escapeHTML(player.name)}')" title="Punish Player">
+                            <button class="action-btn punish-btn" onclick="punishPlayer('${player.id}', '${this.escapeHTML(player.name)}')" title="Punish Player">
                                 <svg width="12" height="12" viewBox="0 0 24 24" fill="currentColor">
                                     <path d="M12 2C6.48 2 2 6.48 2 12s4.48 10 10 10 10-4.48 10-10S17.52 2 12 2zM4 12c0-4.42 3.58-8 8-8 1.85 0 3.55.63 4.9 1.69L5.69 16.9C4.63 15.55 4 13.85 4 12zm8 8c-1.85 0-3.55-.63-4.9-1.69L18.31 7.1C19.37 8.45 20 10.15 20 12c0 4.42-3.58 8-8 8z"/>
                                 </svg>
                             </button>
-                            <button class="action-btn switch-btn" onclick="switchPlayerTeam('${player.iD}', '${this.escapeHTML(player.name)}')" title="Switch Team">
+                            <button class="action-btn switch-btn" onclick="switchPlayerTeam('${player.id}', '${this.escapeHTML(player.name)}')" title="Switch Team">
                                 <svg width="12" height="12" viewBox="0 0 24 24" fill="currentColor">
                                     <path d="M6.99 11L3 15l3.99 4v-3H14v-2H6.99v-3zM21 9l-3.99-4v3H10v2h7.01v3L21 9z"/>
                                 </svg>
                             </button>
-                            <button class="action-btn kick-btn" onclick="kickPlayer('${player.iD}', '${this.escapeHTML(player.name)}')" title="Kick Player">
+                            <button class="action-btn kick-btn" onclick="kickPlayer('${player.id}', '${this.escapeHTML(player.name)}')" title="Kick Player">
                                 <svg width="12" height="12" viewBox="0 0 24 24" fill="currentColor">
                                     <path d="M19 6.41L17.59 5 12 10.59 6.41 5 5 6.41 10.59 12 5 17.59 6.41 19 12 13.41 17.59 19 19 17.59 13.41 12z"/>
                                 </svg>
@@ -387,7 +387,7 @@ export class PlayersComponent {
                 <div class="player-header-section">
                     <h3 class="player-detail-name">
                         ${this.escapeHTML(player.name)}
-                        ${player.clanTag ? `<span class="clan-tag">[${this.escapeHTML(player.clanTag)}]</span>` : ''}
+                        ${player.clan_tag ? `<span class="clan-tag">[${this.escapeHTML(player.clan_tag)}]</span>` : ''}
                     </h3>
                     <div class="player-badges">
                         <span class="team-badge ${teamClass}">${team}</span>
@@ -413,7 +413,7 @@ export class PlayersComponent {
                         </div>
                         <div class="stat-row">
                             <span class="stat-name">Total Score:</span>
-                            <span class="score-display" data-score="${this.getScoreCategory(this.getTotalScore(player.scoreData))}">${this.getTotalScore(player.scoreData)}</span>
+                            <span class="score-display" data-score="${this.getScoreCategory(this.getTotalScore(player.score_data))}">${this.getTotalScore(player.score_data)}</span>
                         </div>
                     </div>
 
@@ -421,19 +421,19 @@ export class PlayersComponent {
                         <h4>Score Breakdown</h4>
                         <div class="stat-row">
                             <span class="stat-name">Combat:</span>
-                            <span class="stat-value">${player.scoreData?.cOMBAT || 0}</span>
+                            <span class="stat-value">${player.score_data?.combat || 0}</span>
                         </div>
                         <div class="stat-row">
                             <span class="stat-name">Defense:</span>
-                            <span class="stat-value">${player.scoreData?.defense || 0}</span>
+                            <span class="stat-value">${player.score_data?.defense || 0}</span>
                         </div>
                         <div class="stat-row">
                             <span class="stat-name">Offense:</span>
-                            <span class="stat-value">${player.scoreData?.offense || 0}</span>
+                            <span class="stat-value">${player.score_data?.offense || 0}</span>
                         </div>
                         <div class="stat-row">
                             <span class="stat-name">Support:</span>
-                            <span class="stat-value">${player.scoreData?.support || 0}</span>
+                            <span class="stat-value">${player.score_data?.support || 0}</span>
                         </div>
                     </div>
 
@@ -453,7 +453,7 @@ export class PlayersComponent {
                         </div>
                         <div class="stat-row">
                             <span class="stat-name">Position:</span>
-                            <span class="stat-value">X:${player.worldPosition?.x || 0} Y:${player.worldPosition?.y || 0} Z:${player.worldPosition?.z || 0}</span>
+                            <span class="stat-value">X:${player.world_position?.x || 0} Y:${player.world_position?.y || 0} Z:${player.world_position?.z || 0}</span>
                         </div>
                     </div>
                 </div>
@@ -461,13 +461,13 @@ export class PlayersComponent {
                 <div class="player-actions-section">
                     <h4>Admin Actions</h4>
                     <div class="detail-actions-grid">
-                        <button class="detail-action-btn message-action" onclick="messagePlayer('${player.iD}', '${this.escapeHTML(player.name)}')">
+                        <button class="detail-action-btn message-action" onclick="messagePlayer('${player.id}', '${this.escapeHTML(player.name)}')">
                             <svg width="14" height="14" viewBox="0 0 24 24" fill="currentColor">
                                 <path d="M20 2H4c-1.1 0-1.99.9-1.99 2L2 22l4-4h14c1.1 0 2-.9 2-2V4c0-1.1-.9-2-2-2zm-2 12H6v-2h12v2zm0-3H6V9h12v2zm0-3H6V6h12v2z"/>
                             </svg>
                             Message Player
                         </button>
-                        <button class="detail-action-btn kick-action" onclick="kickPlayer('${player.iD}', '${this.escapeHTML(player.name)}')">
+                        <button class="detail-action-btn kick-action" onclick="kickPlayer('${player.id}', '${this.escapeHTML(player.name)}')">
                             <svg width="14" height="14" viewBox="0 0 24 24" fill="currentColor">
                                 <path d="M19 6.41L17.59 5 12 10.59 6.41 5 5 6.41 10.59 12 5 17.59 6.41 19 12 13.41 17.59 19 19 17.59 13.41 12z"/>
                             </svg>
@@ -478,11 +478,11 @@ export class PlayersComponent {
                     <div class="player-ids">
                         <div class="id-section">
                             <span class="id-label">Player ID:</span>
-                            <span class="player-id selectable">${player.iD}</span>
+                            <span class="player-id selectable">${player.id}</span>
                         </div>
                         <div class="id-section">
                             <span class="id-label">EOS ID:</span>
-                            <span class="player-id selectable">${player.eosId || 'N/A'}</span>
+                            <span class="player-id selectable">${player.eos_id || 'N/A'}</span>
                         </div>
                     </div>
                 </div>
@@ -503,11 +503,11 @@ export class PlayersComponent {
                         </div>
                         <div class="detail-item">
                             <span class="detail-label">EOS ID:</span>
-                            <span class="detail-value player-id">${player.eosId || 'N/A'}</span>
+                            <span class="detail-value player-id">${player.eos_id || 'N/A'}</span>
                         </div>
                         <div class="detail-item">
                             <span class="detail-label">Player ID:</span>
-                            <span class="detail-value player-id">${player.iD}</span>
+                            <span class="detail-value player-id">${player.id}</span>
                         </div>
                     </div>
                     <div class="detail-group">
@@ -515,19 +515,19 @@ export class PlayersComponent {
                         <div class="score-breakdown">
                             <div class="score-item">
                                 <span class="score-type">Combat:</span>
-                                <span class="score-num">${player.scoreData?.cOMBAT || 0}</span>
+                                <span class="score-num">${player.score_data?.combat || 0}</span>
                             </div>
                             <div class="score-item">
                                 <span class="score-type">Defense:</span>
-                                <span class="score-num">${player.scoreData?.defense || 0}</span>
+                                <span class="score-num">${player.score_data?.defense || 0}</span>
                             </div>
                             <div class="score-item">
                                 <span class="score-type">Offense:</span>
-                                <span class="score-num">${player.scoreData?.offense || 0}</span>
+                                <span class="score-num">${player.score_data?.offense || 0}</span>
                             </div>
                             <div class="score-item">
                                 <span class="score-type">Support:</span>
-                                <span class="score-num">${player.scoreData?.support || 0}</span>
+                                <span class="score-num">${player.score_data?.support || 0}</span>
                             </div>
                         </div>
                     </div>
@@ -535,7 +535,7 @@ export class PlayersComponent {
                         <h4>Position</h4>
                         <div class="detail-item">
                             <span class="detail-label">Coordinates:</span>
-                            <span class="detail-value">X: ${player.worldPosition?.x || 0}, Y: ${player.worldPosition?.y || 0}, Z: ${player.worldPosition?.z || 0}</span>
+                            <span class="detail-value">X: ${player.world_position?.x || 0}, Y: ${player.world_position?.y || 0}, Z: ${player.world_position?.z || 0}</span>
                         </div>
                     </div>
                 </div>
@@ -754,15 +754,15 @@ export class PlayersComponent {
             }
 
             // Extract data from HLL RCON response
-            currentMap = sessionData?.mapName || sessionData?.mapId || 'Unknown Map';
-            timeRemaining = sessionData?.remainingMatchTime ?? 'Unknown';
+            currentMap = sessionData?.map_name || sessionData?.map_id || 'Unknown Map';
+            timeRemaining = sessionData?.remaining_match_time ?? 'Unknown';
             if (typeof timeRemaining === 'number') {
                 this.lastRemainingTime = timeRemaining;
             }
-            alliedScore = sessionData?.alliedScore || 0;
-            axisScore = sessionData?.axisScore || 0;
-            maxPlayers = sessionData?.maxPlayerCount || 100;
-            currentPlayers = sessionData?.playerCount || 0;
+            alliedScore = sessionData?.allied_score || 0;
+            axisScore = sessionData?.axis_score || 0;
+            maxPlayers = sessionData?.max_player_count || 100;
+            currentPlayers = sessionData?.player_count || 0;
 
             // Update in-place if grid already exists, otherwise build it
             const existing = matchContainer.querySelector('.match-data-grid');
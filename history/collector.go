@@ -0,0 +1,49 @@
+package history
+
+import (
+	"log/slog"
+
+	"github.com/Sledro/hllrcon/logstream"
+	"github.com/Sledro/hllrcon/observe"
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+// Collector feeds a Store from the shared observation feed: player list samples become
+// sessions and admin log entries become events
+type Collector struct {
+	store *Store
+}
+
+func NewCollector(store *Store) *Collector {
+	return &Collector{store: store}
+}
+
+// Consumer returns the feed consumer that records into the store
+func (c *Collector) Consumer() observe.Consumer {
+	return observe.Consumer{Players: true, Round: c.observe, Log: c.ingest}
+}
+
+// observe records the player snapshot of each server that answered
+func (c *Collector) observe(round observe.Round) {
+	for _, s := range round.Servers {
+		if s.PlayersErr != nil {
+			continue
+		}
+		if err := c.store.ObservePlayers(s.Addr, round.At, s.Players); err != nil {
+			slog.Error("Failed to record player snapshot", "server", s.Addr, "error", err)
+		}
+	}
+}
+
+// ingest applies a batch of admin log entries of server
+func (c *Collector) ingest(server string, _ *rcon.Client, batch []logstream.Entry) error {
+	entries := make([]rcon.LogEntry, len(batch))
+	for i, e := range batch {
+		entries[i] = rcon.LogEntry{Timestamp: e.Timestamp, Message: e.Message}
+	}
+	if _, err := c.store.IngestLog(server, entries); err != nil {
+		slog.Error("Failed to record admin log events", "server", server, "error", err)
+		return err
+	}
+	return nil
+}
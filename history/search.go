@@ -0,0 +1,236 @@
+package history
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Sledro/hllrcon/rcon"
+	bolt "go.etcd.io/bbolt"
+)
+
+// minScore is the weakest similarity reported as a match
+const minScore = 0.6
+
+// lookalikes undoes the usual character swaps in player names
+var lookalikes = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b',
+	'$': 's', '@': 'a', '!': 'i', '|': 'l',
+}
+
+// Match is a player whose ID or one of whose names matches a search
+type Match struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`  // Best matching name, or the latest one when the ID matched
+	Score    float64   `json:"score"` // 1 for an exact match, down to 0.6
+	Online   bool      `json:"online"`
+	LastSeen time.Time `json:"last_seen"`
+	Aliases  []Name    `json:"aliases"` // Every name used, most recent first
+}
+
+// Search returns recorded players matching query by ID or by any name they have
+// used, best first. Names are compared case-insensitively with decorations such as
+// clan tags, separators and look-alike digits folded away, and small typos tolerated.
+// A limit of 0 returns every match.
+func (s *Store) Search(query string, limit int) ([]Match, error) {
+	var matches []Match
+	err := s.db.View(func(tx *bolt.Tx) error {
+		online := onlineIDs(tx)
+		return tx.Bucket(aliasesBucket).ForEach(func(k, v []byte) error {
+			var entry aliasEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return fmt.Errorf("corrupt aliases for %s: %w", k, err)
+			}
+			m := Match{ID: string(k), Online: online[string(k)], LastSeen: entry.LastSeen, Aliases: sortAliases(entry.Names)}
+			if m.Score, m.Name = matchPlayer(query, m.ID, m.Aliases); m.Score >= minScore {
+				matches = append(matches, m)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rank(matches, limit), nil
+}
+
+// MergeLive folds a live player list into search results, so players who joined
+// since the last snapshot are found and listed players are marked online
+func MergeLive(matches []Match, query string, live []rcon.Player, at time.Time, limit int) []Match {
+	for _, p := range live {
+		if p.ID == "" {
+			continue
+		}
+		score, _ := matchPlayer(query, p.ID, []Name{{Name: p.Name}})
+		i := slices.IndexFunc(matches, func(m Match) bool { return m.ID == p.ID })
+		if i < 0 {
+			if score < minScore {
+				continue
+			}
+			matches = append(matches, Match{ID: p.ID, LastSeen: at, Aliases: []Name{{Name: p.Name, FirstSeen: at, LastSeen: at}}})
+			i = len(matches) - 1
+		}
+
+		m := &matches[i]
+		m.Online = true
+		if at.After(m.LastSeen) {
+			m.LastSeen = at
+		}
+		if j := slices.IndexFunc(m.Aliases, func(n Name) bool { return n.Name == p.Name }); j >= 0 {
+			m.Aliases[j].LastSeen = at
+		} else if p.Name != "" {
+			m.Aliases = append(m.Aliases, Name{Name: p.Name, FirstSeen: at, LastSeen: at})
+		}
+		m.Aliases = sortAliases(m.Aliases)
+		if score >= m.Score {
+			m.Score, m.Name = score, p.Name
+		}
+	}
+	return rank(matches, limit)
+}
+
+// onlineIDs returns the players with an open session on any server
+func onlineIDs(tx *bolt.Tx) map[string]bool {
+	ids := make(map[string]bool)
+	tx.Bucket(onlineBucket).ForEachBucket(func(server []byte) error {
+		return tx.Bucket(onlineBucket).Bucket(server).ForEach(func(k, _ []byte) error {
+			ids[string(k)] = true
+			return nil
+		})
+	})
+	return ids
+}
+
+// matchPlayer scores a player against a query: an exact ID is a full match, else the
+// best scoring name counts. aliases must be most recent first.
+func matchPlayer(query, id string, aliases []Name) (float64, string) {
+	if strings.EqualFold(strings.TrimSpace(query), id) {
+		if len(aliases) > 0 {
+			return 1, aliases[0].Name
+		}
+		return 1, ""
+	}
+	var best float64
+	var name string
+	for _, a := range aliases {
+		if s := nameScore(query, a.Name); s > best {
+			best, name = s, a.Name
+		}
+	}
+	return math.Round(best*100) / 100, name
+}
+
+// nameScore rates a name against a query, with and without bracketed clan tags so
+// "[ABC] Bob" and "Bob" find each other
+func nameScore(query, name string) float64 {
+	return max(foldedScore(foldName(query), foldName(name)), foldedScore(foldName(stripTags(query)), foldName(stripTags(name))))
+}
+
+// foldedScore rates a folded name against a folded query: 1 when equal, 0.8 and up
+// when the query is contained in the name, and otherwise by edit distance to the
+// whole name or to its closest stretch of the query's length
+func foldedScore(query, name string) float64 {
+	q, n := []rune(query), []rune(name)
+	switch {
+	case len(q) == 0 || len(n) == 0:
+		return 0
+	case string(q) == string(n):
+		return 1
+	case strings.Contains(string(n), string(q)):
+		return 0.8 + 0.2*float64(len(q))/float64(len(n))
+	}
+
+	best := 1 - float64(levenshtein(q, n))/float64(max(len(q), len(n)))
+	for i := 0; i+len(q) <= len(n); i++ {
+		// Ranked below containment, as the rest of the name did not match
+		best = max(best, 0.8*(1-float64(levenshtein(q, n[i:i+len(q)]))/float64(len(q))))
+	}
+	return best
+}
+
+// stripTags removes bracketed segments such as clan tags
+func stripTags(name string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch r {
+		case '[', '(', '{', '<':
+			depth++
+		case ']', ')', '}', '>':
+			depth = max(depth-1, 0)
+		default:
+			if depth == 0 {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// foldName lowercases a name, undoes look-alike characters and drops everything
+// that is not a letter or digit
+func foldName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if f, ok := lookalikes[r]; ok {
+			r = f
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// levenshtein is the edit distance between a and b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// sortAliases orders names most recently used first
+func sortAliases(names []Name) []Name {
+	slices.SortStableFunc(names, func(a, b Name) int { return b.LastSeen.Compare(a.LastSeen) })
+	return names
+}
+
+// rank orders matches best first, then online players, then the most recently seen,
+// and keeps at most limit of them
+func rank(matches []Match, limit int) []Match {
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		if a.Online != b.Online {
+			if a.Online {
+				return -1
+			}
+			return 1
+		}
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
@@ -0,0 +1,199 @@
+package history
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+func TestFoldName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"xX_Sn1per_Xx", "xxsniperxx"},
+		{"B0b$", "bobs"},
+		{"|_3g3nd!", "legendi"},
+		{"Ünter 7", "üntert"},
+		{"--", ""},
+	}
+	for _, tt := range tests {
+		if got := foldName(tt.in); got != tt.want {
+			t.Errorf("foldName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStripTags(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"[7TH] Able", " Able"},
+		{"{x}Baker(EU)", "Baker"},
+		{"<[nested]>Charlie", "Charlie"},
+		{"Dog]", "Dog"},
+		{"Easy", "Easy"},
+	}
+	for _, tt := range tests {
+		if got := stripTags(tt.in); got != tt.want {
+			t.Errorf("stripTags(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+	}
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFoldedScore(t *testing.T) {
+	tests := []struct {
+		name        string
+		query, in   string
+		want        float64
+		wantMatched bool
+	}{
+		{"equal", "sniper", "sniper", 1, true},
+		{"contained", "sniper", "xxsniperxx", 0.92, true},
+		{"typo", "sniepr", "sniper", 0.67, true},
+		{"typo inside a longer name", "sniepr", "thesniperclan", 0.53, false},
+		{"unrelated", "zzzz", "sniper", 0, false},
+		{"empty query", "", "sniper", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := foldedScore(tt.query, tt.in)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("foldedScore(%q, %q) = %.3f, want %.2f", tt.query, tt.in, got, tt.want)
+			}
+			if matched := got >= minScore; matched != tt.wantMatched {
+				t.Errorf("foldedScore(%q, %q) = %.3f passes the %.1f cutoff: %v", tt.query, tt.in, got, minScore, matched)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	t0 := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	matches := []Match{
+		{ID: "offline-old", Score: 0.9, LastSeen: t0},
+		{ID: "weaker", Score: 0.7, Online: true, LastSeen: t0.Add(time.Hour)},
+		{ID: "offline-new", Score: 0.9, LastSeen: t0.Add(time.Minute)},
+		{ID: "online", Score: 0.9, Online: true, LastSeen: t0.Add(-time.Hour)},
+		{ID: "exact", Score: 1, LastSeen: t0.Add(-24 * time.Hour)},
+	}
+
+	var got []string
+	for _, m := range rank(slices.Clone(matches), 0) {
+		got = append(got, m.ID)
+	}
+	want := []string{"exact", "online", "offline-new", "offline-old", "weaker"}
+	if !slices.Equal(got, want) {
+		t.Errorf("rank = %v, want %v", got, want)
+	}
+	if n := len(rank(slices.Clone(matches), 2)); n != 2 {
+		t.Errorf("rank with limit 2 kept %d", n)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	s := openTestStore(t)
+	t0 := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	able := rcon.Player{Name: "[7TH] Sn1per", ID: "76561198000000001"}
+	baker := rcon.Player{Name: "Bob", ID: "76561198000000002"}
+	for _, step := range []struct {
+		at      time.Time
+		players []rcon.Player
+	}{
+		{t0, []rcon.Player{able, baker}},
+		{t0.Add(time.Minute), []rcon.Player{able}},
+	} {
+		if err := s.ObservePlayers(testServer, step.at, step.players); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantIDs []string
+		score   float64
+	}{
+		{"clan tag and look-alike digit", "sniper", []string{able.ID}, 1},
+		{"typo", "snipr", []string{able.ID}, 0.8},
+		{"exact ID", baker.ID, []string{baker.ID}, 1},
+		{"below the cutoff", "zzzz", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := s.Search(tt.query, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, m := range matches {
+				ids = append(ids, m.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, ids, tt.wantIDs)
+			}
+			if len(matches) > 0 && matches[0].Score < tt.score {
+				t.Errorf("Search(%q) scored %.2f, want at least %.2f", tt.query, matches[0].Score, tt.score)
+			}
+		})
+	}
+
+	matches, err := s.Search("sniper", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := matches[0]; !m.Online || m.Name != able.Name || len(m.Aliases) != 1 {
+		t.Errorf("Able = %+v, want online under the listed name", m)
+	}
+	matches, err = s.Search(baker.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := matches[0]; m.Online || !m.LastSeen.Equal(t0) {
+		t.Errorf("Baker = %+v, want offline and last seen at %v", m, t0)
+	}
+}
+
+func TestMergeLive(t *testing.T) {
+	t0 := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	at := t0.Add(time.Hour)
+	recorded := []Match{
+		{ID: "1", Name: "Sniper", Score: 1, LastSeen: t0, Aliases: []Name{{Name: "Sniper", FirstSeen: t0, LastSeen: t0}}},
+	}
+	live := []rcon.Player{
+		{ID: "1", Name: "Sn1per_TTV"},
+		{ID: "2", Name: "xX_Sniper_Xx"},
+		{ID: "3", Name: "Charlie"},
+		{Name: "Sniper"},
+	}
+
+	got := MergeLive(recorded, "sniper", live, at, 0)
+	if len(got) != 2 {
+		t.Fatalf("merged %d matches, want 2: %+v", len(got), got)
+	}
+	first := got[0]
+	if first.ID != "1" || !first.Online || !first.LastSeen.Equal(at) || first.Score != 1 || first.Name != "Sniper" {
+		t.Errorf("recorded player = %+v, want online, seen now, keeping the exact name", first)
+	}
+	if len(first.Aliases) != 2 || first.Aliases[0].Name != "Sn1per_TTV" {
+		t.Errorf("recorded player aliases = %+v, want the live name first", first.Aliases)
+	}
+	if second := got[1]; second.ID != "2" || !second.Online || second.Name != "xX_Sniper_Xx" {
+		t.Errorf("live-only player = %+v", second)
+	}
+}
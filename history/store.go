@@ -0,0 +1,483 @@
+// Package history keeps a persistent record of every player seen on the connected
+// servers, built from player list snapshots and the admin log.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sledro/hllrcon/logparse"
+	"github.com/Sledro/hllrcon/rcon"
+	bolt "go.etcd.io/bbolt"
+)
+
+var ErrNotFound = errors.New("player not found")
+
+// Limits on the per-player lists; older items are dropped first
+const (
+	maxSessions  = 200
+	maxSanctions = 200
+	maxChat      = 100
+)
+
+var (
+	playersBucket = []byte("players") // Player ID -> Record
+	namesBucket   = []byte("names")   // Lowercased name -> player ID last seen using it
+	onlineBucket  = []byte("online")  // Server -> nested bucket of player ID -> session start
+	marksBucket   = []byte("marks")   // Server -> logMark
+	aliasesBucket = []byte("aliases") // Player ID -> aliasEntry, the subset of a record name search reads
+)
+
+// Record is everything known about one player
+type Record struct {
+	ID              string     `json:"id"`
+	Platform        string     `json:"platform,omitempty"`
+	EOSID           string     `json:"eos_id,omitempty"`
+	FirstSeen       time.Time  `json:"first_seen"`
+	LastSeen        time.Time  `json:"last_seen"`
+	Names           []Name     `json:"names"`
+	Sessions        []Session  `json:"sessions"` // Most recent last
+	SessionCount    int        `json:"session_count"`
+	PlaytimeSeconds int64      `json:"playtime_seconds"` // Sum of closed sessions
+	Kills           int        `json:"kills"`
+	Deaths          int        `json:"deaths"`
+	TeamKills       int        `json:"team_kills"`
+	Sanctions       []Sanction `json:"sanctions"`
+	Chat            []ChatLine `json:"chat,omitempty"` // Most recent last
+}
+
+// Name is a name a player has used
+type Name struct {
+	Name      string    `json:"name"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Session is one stay on a server; End is nil while the player is online
+type Session struct {
+	Server          string     `json:"server"`
+	Start           time.Time  `json:"start"`
+	End             *time.Time `json:"end,omitempty"`
+	DurationSeconds int64      `json:"duration_seconds,omitempty"`
+}
+
+// Sanction is a kick or ban taken from the admin log
+type Sanction struct {
+	Type   logparse.Type `json:"type"`
+	Reason string        `json:"reason"`
+	Server string        `json:"server"`
+	Time   time.Time     `json:"time"`
+}
+
+// ChatLine is a chat message sent by the player
+type ChatLine struct {
+	Server  string    `json:"server"`
+	Channel string    `json:"channel"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// aliasEntry is the name history of a player, kept apart from the full record so
+// searches do not decode every session and chat line
+type aliasEntry struct {
+	Names    []Name    `json:"names"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// logMark is how far a server's admin log has been ingested: the time of the newest
+// line and the lines seen at that second, so replayed lines are skipped
+type logMark struct {
+	Unix  int64    `json:"unix"`
+	Lines []string `json:"lines"`
+}
+
+// Store is a bbolt database of player records
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens or creates the history database at path
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{playersBucket, namesBucket, onlineBucket, marksBucket, aliasesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise history database: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// putAliases writes the alias entry of rec
+func putAliases(aliases *bolt.Bucket, rec *Record) error {
+	data, err := json.Marshal(aliasEntry{Names: rec.Names, LastSeen: rec.LastSeen})
+	if err != nil {
+		return err
+	}
+	return aliases.Put([]byte(rec.ID), data)
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Get returns the record of a player
+func (s *Store) Get(id string) (Record, error) {
+	var rec Record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(playersBucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &rec)
+	})
+	return rec, err
+}
+
+// ObservePlayers records a player list snapshot of server taken at at. Players without
+// an open session get one, and open sessions of players no longer listed are closed,
+// covering connects and disconnects missing from the log.
+func (s *Store) ObservePlayers(server string, at time.Time, players []rcon.Player) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		u, err := newUpdate(tx, server)
+		if err != nil {
+			return err
+		}
+		listed := make(map[string]bool, len(players))
+		for _, p := range players {
+			if p.ID == "" {
+				continue
+			}
+			listed[p.ID] = true
+			rec, err := u.record(p.ID)
+			if err != nil {
+				return err
+			}
+			if p.Platform != "" {
+				rec.Platform = p.Platform
+			}
+			if p.EOSID != "" {
+				rec.EOSID = p.EOSID
+			}
+			u.seen(rec, p.Name, at)
+			if err := u.openSession(rec, at); err != nil {
+				return err
+			}
+		}
+
+		var gone []string
+		u.online.ForEach(func(k, _ []byte) error {
+			if !listed[string(k)] {
+				gone = append(gone, string(k))
+			}
+			return nil
+		})
+		for _, id := range gone {
+			rec, err := u.record(id)
+			if err != nil {
+				return err
+			}
+			if err := u.closeSession(rec, rec.LastSeen); err != nil {
+				return err
+			}
+		}
+		return u.save()
+	})
+}
+
+// IngestLog applies admin log entries of server, skipping lines already ingested. It
+// returns how many entries were new.
+func (s *Store) IngestLog(server string, entries []rcon.LogEntry) (int, error) {
+	ingested := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		u, err := newUpdate(tx, server)
+		if err != nil {
+			return err
+		}
+		var mark logMark
+		if data := tx.Bucket(marksBucket).Get([]byte(server)); data != nil {
+			if err := json.Unmarshal(data, &mark); err != nil {
+				return err
+			}
+		}
+
+		for _, entry := range entries {
+			event := logparse.ParseEntry(entry)
+			at := eventTime(entry, event)
+			unix := at.Unix()
+			switch {
+			case unix < mark.Unix:
+				continue
+			case unix == mark.Unix:
+				if slices.Contains(mark.Lines, entry.Message) {
+					continue
+				}
+				mark.Lines = append(mark.Lines, entry.Message)
+			default:
+				mark = logMark{Unix: unix, Lines: []string{entry.Message}}
+			}
+			if err := u.apply(event, at); err != nil {
+				return err
+			}
+			ingested++
+		}
+
+		data, err := json.Marshal(mark)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(marksBucket).Put([]byte(server), data); err != nil {
+			return err
+		}
+		return u.save()
+	})
+	return ingested, err
+}
+
+// eventTime is when a log line was written: the Unix time in its prefix, else its
+// entry timestamp, else now
+func eventTime(entry rcon.LogEntry, event logparse.Event) time.Time {
+	if base, ok := eventBase(event); ok && base.UnixTime > 0 {
+		return time.Unix(base.UnixTime, 0).UTC()
+	}
+	if t, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err == nil {
+		return t.UTC()
+	}
+	return time.Now().UTC()
+}
+
+// eventBase returns the common fields of the events history uses
+func eventBase(event logparse.Event) (logparse.Base, bool) {
+	switch e := event.(type) {
+	case logparse.Kill:
+		return e.Base, true
+	case logparse.Chat:
+		return e.Base, true
+	case logparse.Connection:
+		return e.Base, true
+	case logparse.Sanction:
+		return e.Base, true
+	}
+	return logparse.Base{}, false
+}
+
+// update batches record changes within one transaction
+type update struct {
+	tx      *bolt.Tx
+	server  string
+	online  *bolt.Bucket
+	records map[string]*Record
+	names   map[string]string // Lowercased name -> player ID
+}
+
+func newUpdate(tx *bolt.Tx, server string) (*update, error) {
+	online, err := tx.Bucket(onlineBucket).CreateBucketIfNotExists([]byte(server))
+	if err != nil {
+		return nil, err
+	}
+	return &update{tx: tx, server: server, online: online, records: make(map[string]*Record), names: make(map[string]string)}, nil
+}
+
+// record loads a player, creating the record on first sight
+func (u *update) record(id string) (*Record, error) {
+	if rec, ok := u.records[id]; ok {
+		return rec, nil
+	}
+	rec := &Record{ID: id}
+	if data := u.tx.Bucket(playersBucket).Get([]byte(id)); data != nil {
+		if err := json.Unmarshal(data, rec); err != nil {
+			return nil, fmt.Errorf("corrupt record for %s: %w", id, err)
+		}
+	}
+	u.records[id] = rec
+	return rec, nil
+}
+
+// resolve finds the record of a log player, by ID or else by the last ID seen using the name
+func (u *update) resolve(p logparse.Player) (*Record, bool, error) {
+	id := p.ID
+	if id == "" {
+		id = u.names[strings.ToLower(p.Name)]
+	}
+	if id == "" {
+		id = string(u.tx.Bucket(namesBucket).Get([]byte(strings.ToLower(p.Name))))
+	}
+	if id == "" {
+		return nil, false, nil
+	}
+	rec, err := u.record(id)
+	return rec, err == nil, err
+}
+
+// seen updates first/last seen and the name history
+func (u *update) seen(rec *Record, name string, at time.Time) {
+	if rec.FirstSeen.IsZero() || at.Before(rec.FirstSeen) {
+		rec.FirstSeen = at
+	}
+	if at.After(rec.LastSeen) {
+		rec.LastSeen = at
+	}
+	if name == "" {
+		return
+	}
+	u.names[strings.ToLower(name)] = rec.ID
+	for i := range rec.Names {
+		if rec.Names[i].Name == name {
+			if at.Before(rec.Names[i].FirstSeen) {
+				rec.Names[i].FirstSeen = at
+			}
+			if at.After(rec.Names[i].LastSeen) {
+				rec.Names[i].LastSeen = at
+			}
+			return
+		}
+	}
+	rec.Names = append(rec.Names, Name{Name: name, FirstSeen: at, LastSeen: at})
+}
+
+// openSession starts a session on the update's server unless one is open. An open
+// session that started after at, e.g. from a snapshot, is moved back to at.
+func (u *update) openSession(rec *Record, at time.Time) error {
+	if u.online.Get([]byte(rec.ID)) != nil {
+		if sess := u.openSessionOf(rec); sess != nil && at.Before(sess.Start) {
+			sess.Start = at
+		}
+		return nil
+	}
+	rec.Sessions = append(rec.Sessions, Session{Server: u.server, Start: at})
+	rec.SessionCount++
+	if len(rec.Sessions) > maxSessions {
+		rec.Sessions = rec.Sessions[len(rec.Sessions)-maxSessions:]
+	}
+	return u.online.Put([]byte(rec.ID), []byte(at.Format(time.RFC3339)))
+}
+
+// closeSession ends the player's open session on the update's server at at. A
+// disconnect from before the session started belongs to an earlier one and is ignored.
+func (u *update) closeSession(rec *Record, at time.Time) error {
+	if u.online.Get([]byte(rec.ID)) == nil {
+		return nil
+	}
+	if sess := u.openSessionOf(rec); sess != nil {
+		if at.Before(sess.Start) {
+			return nil
+		}
+		end := at
+		sess.End = &end
+		sess.DurationSeconds = int64(end.Sub(sess.Start).Seconds())
+		rec.PlaytimeSeconds += sess.DurationSeconds
+	}
+	return u.online.Delete([]byte(rec.ID))
+}
+
+// openSessionOf returns the player's open session on the update's server
+func (u *update) openSessionOf(rec *Record) *Session {
+	for i := len(rec.Sessions) - 1; i >= 0; i-- {
+		if rec.Sessions[i].Server == u.server && rec.Sessions[i].End == nil {
+			return &rec.Sessions[i]
+		}
+	}
+	return nil
+}
+
+// apply updates records from one log event
+func (u *update) apply(event logparse.Event, at time.Time) error {
+	switch e := event.(type) {
+	case logparse.Connection:
+		rec, ok, err := u.resolve(e.Player)
+		if !ok {
+			return err
+		}
+		u.seen(rec, e.Player.Name, at)
+		if e.Type == logparse.TypeConnected {
+			return u.openSession(rec, at)
+		}
+		return u.closeSession(rec, at)
+
+	case logparse.Kill:
+		killer, ok, err := u.resolve(e.Killer)
+		if err != nil {
+			return err
+		}
+		if ok {
+			u.seen(killer, e.Killer.Name, at)
+			if e.Type == logparse.TypeTeamKill {
+				killer.TeamKills++
+			} else {
+				killer.Kills++
+			}
+		}
+		victim, ok, err := u.resolve(e.Victim)
+		if ok {
+			u.seen(victim, e.Victim.Name, at)
+			victim.Deaths++
+		}
+		return err
+
+	case logparse.Chat:
+		rec, ok, err := u.resolve(e.Player)
+		if !ok {
+			return err
+		}
+		u.seen(rec, e.Player.Name, at)
+		rec.Chat = append(rec.Chat, ChatLine{Server: u.server, Channel: e.Channel, Message: e.Message, Time: at})
+		if len(rec.Chat) > maxChat {
+			rec.Chat = rec.Chat[len(rec.Chat)-maxChat:]
+		}
+
+	case logparse.Sanction:
+		rec, ok, err := u.resolve(e.Player)
+		if !ok {
+			return err
+		}
+		rec.Sanctions = append(rec.Sanctions, Sanction{Type: e.Type, Reason: e.Reason, Server: u.server, Time: at})
+		if len(rec.Sanctions) > maxSanctions {
+			rec.Sanctions = rec.Sanctions[len(rec.Sanctions)-maxSanctions:]
+		}
+	}
+	return nil
+}
+
+// save writes every touched record and name
+func (u *update) save() error {
+	names := u.tx.Bucket(namesBucket)
+	for name, id := range u.names {
+		if err := names.Put([]byte(name), []byte(id)); err != nil {
+			return err
+		}
+	}
+	players := u.tx.Bucket(playersBucket)
+	aliases := u.tx.Bucket(aliasesBucket)
+	for id, rec := range u.records {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		if err := players.Put([]byte(id), data); err != nil {
+			return err
+		}
+		if err := putAliases(aliases, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
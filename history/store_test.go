@@ -0,0 +1,160 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+const testServer = "10.0.0.1:7779"
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func getRecord(t *testing.T, s *Store, id string) Record {
+	t.Helper()
+	rec, err := s.Get(id)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", id, err)
+	}
+	return rec
+}
+
+// logLine is an admin log entry carrying the Unix time prefix the server writes
+func logLine(at time.Time, message string) rcon.LogEntry {
+	return rcon.LogEntry{
+		Timestamp: at.Format(time.RFC3339),
+		Message:   fmt.Sprintf("[0:00 min (%d)] %s", at.Unix(), message),
+	}
+}
+
+func TestObservePlayersSessions(t *testing.T) {
+	s := openTestStore(t)
+	t0 := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	able := rcon.Player{Name: "Able", ID: "1", Platform: "steam"}
+	baker := rcon.Player{Name: "Baker", ID: "2"}
+
+	steps := []struct {
+		at      time.Time
+		players []rcon.Player
+	}{
+		{t0, []rcon.Player{able, baker}},
+		{t0.Add(time.Minute), []rcon.Player{able, baker}},
+		{t0.Add(2 * time.Minute), []rcon.Player{able}},
+		{t0.Add(3 * time.Minute), []rcon.Player{able, baker}},
+	}
+	for _, step := range steps {
+		if err := s.ObservePlayers(testServer, step.at, step.players); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := getRecord(t, s, "1")
+	if rec.SessionCount != 1 || rec.Sessions[0].End != nil || !rec.Sessions[0].Start.Equal(t0) {
+		t.Errorf("Able sessions = %+v, want one open since %v", rec.Sessions, t0)
+	}
+	if rec.Platform != "steam" || !rec.LastSeen.Equal(t0.Add(3*time.Minute)) {
+		t.Errorf("Able = %+v", rec)
+	}
+
+	rec = getRecord(t, s, "2")
+	if rec.SessionCount != 2 {
+		t.Fatalf("Baker has %d sessions, want 2", rec.SessionCount)
+	}
+	first := rec.Sessions[0]
+	if first.End == nil || !first.End.Equal(t0.Add(time.Minute)) || first.DurationSeconds != 60 {
+		t.Errorf("Baker's first session = %+v, want closed at the last snapshot listing him", first)
+	}
+	if rec.PlaytimeSeconds != 60 || rec.Sessions[1].End != nil {
+		t.Errorf("Baker = %+v", rec)
+	}
+}
+
+func TestIngestLogWatermark(t *testing.T) {
+	s := openTestStore(t)
+	t0 := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	window := []rcon.LogEntry{
+		logLine(t0, "CONNECTED Able (1)"),
+		logLine(t0.Add(time.Second), "CONNECTED Baker (2)"),
+		logLine(t0.Add(time.Second), "CHAT[Team][Able(Allies/1)]: hello"),
+	}
+
+	ingest := func(entries []rcon.LogEntry, want int) {
+		t.Helper()
+		n, err := s.IngestLog(testServer, entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Errorf("ingested %d entries, want %d", n, want)
+		}
+	}
+	ingest(window, 3)
+	ingest(window, 0)
+
+	// The next poll overlaps the last second and adds a line written in it
+	next := append(window[1:], logLine(t0.Add(time.Second), "CHAT[Team][Baker(Axis/2)]: hi"), logLine(t0.Add(2*time.Second), "CHAT[Team][Able(Allies/1)]: again"))
+	ingest(next, 2)
+
+	// Lines older than the watermark are never applied again
+	ingest([]rcon.LogEntry{logLine(t0, "CHAT[Team][Able(Allies/1)]: late")}, 0)
+
+	if rec := getRecord(t, s, "1"); len(rec.Chat) != 2 || rec.SessionCount != 1 {
+		t.Errorf("Able chat = %+v, sessions = %d", rec.Chat, rec.SessionCount)
+	}
+	if rec := getRecord(t, s, "2"); len(rec.Chat) != 1 {
+		t.Errorf("Baker chat = %+v", rec.Chat)
+	}
+}
+
+func TestIngestLogCounts(t *testing.T) {
+	s := openTestStore(t)
+	t0 := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	_, err := s.IngestLog(testServer, []rcon.LogEntry{
+		logLine(t0, "CONNECTED Able (1)"),
+		logLine(t0, "CONNECTED Baker (2)"),
+		logLine(t0, "CONNECTED Charlie (3)"),
+		logLine(t0.Add(1*time.Second), "KILL: Able(Allies/1) -> Baker(Axis/2) with M1 GARAND"),
+		logLine(t0.Add(2*time.Second), "KILL: Able(Allies/1) -> Baker(Axis/2) with M1 GARAND"),
+		logLine(t0.Add(3*time.Second), "KILL: Baker(Axis/2) -> Able(Allies/1) with MP40"),
+		logLine(t0.Add(4*time.Second), "TEAM KILL: Charlie(Allies/3) -> Able(Allies/1) with MK2 GRENADE"),
+		logLine(t0.Add(5*time.Second), "KICK: [Charlie] has been kicked. [KICKED FOR TEAM KILLING!]"),
+		logLine(t0.Add(5*time.Second), "DISCONNECTED Charlie (3)"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id                       string
+		kills, deaths, teamKills int
+	}{
+		{"1", 2, 2, 0},
+		{"2", 1, 2, 0},
+		{"3", 0, 0, 1},
+	}
+	for _, tt := range tests {
+		rec := getRecord(t, s, tt.id)
+		if rec.Kills != tt.kills || rec.Deaths != tt.deaths || rec.TeamKills != tt.teamKills {
+			t.Errorf("player %s K/D/TK = %d/%d/%d, want %d/%d/%d", tt.id, rec.Kills, rec.Deaths, rec.TeamKills, tt.kills, tt.deaths, tt.teamKills)
+		}
+	}
+
+	charlie := getRecord(t, s, "3")
+	if len(charlie.Sanctions) != 1 || charlie.Sanctions[0].Reason != "KICKED FOR TEAM KILLING!" {
+		t.Errorf("Charlie sanctions = %+v", charlie.Sanctions)
+	}
+	if charlie.PlaytimeSeconds != 5 || charlie.Sessions[0].End == nil {
+		t.Errorf("Charlie sessions = %+v", charlie.Sessions)
+	}
+}
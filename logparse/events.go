@@ -0,0 +1,189 @@
+// Package logparse turns raw GetAdminLog lines into typed game events.
+package logparse
+
+import "strings"
+
+// Type identifies the kind of a log event
+type Type string
+
+const (
+	TypeKill         Type = "kill"
+	TypeTeamKill     Type = "team_kill"
+	TypeChat         Type = "chat"
+	TypeConnected    Type = "connected"
+	TypeDisconnected Type = "disconnected"
+	TypeTeamSwitch   Type = "team_switch"
+	TypeMatchStart   Type = "match_start"
+	TypeMatchEnded   Type = "match_ended"
+	TypeVote         Type = "vote"
+	TypeBan          Type = "ban"
+	TypeKick         Type = "kick"
+	TypeMessage      Type = "message"
+	TypeCamera       Type = "camera"
+	TypeUnknown      Type = "unknown"
+)
+
+// Types lists every event type the parser produces
+var Types = []Type{
+	TypeKill, TypeTeamKill, TypeChat, TypeConnected, TypeDisconnected, TypeTeamSwitch,
+	TypeMatchStart, TypeMatchEnded, TypeVote, TypeBan, TypeKick, TypeMessage, TypeCamera,
+	TypeUnknown,
+}
+
+// Event is implemented by every parsed event
+type Event interface {
+	EventType() Type
+	// Players returns everyone the event refers to
+	Players() []Player
+}
+
+// Player identifies a player as far as the log line does. ID and Team are empty
+// when the line only carries a name.
+type Player struct {
+	Name string `json:"name"`
+	ID   string `json:"id,omitempty"`
+	Team string `json:"team,omitempty"`
+}
+
+// Matches reports whether query equals the player's ID or, case-insensitively, name
+func (p Player) Matches(query string) bool {
+	return (p.ID != "" && p.ID == query) || strings.EqualFold(p.Name, query)
+}
+
+// Base carries the fields common to every event
+type Base struct {
+	Type      Type   `json:"type"`
+	Timestamp string `json:"timestamp"`
+	UnixTime  int64  `json:"unix_time,omitempty"` // From the line prefix, when present
+	Raw       string `json:"raw"`
+}
+
+func (b Base) EventType() Type { return b.Type }
+
+// Unknown is a line no parser recognised
+type Unknown struct {
+	Base
+}
+
+func (Unknown) Players() []Player { return nil }
+
+// Kill is a KILL or TEAM KILL line
+type Kill struct {
+	Base
+	Killer Player `json:"killer"`
+	Victim Player `json:"victim"`
+	Weapon string `json:"weapon"`
+}
+
+func (e Kill) Players() []Player { return []Player{e.Killer, e.Victim} }
+
+// Chat is a CHAT line
+type Chat struct {
+	Base
+	Player  Player `json:"player"`
+	Channel string `json:"channel"` // Team or Unit
+	Message string `json:"message"`
+}
+
+func (e Chat) Players() []Player { return []Player{e.Player} }
+
+// Connection is a CONNECTED or DISCONNECTED line
+type Connection struct {
+	Base
+	Player Player `json:"player"`
+}
+
+func (e Connection) Players() []Player { return []Player{e.Player} }
+
+// TeamSwitch is a TEAMSWITCH line
+type TeamSwitch struct {
+	Base
+	Player Player `json:"player"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+func (e TeamSwitch) Players() []Player { return []Player{e.Player} }
+
+// MatchStart is a MATCH START line
+type MatchStart struct {
+	Base
+	Map      string `json:"map"`
+	GameMode string `json:"game_mode"`
+}
+
+func (MatchStart) Players() []Player { return nil }
+
+// MatchEnded is a MATCH ENDED line
+type MatchEnded struct {
+	Base
+	Map         string `json:"map"`
+	GameMode    string `json:"game_mode"`
+	AlliedScore int    `json:"allied_score"`
+	AxisScore   int    `json:"axis_score"`
+}
+
+func (MatchEnded) Players() []Player { return nil }
+
+// Vote actions
+const (
+	VoteStarted   = "started"
+	VoteCast      = "cast"
+	VoteCompleted = "completed"
+	VotePassed    = "passed"
+	VoteExpired   = "expired"
+	VoteOther     = "other"
+)
+
+// Vote is a VOTESYS line. Which fields are set depends on Action.
+type Vote struct {
+	Base
+	Action       string `json:"action"`
+	VoteID       string `json:"vote_id,omitempty"`
+	Initiator    string `json:"initiator,omitempty"` // Player who started or cast the vote
+	Target       string `json:"target,omitempty"`
+	VoteType     string `json:"vote_type,omitempty"` // e.g. PVR_Kick_Abuse
+	Choice       string `json:"choice,omitempty"`    // e.g. PV_Favour
+	Result       string `json:"result,omitempty"`    // e.g. PVR_Passed
+	VotesFor     int    `json:"votes_for,omitempty"`
+	VotesNeeded  int    `json:"votes_needed,omitempty"`
+	VotesAgainst int    `json:"votes_against,omitempty"`
+}
+
+func (e Vote) Players() []Player {
+	var players []Player
+	if e.Initiator != "" {
+		players = append(players, Player{Name: e.Initiator})
+	}
+	if e.Target != "" {
+		players = append(players, Player{Name: e.Target})
+	}
+	return players
+}
+
+// Sanction is a BAN or KICK line
+type Sanction struct {
+	Base
+	Player Player `json:"player"`
+	Reason string `json:"reason"`
+}
+
+func (e Sanction) Players() []Player { return []Player{e.Player} }
+
+// Message is a MESSAGE line recording an admin message sent to a player
+type Message struct {
+	Base
+	Player  Player `json:"player"`
+	Content string `json:"content"`
+}
+
+func (e Message) Players() []Player { return []Player{e.Player} }
+
+// Camera is an admin entering or leaving the admin camera
+type Camera struct {
+	Base
+	Player  Player `json:"player"`
+	Entered bool   `json:"entered"`
+}
+
+func (e Camera) Players() []Player { return []Player{e.Player} }
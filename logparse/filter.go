@@ -0,0 +1,57 @@
+package logparse
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Filter selects events by type and involved player. Zero values match everything.
+type Filter struct {
+	Types  []Type
+	Player string // Player ID or name
+}
+
+// ParseTypes parses a comma-separated list of event types
+func ParseTypes(s string) ([]Type, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var types []Type
+	for _, name := range strings.Split(s, ",") {
+		t := Type(strings.ToLower(strings.TrimSpace(name)))
+		if !slices.Contains(Types, t) {
+			return nil, fmt.Errorf("unknown event type %q", name)
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+// Match reports whether e passes the filter
+func (f Filter) Match(e Event) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, e.EventType()) {
+		return false
+	}
+	if f.Player == "" {
+		return true
+	}
+	for _, p := range e.Players() {
+		if p.Matches(f.Player) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the events that pass the filter
+func (f Filter) Apply(events []Event) []Event {
+	matched := make([]Event, 0, len(events))
+	for _, e := range events {
+		if f.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
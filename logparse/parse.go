@@ -0,0 +1,156 @@
+package logparse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+var (
+	// Optional "[1:23 min (1700000000)] " prefix carried by some log lines
+	prefixRe = regexp.MustCompile(`^\[[^\]]*?\((\d+)\)\]\s*`)
+
+	killRe       = regexp.MustCompile(`^(TEAM KILL|KILL): (.+)\((Allies|Axis|None)/([^)]*)\) -> (.+)\((Allies|Axis|None)/([^)]*)\) with (.+)$`)
+	chatRe       = regexp.MustCompile(`(?s)^CHAT\[(\w+)\]\[(.+)\((Allies|Axis|None)/([^)]*)\)\]: (.*)$`)
+	connectionRe = regexp.MustCompile(`^(CONNECTED|DISCONNECTED) (.+) \(([^)]*)\)$`)
+	teamSwitchRe = regexp.MustCompile(`^TEAMSWITCH (.+) \((\w+) > (\w+)\)$`)
+	matchStartRe = regexp.MustCompile(`^MATCH START (.+) (\S+)$`)
+	matchEndRe   = regexp.MustCompile("^MATCH ENDED `(.+) (\\S+)` ALLIED \\((\\d+) - (\\d+)\\) AXIS$")
+	banRe        = regexp.MustCompile(`(?s)^BAN: \[(.+?)\] has been banned\. \[(.*)\]$`)
+	kickRe       = regexp.MustCompile(`(?s)^KICK: \[(.+?)\] has been kicked\. \[(.*)\]$`)
+	messageRe    = regexp.MustCompile(`(?s)^MESSAGE: player \[(.+)\(([^)]*)\)\], content \[(.*)\]$`)
+	cameraRe     = regexp.MustCompile(`^Player \[(.+) \(([^)]*)\)\] (Entered|Left) Admin Camera$`)
+
+	voteStartedRe   = regexp.MustCompile(`^VOTESYS: Player \[(.+)\] Started a vote of type \((\w+)\) against \[(.+)\]\. VoteID: \[(\d+)\]$`)
+	voteCastRe      = regexp.MustCompile(`^VOTESYS: Player \[(.+)\] voted \[(\w+)\] for VoteID\[(\d+)\]$`)
+	voteCompletedRe = regexp.MustCompile(`^VOTESYS: Vote \[(\d+)\] completed\. Result: (\w+)$`)
+	votePassedRe    = regexp.MustCompile(`^VOTESYS: Vote Kick \{(.+)\} successfully passed\. \[For: (\d+)/(\d+) - Against: (\d+)\]$`)
+	voteExpiredRe   = regexp.MustCompile(`^VOTESYS: Vote \[(\d+)\] expired before completion\.$`)
+)
+
+// ParseEntry parses an admin log entry
+func ParseEntry(entry rcon.LogEntry) Event {
+	return Parse(entry.Timestamp, entry.Message)
+}
+
+// ParseEntries parses admin log entries in order
+func ParseEntries(entries []rcon.LogEntry) []Event {
+	events := make([]Event, 0, len(entries))
+	for _, entry := range entries {
+		events = append(events, ParseEntry(entry))
+	}
+	return events
+}
+
+// Parse turns a single log line into an event. Lines that match no known format
+// come back as Unknown rather than an error, so new server messages never break
+// a stream.
+func Parse(timestamp, line string) Event {
+	base := Base{Timestamp: timestamp, Raw: line}
+	msg := strings.TrimSpace(line)
+	if m := prefixRe.FindStringSubmatch(msg); m != nil {
+		base.UnixTime, _ = strconv.ParseInt(m[1], 10, 64)
+		msg = msg[len(m[0]):]
+	}
+
+	switch {
+	case strings.HasPrefix(msg, "KILL: "), strings.HasPrefix(msg, "TEAM KILL: "):
+		if m := killRe.FindStringSubmatch(msg); m != nil {
+			base.Type = TypeKill
+			if m[1] == "TEAM KILL" {
+				base.Type = TypeTeamKill
+			}
+			return Kill{
+				Base:   base,
+				Killer: Player{Name: m[2], Team: m[3], ID: m[4]},
+				Victim: Player{Name: m[5], Team: m[6], ID: m[7]},
+				Weapon: m[8],
+			}
+		}
+	case strings.HasPrefix(msg, "CHAT["):
+		if m := chatRe.FindStringSubmatch(msg); m != nil {
+			base.Type = TypeChat
+			return Chat{
+				Base:    base,
+				Channel: m[1],
+				Player:  Player{Name: m[2], Team: m[3], ID: m[4]},
+				Message: m[5],
+			}
+		}
+	case strings.HasPrefix(msg, "CONNECTED "), strings.HasPrefix(msg, "DISCONNECTED "):
+		if m := connectionRe.FindStringSubmatch(msg); m != nil {
+			base.Type = TypeConnected
+			if m[1] == "DISCONNECTED" {
+				base.Type = TypeDisconnected
+			}
+			return Connection{Base: base, Player: Player{Name: m[2], ID: m[3]}}
+		}
+	case strings.HasPrefix(msg, "TEAMSWITCH "):
+		if m := teamSwitchRe.FindStringSubmatch(msg); m != nil {
+			base.Type = TypeTeamSwitch
+			return TeamSwitch{Base: base, Player: Player{Name: m[1]}, From: m[2], To: m[3]}
+		}
+	case strings.HasPrefix(msg, "MATCH START "):
+		if m := matchStartRe.FindStringSubmatch(msg); m != nil {
+			base.Type = TypeMatchStart
+			return MatchStart{Base: base, Map: m[1], GameMode: m[2]}
+		}
+	case strings.HasPrefix(msg, "MATCH ENDED "):
+		if m := matchEndRe.FindStringSubmatch(msg); m != nil {
+			base.Type = TypeMatchEnded
+			allied, _ := strconv.Atoi(m[3])
+			axis, _ := strconv.Atoi(m[4])
+			return MatchEnded{Base: base, Map: m[1], GameMode: m[2], AlliedScore: allied, AxisScore: axis}
+		}
+	case strings.HasPrefix(msg, "VOTESYS: "):
+		base.Type = TypeVote
+		return parseVote(base, msg)
+	case strings.HasPrefix(msg, "BAN: "):
+		if m := banRe.FindStringSubmatch(msg); m != nil {
+			base.Type = TypeBan
+			return Sanction{Base: base, Player: Player{Name: m[1]}, Reason: m[2]}
+		}
+	case strings.HasPrefix(msg, "KICK: "):
+		if m := kickRe.FindStringSubmatch(msg); m != nil {
+			base.Type = TypeKick
+			return Sanction{Base: base, Player: Player{Name: m[1]}, Reason: m[2]}
+		}
+	case strings.HasPrefix(msg, "MESSAGE: "):
+		if m := messageRe.FindStringSubmatch(msg); m != nil {
+			base.Type = TypeMessage
+			return Message{Base: base, Player: Player{Name: m[1], ID: m[2]}, Content: m[3]}
+		}
+	case strings.HasPrefix(msg, "Player ["):
+		if m := cameraRe.FindStringSubmatch(msg); m != nil {
+			base.Type = TypeCamera
+			return Camera{Base: base, Player: Player{Name: m[1], ID: m[2]}, Entered: m[3] == "Entered"}
+		}
+	}
+
+	base.Type = TypeUnknown
+	return Unknown{Base: base}
+}
+
+func parseVote(base Base, msg string) Vote {
+	if m := voteStartedRe.FindStringSubmatch(msg); m != nil {
+		return Vote{Base: base, Action: VoteStarted, Initiator: m[1], VoteType: m[2], Target: m[3], VoteID: m[4]}
+	}
+	if m := voteCastRe.FindStringSubmatch(msg); m != nil {
+		return Vote{Base: base, Action: VoteCast, Initiator: m[1], Choice: m[2], VoteID: m[3]}
+	}
+	if m := voteCompletedRe.FindStringSubmatch(msg); m != nil {
+		return Vote{Base: base, Action: VoteCompleted, VoteID: m[1], Result: m[2]}
+	}
+	if m := votePassedRe.FindStringSubmatch(msg); m != nil {
+		votesFor, _ := strconv.Atoi(m[2])
+		needed, _ := strconv.Atoi(m[3])
+		against, _ := strconv.Atoi(m[4])
+		return Vote{Base: base, Action: VotePassed, Target: m[1], VotesFor: votesFor, VotesNeeded: needed, VotesAgainst: against}
+	}
+	if m := voteExpiredRe.FindStringSubmatch(msg); m != nil {
+		return Vote{Base: base, Action: VoteExpired, VoteID: m[1]}
+	}
+	return Vote{Base: base, Action: VoteOther}
+}
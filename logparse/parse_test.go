@@ -0,0 +1,99 @@
+package logparse
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/*.golden.json from the parser output")
+
+// parseFile parses every line of a testdata log
+func parseFile(t *testing.T, path string) []Event {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		events = append(events, Parse("2026-10-17T20:00:00Z", scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestParseGolden(t *testing.T) {
+	logs, err := filepath.Glob(filepath.Join("testdata", "*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) == 0 {
+		t.Fatal("no testdata logs")
+	}
+
+	covered := make(map[Type]bool)
+	for _, path := range logs {
+		name := strings.TrimSuffix(filepath.Base(path), ".log")
+		t.Run(name, func(t *testing.T) {
+			events := parseFile(t, path)
+			for _, e := range events {
+				covered[e.EventType()] = true
+			}
+
+			got, err := json.MarshalIndent(events, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := strings.TrimSuffix(path, ".log") + ".golden.json"
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s does not match the parser output:\n%s", golden, got)
+			}
+		})
+	}
+
+	for _, typ := range Types {
+		if !covered[typ] {
+			t.Errorf("no testdata line parses as %s", typ)
+		}
+	}
+}
+
+func TestVoteActions(t *testing.T) {
+	events := parseFile(t, filepath.Join("testdata", "votes.log"))
+	want := []string{VoteStarted, VoteCast, VoteCompleted, VotePassed, VoteExpired, VoteOther}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		vote, ok := e.(Vote)
+		if !ok {
+			t.Fatalf("line %d parsed as %T, want Vote", i+1, e)
+		}
+		if vote.Action != want[i] {
+			t.Errorf("line %d action = %s, want %s", i+1, vote.Action, want[i])
+		}
+	}
+}
@@ -0,0 +1,294 @@
+// Package logstream follows the admin log of game servers and fans new lines out
+// to subscribers with resumable cursors.
+package logstream
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+// Entry is a deduplicated admin log line with its stream cursor
+type Entry struct {
+	Cursor    string `json:"cursor"`
+	Timestamp string `json:"timestamp"`
+	Message   string `json:"message"`
+	seq       uint64
+	at        time.Time // Parsed Timestamp, zero if it is not RFC 3339
+	line      string    // Hash of timestamp and message
+}
+
+// Config controls polling and buffering
+type Config struct {
+	PollInterval    time.Duration // How often GetAdminLog is polled
+	InitialBacklog  time.Duration // How far back the first poll reaches
+	BufferSize      int           // Entries kept for resuming subscribers
+	SubscriberQueue int           // Per-subscriber queue before it is dropped as too slow
+	IdleShutdown    time.Duration // Stop following once unsubscribed for this long
+}
+
+// Follower polls one server's admin log and publishes new entries
+type Follower struct {
+	server  string
+	client  *rcon.Client
+	release func()
+	cfg     Config
+	epoch   string // Distinguishes cursors from earlier followers of the same server
+
+	mu       sync.Mutex
+	seq      uint64
+	buffer   []Entry        // Ring of the most recent entries, oldest first
+	seen     map[string]int // Occurrences of timestamp|message within the poll window
+	subs     map[*Subscription]struct{}
+	lastPoll time.Time
+	idleAt   time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newFollower(server string, client *rcon.Client, release func(), cfg Config) *Follower {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &Follower{
+		server:  server,
+		client:  client,
+		release: release,
+		cfg:     cfg,
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		seen:    make(map[string]int),
+		subs:    make(map[*Subscription]struct{}),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go f.run(ctx)
+	return f
+}
+
+func (f *Follower) run(ctx context.Context) {
+	defer close(f.done)
+	defer f.release()
+
+	ticker := time.NewTicker(f.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		f.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches the log window since the last poll and publishes unseen entries
+func (f *Follower) poll(ctx context.Context) {
+	now := time.Now()
+	backtrack := f.cfg.InitialBacklog
+	if !f.lastPoll.IsZero() {
+		// Overlap generously; duplicates are filtered below
+		backtrack = now.Sub(f.lastPoll) + 2*f.cfg.PollInterval
+	}
+
+	pollCtx, cancel := context.WithTimeout(ctx, f.cfg.PollInterval*5)
+	defer cancel()
+	entries, err := f.client.GetAdminLog(pollCtx, int(backtrack.Seconds())+1, "")
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Warn("Admin log poll failed", "server", f.server, "error", err)
+		}
+		return
+	}
+	f.lastPoll = now
+	f.publish(entries)
+}
+
+// publish appends entries not already seen and delivers them to subscribers. Lines
+// are identified by timestamp and content; identical lines within one window are
+// counted so genuine repeats still come through.
+func (f *Follower) publish(entries []rcon.LogEntry) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	window := make(map[string]int, len(entries))
+	for _, e := range entries {
+		key := e.Timestamp + "|" + e.Message
+		window[key]++
+		if window[key] <= f.seen[key] {
+			continue
+		}
+
+		f.seq++
+		entry := Entry{
+			Timestamp: e.Timestamp,
+			Message:   e.Message,
+			seq:       f.seq,
+			line:      lineHash(e),
+		}
+		entry.at, _ = time.Parse(time.RFC3339Nano, e.Timestamp)
+		entry.Cursor = f.cursor(entry)
+		f.buffer = append(f.buffer, entry)
+		if len(f.buffer) > f.cfg.BufferSize {
+			f.buffer = f.buffer[len(f.buffer)-f.cfg.BufferSize:]
+		}
+		for sub := range f.subs {
+			sub.deliver(entry)
+		}
+	}
+
+	// Only the latest window matters for deduplication; older lines fall out of it
+	f.seen = window
+}
+
+// cursor is "epoch-seq-time-line": the follower and position of an entry, plus its log
+// time and line hash so a later follower of the same server can find the position again
+func (f *Follower) cursor(e Entry) string {
+	var at int64
+	if !e.at.IsZero() {
+		at = e.at.UnixNano()
+	}
+	return fmt.Sprintf("%s-%d-%s-%s", f.epoch, e.seq, strconv.FormatInt(at, 36), e.line)
+}
+
+// lineHash identifies a log line by timestamp and message
+func lineHash(e rcon.LogEntry) string {
+	sum := sha256.Sum256([]byte(e.Timestamp + "|" + e.Message))
+	return hex.EncodeToString(sum[:6])
+}
+
+// backlog returns buffered entries after cursor; without a cursor, everything
+// buffered. A cursor of this follower that fell out of the buffer replays everything
+// still buffered. A cursor from an earlier follower resumes after the same line if it
+// is buffered, else after the cursor's log time, and replays nothing if neither is known.
+func (f *Follower) backlog(cursor string) []Entry {
+	if cursor == "" {
+		return append([]Entry(nil), f.buffer...)
+	}
+
+	parts := strings.Split(cursor, "-")
+	if parts[0] == f.epoch && len(parts) >= 2 {
+		after, _ := strconv.ParseUint(parts[1], 10, 64)
+		return f.since(func(e Entry) bool { return e.seq > after })
+	}
+	if len(parts) != 4 {
+		return nil
+	}
+
+	for i := len(f.buffer) - 1; i >= 0; i-- {
+		if f.buffer[i].line == parts[3] {
+			return append([]Entry(nil), f.buffer[i+1:]...)
+		}
+	}
+	nanos, err := strconv.ParseInt(parts[2], 36, 64)
+	if err != nil || nanos == 0 {
+		return nil
+	}
+	at := time.Unix(0, nanos)
+	return f.since(func(e Entry) bool { return e.at.After(at) })
+}
+
+// since returns the buffered entries from the first one matching
+func (f *Follower) since(match func(Entry) bool) []Entry {
+	for i, e := range f.buffer {
+		if match(e) {
+			return append([]Entry(nil), f.buffer[i:]...)
+		}
+	}
+	return nil
+}
+
+// subscribe registers a subscription, replaying entries after cursor first
+func (f *Follower) subscribe(cursor string) *Subscription {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	backlog := f.backlog(cursor)
+	sub := &Subscription{
+		follower: f,
+		entries:  make(chan Entry, max(f.cfg.SubscriberQueue, len(backlog))),
+		done:     make(chan struct{}),
+	}
+	for _, e := range backlog {
+		sub.entries <- e
+	}
+	f.subs[sub] = struct{}{}
+	return sub
+}
+
+func (f *Follower) unsubscribe(sub *Subscription) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.subs[sub]; ok {
+		delete(f.subs, sub)
+		close(sub.done)
+	}
+	if len(f.subs) == 0 {
+		f.idleAt = time.Now()
+	}
+}
+
+// idle reports whether nobody has been subscribed for longer than d
+func (f *Follower) idle(d time.Duration) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subs) == 0 && time.Since(f.idleAt) > d
+}
+
+func (f *Follower) stop() {
+	f.cancel()
+	<-f.done
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for sub := range f.subs {
+		delete(f.subs, sub)
+		close(sub.done)
+	}
+}
+
+// Subscription receives entries from a follower
+type Subscription struct {
+	follower *Follower
+	entries  chan Entry
+	done     chan struct{}
+}
+
+// Entries delivers new log entries in order
+func (s *Subscription) Entries() <-chan Entry {
+	return s.entries
+}
+
+// Done is closed when the subscription ends, either because it was closed, it fell
+// too far behind, or the follower stopped
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.follower.unsubscribe(s)
+}
+
+// deliver queues an entry, dropping the subscriber if it cannot keep up (caller
+// must hold the follower lock). The subscriber can resume from its last cursor.
+func (s *Subscription) deliver(e Entry) {
+	select {
+	case s.entries <- e:
+	default:
+		slog.Warn("Dropping slow log stream subscriber", "server", s.follower.server)
+		delete(s.follower.subs, s)
+		close(s.done)
+		if len(s.follower.subs) == 0 {
+			s.follower.idleAt = time.Now()
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package logstream
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+// testFollower builds a follower that is fed through publish instead of polling
+func testFollower(epoch string, bufferSize int) *Follower {
+	return &Follower{
+		epoch: epoch,
+		cfg:   Config{BufferSize: bufferSize},
+		seen:  make(map[string]int),
+		subs:  make(map[*Subscription]struct{}),
+	}
+}
+
+// logLines returns n entries one second apart starting at start
+func logLines(start time.Time, from, n int) []rcon.LogEntry {
+	entries := make([]rcon.LogEntry, n)
+	for i := range entries {
+		entries[i] = rcon.LogEntry{
+			Timestamp: start.Add(time.Duration(from+i) * time.Second).Format(time.RFC3339Nano),
+			Message:   fmt.Sprintf("line %d", from+i),
+		}
+	}
+	return entries
+}
+
+func messages(entries []Entry) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.Message
+	}
+	return out
+}
+
+func TestBacklog(t *testing.T) {
+	start := time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC)
+
+	old := testFollower("old", 100)
+	old.publish(logLines(start, 0, 5))
+	cursorAt := func(i int) string { return old.buffer[i].Cursor }
+
+	// The restarted follower re-read an overlapping window and then saw new lines
+	current := testFollower("new", 100)
+	current.publish(logLines(start, 2, 6))
+
+	// A follower whose buffer no longer holds the cursor's line
+	trimmed := testFollower("trimmed", 3)
+	trimmed.publish(logLines(start, 0, 8))
+
+	tests := []struct {
+		name   string
+		f      *Follower
+		cursor string
+		want   []string
+	}{
+		{"no cursor", current, "", []string{"line 2", "line 3", "line 4", "line 5", "line 6", "line 7"}},
+		{"own cursor", current, current.buffer[3].Cursor, []string{"line 6", "line 7"}},
+		{"own cursor fell out of buffer", trimmed, trimmed.cursor(Entry{seq: 1}), []string{"line 5", "line 6", "line 7"}},
+		{"earlier follower, line buffered", current, cursorAt(3), []string{"line 4", "line 5", "line 6", "line 7"}},
+		{"earlier follower, line not buffered", trimmed, cursorAt(4), []string{"line 5", "line 6", "line 7"}},
+		{"earlier follower, last line", current, old.cursor(Entry{seq: 99, line: "none", at: start.Add(7 * time.Second)}), nil},
+		{"earlier follower, unknown position", current, "gone-3-0-none", nil},
+		{"legacy cursor", current, "gone-3", nil},
+		{"garbage", current, "garbage", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := messages(tt.f.backlog(tt.cursor))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("backlog(%q) = %v, want %v", tt.cursor, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package logstream
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+// Hub runs at most one follower per server and shares it between subscribers
+type Hub struct {
+	cfg Config
+
+	mu        sync.Mutex
+	followers map[string]*Follower
+}
+
+func NewHub(cfg Config) *Hub {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1000
+	}
+	if cfg.SubscriberQueue <= 0 {
+		cfg.SubscriberQueue = 256
+	}
+
+	h := &Hub{
+		cfg:       cfg,
+		followers: make(map[string]*Follower),
+	}
+
+	// Start cleanup goroutine
+	go h.cleanupLoop()
+
+	return h
+}
+
+// Subscribe streams the admin log of server, resuming after cursor if given. If no
+// follower is running for the server one is started on client; retain is called to
+// keep the client alive for as long as that follower runs and must return its release.
+func (h *Hub) Subscribe(server string, client *rcon.Client, retain func() func(), cursor string) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, ok := h.followers[server]
+	if !ok {
+		f = newFollower(server, client, retain(), h.cfg)
+		h.followers[server] = f
+	}
+	return f.subscribe(cursor)
+}
+
+func (h *Hub) cleanupLoop() {
+	ticker := time.NewTicker(h.cfg.PollInterval * 5)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.cleanup()
+	}
+}
+
+// cleanup stops followers nobody has subscribed to for a while
+func (h *Hub) cleanup() {
+	h.mu.Lock()
+	var idle []*Follower
+	for server, f := range h.followers {
+		if f.idle(h.cfg.IdleShutdown) {
+			idle = append(idle, f)
+			delete(h.followers, server)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, f := range idle {
+		f.stop()
+	}
+}
+
+// Close stops every follower
+func (h *Hub) Close() {
+	h.mu.Lock()
+	followers := h.followers
+	h.followers = make(map[string]*Follower)
+	h.mu.Unlock()
+
+	for _, f := range followers {
+		f.stop()
+	}
+}
@@ -0,0 +1,260 @@
+// Package metrics exposes Prometheus metrics for RCON traffic, HTTP requests and the
+// game servers hllrcon is connected to.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Sledro/hllrcon/observe"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/session"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "hllrcon"
+
+// Metrics holds the collectors and implements rcon.Observer
+type Metrics struct {
+	registry *prometheus.Registry
+
+	rconDuration      *prometheus.HistogramVec
+	rconErrors        *prometheus.CounterVec
+	rconBytesSent     prometheus.Counter
+	rconBytesReceived prometheus.Counter
+	rconReconnects    *prometheus.CounterVec
+	httpDuration      *prometheus.HistogramVec
+
+	players    *prometheus.GaugeVec
+	maxPlayers *prometheus.GaugeVec
+	queue      *prometheus.GaugeVec
+	score      *prometheus.GaugeVec
+	sampleErrs *prometheus.CounterVec
+	sampled    map[string]bool // Servers whose game gauges the last sample set
+}
+
+// New creates the collectors on a dedicated registry, along with Go runtime and process metrics
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		rconDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rcon",
+			Name:      "command_duration_seconds",
+			Help:      "Time from sending an RCON command to receiving its response.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"command", "status"}),
+		rconErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rcon",
+			Name:      "errors_total",
+			Help:      "Failed RCON commands by error type: timeout, canceled, auth, magic_mismatch, status, connection or other.",
+		}, []string{"command", "type"}),
+		rconBytesSent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rcon",
+			Name:      "sent_bytes_total",
+			Help:      "Bytes written to RCON connections.",
+		}),
+		rconBytesReceived: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rcon",
+			Name:      "received_bytes_total",
+			Help:      "Bytes read from RCON connections.",
+		}),
+		rconReconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rcon",
+			Name:      "reconnects_total",
+			Help:      "Automatic reconnects after a lost RCON connection, by result.",
+		}, []string{"result"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		players: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "game",
+			Name:      "players",
+			Help:      "Players on each team, sampled from GetServerInformation session.",
+		}, []string{"server", "team"}),
+		maxPlayers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "game",
+			Name:      "max_players",
+			Help:      "Player slots on the server.",
+		}, []string{"server"}),
+		queue: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "game",
+			Name:      "queue_length",
+			Help:      "Players waiting in the regular and VIP join queues.",
+		}, []string{"server", "queue"}),
+		score: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "game",
+			Name:      "score",
+			Help:      "Current match score per team.",
+		}, []string{"server", "team"}),
+		sampleErrs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "game",
+			Name:      "sample_errors_total",
+			Help:      "Failed attempts to sample server information.",
+		}, []string{"server"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.rconDuration, m.rconErrors, m.rconBytesSent, m.rconBytesReceived, m.rconReconnects,
+		m.httpDuration, m.players, m.maxPlayers, m.queue, m.score, m.sampleErrs,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+// Middleware records the latency of every request under its route pattern
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.httpDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Observe(time.Since(start).Seconds())
+	}
+}
+
+// WatchSessions exposes session and connection pool counts
+func (m *Metrics) WatchSessions(sessions *session.Manager) {
+	pool := sessions.Pool()
+	m.registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sessions_active",
+			Help:      "Web and API key sessions currently open.",
+		}, func() float64 { return float64(sessions.Count()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "pool",
+			Name:      "connections",
+			Help:      "Pooled RCON connections.",
+		}, func() float64 { return float64(pool.Stats().Connections) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "pool",
+			Name:      "connections_in_use",
+			Help:      "Pooled RCON connections referenced by at least one session.",
+		}, func() float64 { return float64(pool.Stats().InUse) }),
+	)
+}
+
+// ObserveExchange implements rcon.Observer
+func (m *Metrics) ObserveExchange(command string, duration time.Duration, resp *rcon.Response, err error) {
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	m.rconDuration.WithLabelValues(command, status).Observe(duration.Seconds())
+
+	if kind := errorType(command, resp, err); kind != "" {
+		m.rconErrors.WithLabelValues(command, kind).Inc()
+	}
+}
+
+// ObserveBytes implements rcon.Observer
+func (m *Metrics) ObserveBytes(sent, received int) {
+	if sent > 0 {
+		m.rconBytesSent.Add(float64(sent))
+	}
+	if received > 0 {
+		m.rconBytesReceived.Add(float64(received))
+	}
+}
+
+// ObserveReconnect implements rcon.Observer
+func (m *Metrics) ObserveReconnect(err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.rconReconnects.WithLabelValues(result).Inc()
+}
+
+// errorType classifies a failed exchange, returning "" for a successful one
+func errorType(command string, resp *rcon.Response, err error) string {
+	switch {
+	case err == nil && resp.StatusCode == http.StatusOK:
+		return ""
+	case err == nil && (command == "Login" || resp.StatusCode == http.StatusUnauthorized):
+		return "auth"
+	case err == nil:
+		return "status"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, rcon.ErrInvalidMagic):
+		return "magic_mismatch"
+	case errors.Is(err, rcon.ErrConnectionLost):
+		return "connection"
+	default:
+		return "other"
+	}
+}
+
+// GameConsumer returns the feed consumer that keeps the game gauges up to date from
+// the match state of every server with an open session
+func (m *Metrics) GameConsumer() observe.Consumer {
+	return observe.Consumer{Session: true, Round: m.observeGame}
+}
+
+func (m *Metrics) observeGame(round observe.Round) {
+	sampled := make(map[string]bool)
+	for _, s := range round.Servers {
+		if s.SessionErr != nil {
+			m.sampleErrs.WithLabelValues(s.Addr).Inc()
+			continue
+		}
+
+		info := s.Session
+		sampled[s.Addr] = true
+		m.players.WithLabelValues(s.Addr, "allies").Set(float64(info.AlliedPlayerCount))
+		m.players.WithLabelValues(s.Addr, "axis").Set(float64(info.AxisPlayerCount))
+		m.maxPlayers.WithLabelValues(s.Addr).Set(float64(info.MaxPlayerCount))
+		m.queue.WithLabelValues(s.Addr, "regular").Set(float64(info.QueueCount))
+		m.queue.WithLabelValues(s.Addr, "vip").Set(float64(info.VIPQueueCount))
+		m.score.WithLabelValues(s.Addr, "allies").Set(float64(info.AlliedScore))
+		m.score.WithLabelValues(s.Addr, "axis").Set(float64(info.AxisScore))
+	}
+
+	// Servers that are no longer connected, or failed to answer, drop out of the gauges.
+	// Deleting only those keeps a scrape mid-sample from seeing the gauges empty.
+	for server := range m.sampled {
+		if sampled[server] {
+			continue
+		}
+		labels := prometheus.Labels{"server": server}
+		m.players.DeletePartialMatch(labels)
+		m.maxPlayers.DeletePartialMatch(labels)
+		m.queue.DeletePartialMatch(labels)
+		m.score.DeletePartialMatch(labels)
+	}
+	m.sampled = sampled
+}
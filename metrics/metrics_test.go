@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/observe"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveGameDropsOnlyVanishedServers(t *testing.T) {
+	info := &rcon.SessionInfo{AlliedPlayerCount: 30, AxisPlayerCount: 40, MaxPlayerCount: 100}
+	healthy := observe.Server{Addr: "10.0.0.1:7779", Session: info}
+
+	m := New()
+	m.observeGame(observe.Round{At: time.Now(), Servers: []observe.Server{healthy, {Addr: "10.0.0.2:7779", Session: info}}})
+	if n := testutil.CollectAndCount(m.players); n != 4 {
+		t.Fatalf("players series = %d, want 4", n)
+	}
+
+	failed := observe.Server{Addr: "10.0.0.2:7779", SessionErr: errors.New("500 Internal Server Error")}
+	m.observeGame(observe.Round{At: time.Now(), Servers: []observe.Server{healthy, failed}})
+	if n := testutil.CollectAndCount(m.players); n != 2 {
+		t.Errorf("players series after a failed sample = %d, want 2", n)
+	}
+	if n := testutil.CollectAndCount(m.maxPlayers); n != 1 {
+		t.Errorf("max players series after a failed sample = %d, want 1", n)
+	}
+	if v := testutil.ToFloat64(m.players.WithLabelValues(healthy.Addr, "axis")); v != 40 {
+		t.Errorf("axis players on the healthy server = %v, want 40", v)
+	}
+	if v := testutil.ToFloat64(m.sampleErrs.WithLabelValues(failed.Addr)); v != 1 {
+		t.Errorf("sample errors on the failed server = %v, want 1", v)
+	}
+}
@@ -0,0 +1,225 @@
+// Package observe samples every server with an open session and follows its admin
+// log once, fanning what it sees out to the background features that need it.
+package observe
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/logstream"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/session"
+)
+
+// logBatch bounds how many log entries are handed to consumers at once
+const logBatch = 500
+
+// Round is one sample of every server with an open session
+type Round struct {
+	At      time.Time
+	Servers []Server
+}
+
+// Server is what a round saw of one server. Players and Session are only sampled when
+// a consumer asked for them; the matching error is set if the server failed to answer.
+type Server struct {
+	Addr       string       // host:port
+	Client     *rcon.Client // Borrowed for the round; retain it through the pool to keep using it
+	Players    []rcon.Player
+	PlayersErr error
+	Session    *rcon.SessionInfo
+	SessionErr error
+}
+
+// Consumer is a feature fed by the feed. Its callbacks run on the feed's goroutines
+// and should return quickly.
+type Consumer struct {
+	Players bool        // Sample the player list each round
+	Session bool        // Sample the match state each round
+	Round   func(Round) // Called after each round, if set
+
+	// Log is called with new admin log entries of a server in order, if set. An error
+	// leaves the entries to be read again should the log subscription be resumed.
+	Log func(server string, client *rcon.Client, entries []logstream.Entry) error
+}
+
+// Feed samples each server once per interval for all consumers, and follows the
+// admin log of each server once while any consumer wants it
+type Feed struct {
+	pool      *session.Pool
+	hub       *logstream.Hub
+	consumers []Consumer
+
+	mu      sync.Mutex
+	subs    map[string]*logstream.Subscription
+	cursors map[string]string // Last stream cursor per server, to resume after a drop
+}
+
+func NewFeed(pool *session.Pool, hub *logstream.Hub) *Feed {
+	return &Feed{
+		pool:    pool,
+		hub:     hub,
+		subs:    make(map[string]*logstream.Subscription),
+		cursors: make(map[string]string),
+	}
+}
+
+// Add registers a consumer; it must be called before Run
+func (f *Feed) Add(c Consumer) {
+	f.consumers = append(f.consumers, c)
+}
+
+// Run samples every interval (30 seconds if unset) until ctx is done
+func (f *Feed) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer f.unfollowAll()
+
+	for {
+		f.sample(ctx, interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample runs one round over the servers with sessions and makes sure their logs are
+// followed; servers whose sessions have all ended are no longer followed
+func (f *Feed) sample(ctx context.Context, timeout time.Duration) {
+	var wantPlayers, wantSession, wantLog bool
+	for _, c := range f.consumers {
+		wantPlayers = wantPlayers || c.Players
+		wantSession = wantSession || c.Session
+		wantLog = wantLog || c.Log != nil
+	}
+
+	round := Round{At: time.Now().UTC()}
+	borrowed := f.pool.Borrow()
+	for _, b := range borrowed {
+		s := Server{Addr: net.JoinHostPort(b.Host, strconv.Itoa(b.Port)), Client: b.Client}
+		if wantPlayers {
+			sampleCtx, cancel := context.WithTimeout(ctx, timeout)
+			s.Players, s.PlayersErr = b.Client.GetPlayers(sampleCtx)
+			cancel()
+			if s.PlayersErr != nil && ctx.Err() == nil {
+				slog.Warn("Player list sample failed", "server", s.Addr, "error", s.PlayersErr)
+			}
+		}
+		if wantSession {
+			sampleCtx, cancel := context.WithTimeout(ctx, timeout)
+			s.Session, s.SessionErr = b.Client.GetServerSession(sampleCtx)
+			cancel()
+			if s.SessionErr != nil && ctx.Err() == nil {
+				slog.Warn("Session sample failed", "server", s.Addr, "error", s.SessionErr)
+			}
+		}
+		if wantLog {
+			f.follow(s.Addr, b.Client)
+		}
+		round.Servers = append(round.Servers, s)
+	}
+
+	for _, c := range f.consumers {
+		if c.Round != nil {
+			c.Round(round)
+		}
+	}
+	for _, b := range borrowed {
+		b.Release()
+	}
+
+	active := make(map[string]bool, len(round.Servers))
+	for _, s := range round.Servers {
+		active[s.Addr] = true
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for server, sub := range f.subs {
+		if !active[server] {
+			sub.Close()
+			delete(f.subs, server)
+		}
+	}
+}
+
+// follow subscribes to the admin log of server unless already subscribed
+func (f *Feed) follow(server string, client *rcon.Client) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.subs[server]; ok {
+		return
+	}
+
+	retain := func() func() {
+		if release, ok := f.pool.Retain(client); ok {
+			return release
+		}
+		return func() {}
+	}
+	sub := f.hub.Subscribe(server, client, retain, f.cursors[server])
+	f.subs[server] = sub
+	go f.deliver(server, client, sub)
+}
+
+// deliver hands log entries from sub to the consumers in batches until it ends. The
+// cursor only advances once every consumer has handled a batch, so a failed batch is
+// read again if the subscription has to be resumed.
+func (f *Feed) deliver(server string, client *rcon.Client, sub *logstream.Subscription) {
+	defer func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.subs[server] == sub {
+			delete(f.subs, server)
+		}
+	}()
+
+	for {
+		var batch []logstream.Entry
+		select {
+		case e := <-sub.Entries():
+			batch = append(batch, e)
+		case <-sub.Done():
+			return
+		}
+	drain:
+		for len(batch) < logBatch {
+			select {
+			case e := <-sub.Entries():
+				batch = append(batch, e)
+			default:
+				break drain
+			}
+		}
+
+		handled := true
+		for _, c := range f.consumers {
+			if c.Log != nil && c.Log(server, client, batch) != nil {
+				handled = false
+			}
+		}
+		if handled {
+			f.mu.Lock()
+			f.cursors[server] = batch[len(batch)-1].Cursor
+			f.mu.Unlock()
+		}
+	}
+}
+
+// unfollowAll ends every log subscription
+func (f *Feed) unfollowAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for server, sub := range f.subs {
+		sub.Close()
+		delete(f.subs, server)
+	}
+}
@@ -0,0 +1,121 @@
+package observe
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/logstream"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/rcon/rcontest"
+	"github.com/Sledro/hllrcon/session"
+)
+
+// collector records what a consumer was fed
+type collector struct {
+	mu     sync.Mutex
+	rounds []Round
+	lines  []string
+}
+
+func (c *collector) consumer(players, session bool) Consumer {
+	return Consumer{
+		Players: players,
+		Session: session,
+		Round: func(r Round) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.rounds = append(c.rounds, r)
+		},
+		Log: func(_ string, _ *rcon.Client, entries []logstream.Entry) error {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			for _, e := range entries {
+				c.lines = append(c.lines, e.Message)
+			}
+			return nil
+		},
+	}
+}
+
+func (c *collector) lineCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.lines)
+}
+
+func TestFeedSamplesEachServerOnce(t *testing.T) {
+	srv := rcontest.NewServer("secret")
+	defer srv.Close()
+	srv.Handle("GetServerInformation", func(body string) rcon.Response {
+		var content any = rcon.PlayerList{Players: []rcon.Player{{Name: "Able", ID: "1"}}}
+		if strings.Contains(body, "session") {
+			content = rcon.SessionInfo{PlayerCount: 1}
+		}
+		data, _ := json.Marshal(content)
+		return rcon.Response{StatusCode: 200, StatusMessage: "OK", Version: rcon.Version, Name: "GetServerInformation", ContentBody: string(data)}
+	})
+	srv.SetResponse("GetAdminLog", map[string]any{"entries": []rcon.LogEntry{
+		{Timestamp: time.Now().UTC().Format(time.RFC3339), Message: "CONNECTED Able (1)"},
+	}})
+
+	pool := session.NewPool(func(host string, port int, password string) *rcon.Client {
+		return rcon.NewClient(host, port, password, time.Second, 1<<20, 1<<20)
+	}, session.PoolConfig{MaxConnsPerServer: 1, IdleTimeout: time.Minute, HealthCheckInterval: time.Minute})
+	defer pool.Close()
+	_, release, err := pool.Acquire(context.Background(), srv.Host(), srv.Port(), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	hub := logstream.NewHub(logstream.Config{PollInterval: 50 * time.Millisecond, InitialBacklog: time.Minute})
+	defer hub.Close()
+	f := NewFeed(pool, hub)
+	history, watchlist, game := &collector{}, &collector{}, &collector{}
+	f.Add(history.consumer(true, false))
+	f.Add(watchlist.consumer(true, false))
+	f.Add(Consumer{Session: true, Round: game.consumer(false, true).Round})
+	f.sample(context.Background(), time.Second)
+	defer f.unfollowAll()
+
+	var playerSamples, sessionSamples int
+	for _, req := range srv.Requests() {
+		switch {
+		case req.Name != "GetServerInformation":
+		case strings.Contains(req.ContentBody, "players"):
+			playerSamples++
+		case strings.Contains(req.ContentBody, "session"):
+			sessionSamples++
+		}
+	}
+	if playerSamples != 1 || sessionSamples != 1 {
+		t.Errorf("sampled players %d and session %d times, want once each", playerSamples, sessionSamples)
+	}
+
+	for name, c := range map[string]*collector{"history": history, "watchlist": watchlist, "game": game} {
+		if len(c.rounds) != 1 || len(c.rounds[0].Servers) != 1 {
+			t.Fatalf("%s rounds = %+v", name, c.rounds)
+		}
+	}
+	s := history.rounds[0].Servers[0]
+	if s.PlayersErr != nil || len(s.Players) != 1 || s.SessionErr != nil || s.Session.PlayerCount != 1 {
+		t.Errorf("round server = %+v", s)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for history.lineCount() == 0 || watchlist.lineCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("log entries were not delivered to every log consumer")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.subs) != 1 {
+		t.Errorf("log subscriptions = %d, want 1", len(f.subs))
+	}
+}
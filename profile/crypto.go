@@ -0,0 +1,97 @@
+package profile
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters for deriving the store key; derivation runs once per Open
+const (
+	scryptN  = 1 << 15
+	scryptR  = 8
+	scryptP  = 1
+	saltSize = 16
+	keySize  = 32
+)
+
+// Cipher holds the secret profile passwords are encrypted under. The AES-256-GCM
+// key is derived from it with scrypt and a salt stored alongside the profiles.
+type Cipher struct {
+	secret []byte
+}
+
+// NewCipher returns a cipher for secret, which should be a long random string
+func NewCipher(secret string) (*Cipher, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("encryption key is empty")
+	}
+	return &Cipher{secret: []byte(secret)}, nil
+}
+
+// newSalt returns a random salt for a new store
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return salt, nil
+}
+
+// derive returns the key for a store salted with salt
+func (c *Cipher) derive(salt []byte) (*sealer, error) {
+	key, err := scrypt.Key(c.secret, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return newSealer(key)
+}
+
+// sealer encrypts with one derived key
+type sealer struct {
+	aead cipher.AEAD
+}
+
+func newSealer(key []byte) (*sealer, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{aead: aead}, nil
+}
+
+// encrypt returns base64(nonce || ciphertext). The ciphertext only decrypts with
+// the same aad, which binds it to its profile.
+func (s *sealer) encrypt(plaintext, aad string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(plaintext), []byte(aad))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt reverses encrypt
+func (s *sealer) decrypt(encoded, aad string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext encoding: %w", err)
+	}
+	if len(sealed) < s.aead.NonceSize() {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, []byte(aad))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt (wrong encryption key?): %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,243 @@
+// Package profile stores named game server profiles with their RCON password
+// encrypted at rest.
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/internal/fsutil"
+)
+
+var (
+	ErrNotFound = errors.New("profile not found")
+	ErrExists   = errors.New("profile already exists")
+)
+
+var nameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Profile is a saved game server. The password is never serialised in clear.
+type Profile struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Host        string    `json:"host"`
+	Port        int       `json:"port"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// record is the on-disk form of a profile
+type record struct {
+	Profile
+	EncryptedPassword string `json:"encrypted_password"`
+}
+
+// storeFile is the on-disk form of the store
+type storeFile struct {
+	Salt     []byte   `json:"salt"`
+	Profiles []record `json:"profiles"`
+}
+
+// Store persists profiles to a JSON file
+type Store struct {
+	path   string
+	salt   []byte
+	sealer *sealer
+
+	mu       sync.RWMutex
+	profiles map[string]record
+}
+
+// Open loads the store at path, creating it on first write
+func Open(path string, cipher *Cipher) (*Store, error) {
+	s := &Store{
+		path:     path,
+		profiles: make(map[string]record),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, s.setKey(cipher, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	var f storeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles: %w", err)
+	}
+	if len(f.Salt) == 0 {
+		return nil, fmt.Errorf("profiles file has no salt")
+	}
+	if err := s.setKey(cipher, f.Salt); err != nil {
+		return nil, err
+	}
+	for _, r := range f.Profiles {
+		s.profiles[r.Name] = r
+	}
+	return s, nil
+}
+
+// setKey derives the store key from salt, generating a salt if there is none
+func (s *Store) setKey(cipher *Cipher, salt []byte) error {
+	if salt == nil {
+		var err error
+		if salt, err = newSalt(); err != nil {
+			return err
+		}
+	}
+	sealer, err := cipher.derive(salt)
+	if err != nil {
+		return err
+	}
+	s.salt, s.sealer = salt, sealer
+	return nil
+}
+
+// ValidateName checks a profile name is a short lowercase slug
+func ValidateName(name string) error {
+	if !nameRe.MatchString(name) {
+		return fmt.Errorf("profile name must be 1-64 lowercase letters, digits, '-' or '_'")
+	}
+	return nil
+}
+
+// List returns all profiles sorted by name
+func (s *Store) List() []Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profiles := make([]Profile, 0, len(s.profiles))
+	for _, r := range s.profiles {
+		profiles = append(profiles, r.Profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles
+}
+
+// Get returns a profile without its password
+func (s *Store) Get(name string) (Profile, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.profiles[name]
+	if !ok {
+		return Profile{}, ErrNotFound
+	}
+	return r.Profile, nil
+}
+
+// Credentials returns the connection details of a profile with its decrypted password
+func (s *Store) Credentials(name string) (host string, port int, password string, err error) {
+	s.mu.RLock()
+	r, ok := s.profiles[name]
+	s.mu.RUnlock()
+	if !ok {
+		return "", 0, "", ErrNotFound
+	}
+
+	password, err = s.sealer.decrypt(r.EncryptedPassword, name)
+	if err != nil {
+		return "", 0, "", err
+	}
+	return r.Host, r.Port, password, nil
+}
+
+// Create adds a new profile
+func (s *Store) Create(p Profile, password string) (Profile, error) {
+	if err := ValidateName(p.Name); err != nil {
+		return Profile{}, err
+	}
+	encrypted, err := s.sealer.encrypt(password, p.Name)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.profiles[p.Name]; ok {
+		return Profile{}, ErrExists
+	}
+	p.CreatedAt = time.Now().UTC()
+	p.UpdatedAt = p.CreatedAt
+	s.profiles[p.Name] = record{Profile: p, EncryptedPassword: encrypted}
+
+	if err := s.saveLocked(); err != nil {
+		delete(s.profiles, p.Name)
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// Update replaces a profile's details. An empty password keeps the stored one.
+func (s *Store) Update(name string, p Profile, password string) (Profile, error) {
+	var encrypted string
+	if password != "" {
+		var err error
+		if encrypted, err = s.sealer.encrypt(password, name); err != nil {
+			return Profile{}, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.profiles[name]
+	if !ok {
+		return Profile{}, ErrNotFound
+	}
+	if encrypted == "" {
+		encrypted = old.EncryptedPassword
+	}
+	p.Name = name
+	p.CreatedAt = old.CreatedAt
+	p.UpdatedAt = time.Now().UTC()
+	s.profiles[name] = record{Profile: p, EncryptedPassword: encrypted}
+
+	if err := s.saveLocked(); err != nil {
+		s.profiles[name] = old
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// Delete removes a profile
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.profiles[name]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.profiles, name)
+
+	if err := s.saveLocked(); err != nil {
+		s.profiles[name] = old
+		return err
+	}
+	return nil
+}
+
+// saveLocked atomically rewrites the store file (caller must hold write lock)
+func (s *Store) saveLocked() error {
+	records := make([]record, 0, len(s.profiles))
+	for _, r := range s.profiles {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+
+	data, err := json.MarshalIndent(storeFile{Salt: s.salt, Profiles: records}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode profiles: %w", err)
+	}
+	return fsutil.WriteFileAtomic(s.path, data)
+}
@@ -0,0 +1,96 @@
+package profile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openTestStore(t *testing.T, path, secret string) *Store {
+	t.Helper()
+	cipher, err := NewCipher(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(path, cipher)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return s
+}
+
+func TestCredentialsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	s := openTestStore(t, path, "secret")
+	if _, err := s.Create(Profile{Name: "eu-1", Host: "10.0.0.1", Port: 7779}, "rconpass"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	reopened := openTestStore(t, path, "secret")
+	host, port, password, err := reopened.Credentials("eu-1")
+	if err != nil {
+		t.Fatalf("Credentials: %v", err)
+	}
+	if host != "10.0.0.1" || port != 7779 || password != "rconpass" {
+		t.Errorf("got %s:%d %q", host, port, password)
+	}
+
+	if _, _, _, err := openTestStore(t, path, "other").Credentials("eu-1"); err == nil {
+		t.Error("decrypted with the wrong secret")
+	}
+}
+
+func TestSaltIsPerStore(t *testing.T) {
+	dir := t.TempDir()
+	a := openTestStore(t, filepath.Join(dir, "a.json"), "secret")
+	b := openTestStore(t, filepath.Join(dir, "b.json"), "secret")
+	if string(a.salt) == string(b.salt) {
+		t.Error("two stores share a salt")
+	}
+}
+
+func TestPasswordIsBoundToProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	s := openTestStore(t, path, "secret")
+	for _, name := range []string{"eu-1", "us-1"} {
+		if _, err := s.Create(Profile{Name: name, Host: "h", Port: 1}, name+"-pass"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Swap the ciphertexts in the file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f storeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	f.Profiles[0].EncryptedPassword, f.Profiles[1].EncryptedPassword = f.Profiles[1].EncryptedPassword, f.Profiles[0].EncryptedPassword
+	if data, err = json.Marshal(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := openTestStore(t, path, "secret").Credentials("eu-1"); err == nil {
+		t.Error("password moved to another profile still decrypts")
+	}
+}
+
+func TestOpenRequiresSalt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(path, []byte(`{"profiles":[]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cipher, err := NewCipher("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, cipher); err == nil {
+		t.Error("opened a store without a salt")
+	}
+}
@@ -0,0 +1,201 @@
+package rcon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces secrets in recorded interactions
+const redacted = "[REDACTED]"
+
+// Interaction is one decrypted request/response pair of a cassette
+type Interaction struct {
+	Time        time.Time `json:"time"`
+	Server      string    `json:"server"`
+	Command     string    `json:"command"`
+	ContentBody string    `json:"content_body"` // Request content as sent to the server
+	Response    *Response `json:"response,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  float64   `json:"duration_ms"`
+}
+
+// Recorder appends interactions to a JSONL cassette. Passwords, the auth token and the
+// XOR key are redacted before anything is written. It is safe for concurrent use by
+// several clients.
+type Recorder struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewRecorder writes interactions to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// OpenRecorder appends interactions to the cassette at path, creating it if needed
+func OpenRecorder(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette: %w", err)
+	}
+	return &Recorder{w: f, closer: f}, nil
+}
+
+// Record redacts and appends one interaction
+func (r *Recorder) Record(i Interaction) error {
+	line, err := json.Marshal(redactInteraction(i))
+	if err != nil {
+		return fmt.Errorf("failed to encode interaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.w.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the cassette file if the recorder opened it
+func (r *Recorder) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closer.Close()
+}
+
+// SetRecorder records every exchange of the client, including the handshake
+func (c *Client) SetRecorder(r *Recorder) {
+	c.recorder.Store(r)
+}
+
+// ReadCassette parses a JSONL cassette
+func ReadCassette(r io.Reader) ([]Interaction, error) {
+	var interactions []Interaction
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var i Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		interactions = append(interactions, i)
+	}
+	return interactions, scanner.Err()
+}
+
+// LoadCassette reads the cassette at path
+func LoadCassette(path string) ([]Interaction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCassette(f)
+}
+
+// record writes an exchange to the client's recorder, if any
+func (c *Client) record(command string, contentBody any, start time.Time, resp *Response, err error) {
+	recorder := c.recorder.Load()
+	if recorder == nil {
+		return
+	}
+
+	i := Interaction{
+		Time:       start.UTC(),
+		Server:     fmt.Sprintf("%s:%d", c.host, c.port),
+		Command:    command,
+		Response:   resp,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	i.ContentBody, _ = encodeContentBody(contentBody)
+	if err != nil {
+		i.Error = err.Error()
+	}
+	if rerr := recorder.Record(i); rerr != nil && c.recorder.CompareAndSwap(recorder, nil) {
+		slog.Error("Failed to record RCON exchange; recording stopped for this connection", "error", rerr)
+	}
+}
+
+// redactInteraction strips credentials from a copy of i
+func redactInteraction(i Interaction) Interaction {
+	if i.Response != nil {
+		resp := *i.Response
+		i.Response = &resp
+	}
+
+	switch i.Command {
+	case "Login":
+		// The request carries the password and the response the auth token
+		i.ContentBody = redacted
+		if i.Response != nil && i.Response.StatusCode == 200 {
+			i.Response.ContentBody = redacted
+		}
+	case "ServerConnect":
+		if i.Response != nil && i.Response.StatusCode == 200 {
+			i.Response.ContentBody = redacted
+		}
+	default:
+		i.ContentBody = redactJSONString(i.ContentBody)
+		if i.Response != nil {
+			if body, ok := i.Response.ContentBody.(string); ok {
+				i.Response.ContentBody = redactJSONString(body)
+			}
+		}
+	}
+	return i
+}
+
+// redactJSONString redacts secret-looking fields of a JSON-encoded string, returning
+// anything that isn't a JSON object or array unchanged
+func redactJSONString(s string) string {
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return s
+	}
+	var value any
+	if err := json.Unmarshal([]byte(trimmed), &value); err != nil {
+		return s
+	}
+	if !redactValue(value) {
+		return s
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return s
+	}
+	return string(encoded)
+}
+
+// redactValue replaces secret-looking fields in place and reports whether any were found
+func redactValue(value any) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			lower := strings.ToLower(key)
+			if strings.Contains(lower, "password") || strings.Contains(lower, "token") || strings.Contains(lower, "secret") {
+				v[key] = redacted
+				changed = true
+				continue
+			}
+			changed = redactValue(inner) || changed
+		}
+	case []any:
+		for _, inner := range v {
+			changed = redactValue(inner) || changed
+		}
+	}
+	return changed
+}
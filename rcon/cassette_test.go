@@ -0,0 +1,89 @@
+package rcon
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRedactInteraction(t *testing.T) {
+	ok := func(body any) *Response { return &Response{StatusCode: 200, StatusMessage: "OK", ContentBody: body} }
+
+	tests := []struct {
+		name     string
+		in       Interaction
+		wantBody string
+		wantResp any
+	}{
+		{
+			name:     "login",
+			in:       Interaction{Command: "Login", ContentBody: "hunter2", Response: ok("auth-token")},
+			wantBody: redacted,
+			wantResp: redacted,
+		},
+		{
+			name:     "failed login keeps the error",
+			in:       Interaction{Command: "Login", ContentBody: "hunter2", Response: &Response{StatusCode: 401, ContentBody: "bad password"}},
+			wantBody: redacted,
+			wantResp: "bad password",
+		},
+		{
+			name:     "server connect",
+			in:       Interaction{Command: "ServerConnect", Response: ok("base64-xor-key")},
+			wantResp: redacted,
+		},
+		{
+			name:     "nested keys",
+			in:       Interaction{Command: "AddAdmin", ContentBody: `{"PlayerId":"1","Auth":{"Password":"p","ApiToken":"t","Nested":[{"client_secret":"s"}]}}`, Response: ok(`{"sessionToken":"t","name":"x"}`)},
+			wantBody: `{"Auth":{"ApiToken":"[REDACTED]","Nested":[{"client_secret":"[REDACTED]"}],"Password":"[REDACTED]"},"PlayerId":"1"}`,
+			wantResp: `{"name":"x","sessionToken":"[REDACTED]"}`,
+		},
+		{
+			name:     "nothing secret",
+			in:       Interaction{Command: "ServerBroadcast", ContentBody: `{"Message": "password is not a key here"}`, Response: ok("")},
+			wantBody: `{"Message": "password is not a key here"}`,
+			wantResp: "",
+		},
+		{
+			name:     "not JSON",
+			in:       Interaction{Command: "GetServerChangelist", ContentBody: "token", Response: ok("12345")},
+			wantBody: "token",
+			wantResp: "12345",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := *tt.in.Response
+			got := redactInteraction(tt.in)
+			if got.ContentBody != tt.wantBody {
+				t.Errorf("content body = %q, want %q", got.ContentBody, tt.wantBody)
+			}
+			if got.Response.ContentBody != tt.wantResp {
+				t.Errorf("response body = %v, want %v", got.Response.ContentBody, tt.wantResp)
+			}
+			if *tt.in.Response != original {
+				t.Error("redaction modified the caller's response")
+			}
+		})
+	}
+}
+
+func TestRecorderWritesRedacted(t *testing.T) {
+	var buf strings.Builder
+	r := NewRecorder(&buf)
+	if err := r.Record(Interaction{Command: "Login", ContentBody: "hunter2", Response: &Response{StatusCode: 200, ContentBody: "auth-token"}}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "hunter2") || strings.Contains(buf.String(), "auth-token") {
+		t.Errorf("cassette leaks credentials: %s", buf.String())
+	}
+
+	interactions, err := ReadCassette(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interactions) != 1 || interactions[0].Command != "Login" {
+		data, _ := json.Marshal(interactions)
+		t.Errorf("read back %s", data)
+	}
+}
@@ -1,17 +1,98 @@
 package rcon
 
 import (
-	"encoding/binary"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"net"
+	"os"
 	"strconv"
-	"sync"
+	"strings"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// DefaultCommandTimeout is used when no timeout is configured for a command
+const DefaultCommandTimeout = 10 * time.Second
+
+// Timeouts configures the I/O deadline applied to each command exchange
+type Timeouts struct {
+	Default  time.Duration            // Applied to commands without an override
+	Commands map[string]time.Duration // Per-command overrides (case-insensitive)
+}
+
+// For returns the timeout for the given command
+func (t Timeouts) For(command string) time.Duration {
+	if d, ok := t.Commands[strings.ToLower(command)]; ok && d > 0 {
+		return d
+	}
+	if t.Default > 0 {
+		return t.Default
+	}
+	return DefaultCommandTimeout
+}
+
+// State describes the lifecycle of the underlying connection
+type State int32
+
+const (
+	StateDisconnected State = iota
+	StateConnecting
+	StateConnected
+	StateClosed
+)
+
+func (s State) String() string {
+	switch s {
+	case StateDisconnected:
+		return "disconnected"
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrConnectionLost is matched by errors after which the connection had to be dropped
+var ErrConnectionLost = errors.New("connection lost")
+
+// ErrInvalidMagic is matched by errors for a response header without HeaderMagic
+var ErrInvalidMagic = errors.New("invalid header magic")
+
+// connError marks a transport failure that leaves the connection unusable
+type connError struct {
+	err  error
+	sent bool // Whether the request may have reached the server
+}
+
+func (e *connError) Error() string { return e.err.Error() }
+
+func (e *connError) Unwrap() error { return e.err }
+
+func (e *connError) Is(target error) bool { return target == ErrConnectionLost }
+
+// ReconnectPolicy controls how a lost connection is re-established
+type ReconnectPolicy struct {
+	MaxAttempts    int           // Handshake attempts per reconnect; 0 disables reconnecting
+	InitialBackoff time.Duration // Delay before the second attempt
+	MaxBackoff     time.Duration // Upper bound for the doubling delay
+}
+
+// DefaultReconnectPolicy is applied to new clients
+var DefaultReconnectPolicy = ReconnectPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+}
+
 type Client struct {
 	host            string
 	port            int
@@ -19,10 +100,19 @@ type Client struct {
 	conn            net.Conn
 	authToken       string
 	xorKey          []byte
-	mu              sync.Mutex
+	sem             chan struct{} // Serialises exchanges; acquirable with a context
 	dialTimeout     time.Duration
 	maxRequestSize  int
 	maxResponseSize int
+	timeouts        Timeouts
+	reconnect       ReconnectPolicy
+	state           atomic.Int32
+	closed          bool // Set by Close; suppresses reconnects
+	onStateChange   func(State, error)
+	observer        Observer
+	recorder        atomic.Pointer[Recorder]
+	maxInFlight     int  // Pipelining limit; below 2 exchanges are serial
+	mux             *mux // Set while the connection is pipelined
 }
 
 func NewClient(host string, port int, password string, dialTimeout time.Duration, maxRequestSize, maxResponseSize int) *Client {
@@ -30,37 +120,129 @@ func NewClient(host string, port int, password string, dialTimeout time.Duration
 		host:            host,
 		port:            port,
 		password:        password,
+		sem:             make(chan struct{}, 1),
 		dialTimeout:     dialTimeout,
 		maxRequestSize:  maxRequestSize,
 		maxResponseSize: maxResponseSize,
+		reconnect:       DefaultReconnectPolicy,
+	}
+}
+
+// SetTimeouts configures per-command deadlines. Override keys are matched case-insensitively.
+func (c *Client) SetTimeouts(t Timeouts) {
+	commands := make(map[string]time.Duration, len(t.Commands))
+	for name, d := range t.Commands {
+		commands[strings.ToLower(name)] = d
+	}
+	c.timeouts = Timeouts{Default: t.Default, Commands: commands}
+}
+
+// SetReconnectPolicy configures automatic reconnection after a lost connection
+func (c *Client) SetReconnectPolicy(p ReconnectPolicy) {
+	c.reconnect = p
+}
+
+// OnStateChange registers a callback invoked on every connection state change, with the
+// error that caused it if any. It runs synchronously and must not call back into the client.
+func (c *Client) OnStateChange(fn func(State, error)) {
+	c.onStateChange = fn
+}
+
+// State returns the current connection state
+func (c *Client) State() State {
+	return State(c.state.Load())
+}
+
+func (c *Client) setState(s State, err error) {
+	if State(c.state.Swap(int32(s))) == s {
+		return
+	}
+	if c.onStateChange != nil {
+		c.onStateChange(s, err)
 	}
 }
 
+// lock acquires the client lock, giving up if ctx is done first
+func (c *Client) lock(ctx context.Context) error {
+	_, span := tracer.Start(ctx, "rcon.lock_wait")
+	defer span.End()
+
+	select {
+	case c.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		err := fmt.Errorf("waiting for connection: %w", ctx.Err())
+		recordOutcome(span, nil, err)
+		return err
+	}
+}
+
+func (c *Client) unlock() {
+	<-c.sem
+}
+
 // Connect establishes connection and authenticates
 func (c *Client) Connect() error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	return c.ConnectContext(context.Background())
+}
+
+// ConnectContext establishes connection and authenticates, honouring ctx cancellation and deadline
+func (c *Client) ConnectContext(ctx context.Context) error {
+	if err := c.lock(ctx); err != nil {
+		return err
+	}
+	defer c.unlock()
+
+	c.closed = false
+	return c.connectUnlocked(ctx)
+}
+
+// connectUnlocked dials and performs the ServerConnect/Login handshake (caller must hold lock)
+func (c *Client) connectUnlocked(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "rcon.connect", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(c.serverAttributes()...))
+	defer span.End()
+
+	c.setState(StateConnecting, nil)
+	if err := c.handshakeUnlocked(ctx); err != nil {
+		c.setState(StateDisconnected, err)
+		recordOutcome(span, nil, err)
+		return err
+	}
+	if c.maxInFlight > 1 {
+		c.mux = newMux(c.conn, c.xorKey, c.maxResponseSize, c.maxInFlight)
+	}
+	c.setState(StateConnected, nil)
+	return nil
+}
 
+func (c *Client) handshakeUnlocked(ctx context.Context) error {
 	addr := net.JoinHostPort(c.host, strconv.Itoa(c.port))
 	slog.Debug("Connecting to RCON")
+	c.closeUnlocked()
 
-	conn, err := net.DialTimeout("tcp", addr, c.dialTimeout)
+	dialer := net.Dialer{Timeout: c.dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
+	if c.observer != nil {
+		conn = &countingConn{Conn: conn, observer: c.observer}
+	}
 	c.conn = conn
+	c.authToken = ""
+	c.xorKey = nil
 	slog.Debug("TCP connection established")
 
 	// Step 1: ServerConnect
 	slog.Debug("Sending ServerConnect command")
-	resp, err := c.exchangeUnlocked("ServerConnect", "")
+	resp, err := c.exchangeContext(ctx, "ServerConnect", "")
 	if err != nil {
-		c.conn.Close()
+		c.closeUnlocked()
 		return fmt.Errorf("ServerConnect failed: %w", err)
 	}
 
 	if resp.StatusCode != 200 {
-		c.conn.Close()
+		c.closeUnlocked()
 		return fmt.Errorf("ServerConnect failed: %s", resp.StatusMessage)
 	}
 	slog.Debug("ServerConnect successful")
@@ -68,34 +250,34 @@ func (c *Client) Connect() error {
 	// Decode XOR key
 	xorKeyStr, ok := resp.ContentBody.(string)
 	if !ok {
-		c.conn.Close()
+		c.closeUnlocked()
 		return fmt.Errorf("invalid XOR key type")
 	}
 
 	c.xorKey, err = DecodeXORKey(xorKeyStr)
 	if err != nil {
-		c.conn.Close()
+		c.closeUnlocked()
 		return fmt.Errorf("failed to decode XOR key: %w", err)
 	}
 	slog.Debug("XOR key decoded", "length", len(c.xorKey))
 
 	// Step 2: Login
 	slog.Debug("Sending Login command")
-	resp, err = c.exchangeUnlocked("Login", c.password)
+	resp, err = c.exchangeContext(ctx, "Login", c.password)
 	if err != nil {
-		c.conn.Close()
+		c.closeUnlocked()
 		return fmt.Errorf("login failed: %w", err)
 	}
 
 	if resp.StatusCode != 200 {
-		c.conn.Close()
+		c.closeUnlocked()
 		return fmt.Errorf("login failed: %s", resp.StatusMessage)
 	}
 
 	// Save auth token
 	authTokenStr, ok := resp.ContentBody.(string)
 	if !ok {
-		c.conn.Close()
+		c.closeUnlocked()
 		return fmt.Errorf("invalid auth token type")
 	}
 	c.authToken = authTokenStr
@@ -104,28 +286,165 @@ func (c *Client) Connect() error {
 	return nil
 }
 
+// reconnectUnlocked redoes the handshake with exponential backoff (caller must hold lock)
+func (c *Client) reconnectUnlocked(ctx context.Context) error {
+	c.checkMuxUnlocked()
+	if c.reconnect.MaxAttempts <= 0 {
+		return fmt.Errorf("not connected")
+	}
+
+	backoff := c.reconnect.InitialBackoff
+	var err error
+	for attempt := 1; attempt <= c.reconnect.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				err = fmt.Errorf("reconnect aborted: %w", ctx.Err())
+				if c.observer != nil {
+					c.observer.ObserveReconnect(err)
+				}
+				return err
+			}
+			backoff = min(backoff*2, c.reconnect.MaxBackoff)
+		}
+
+		slog.Info("Reconnecting to RCON", "attempt", attempt)
+		if err = c.connectUnlocked(ctx); err == nil {
+			if c.observer != nil {
+				c.observer.ObserveReconnect(nil)
+			}
+			return nil
+		}
+		slog.Warn("RCON reconnect attempt failed", "attempt", attempt, "error", err)
+	}
+	err = fmt.Errorf("reconnect failed after %d attempts: %w", c.reconnect.MaxAttempts, err)
+	if c.observer != nil {
+		c.observer.ObserveReconnect(err)
+	}
+	return err
+}
+
 // Close closes the connection
 func (c *Client) Close() error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.sem <- struct{}{}
+	defer c.unlock()
+
+	c.closed = true
+	err := c.closeUnlocked()
+	c.setState(StateClosed, nil)
+	return err
+}
 
-	if c.conn != nil {
-		return c.conn.Close()
+// closeUnlocked closes and forgets the connection (caller must hold lock)
+func (c *Client) closeUnlocked() error {
+	if c.conn == nil {
+		return nil
 	}
-	return nil
+	err := c.conn.Close()
+	c.conn = nil
+	c.mux = nil
+	return err
 }
 
 // Execute sends a command and returns the response
 func (c *Client) Execute(command string, contentBody any) (*Response, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	return c.ExecuteContext(context.Background(), command, contentBody)
+}
 
-	if c.conn == nil {
+// ExecuteContext sends a command and returns the response. The exchange is bounded by
+// the command's configured timeout and by ctx, whichever ends first. A lost connection
+// is re-established transparently and the command retried once if that is safe.
+func (c *Client) ExecuteContext(ctx context.Context, command string, contentBody any) (*Response, error) {
+	ctx, span := c.startCommandSpan(ctx, command)
+	defer span.End()
+
+	resp, err := c.execute(ctx, command, contentBody, true)
+	recordOutcome(span, resp, err)
+	return resp, err
+}
+
+// execute takes the lock and runs a command in pipelined or serial mode. retry allows
+// one more attempt, made from scratch, when a multiplexed connection is lost.
+func (c *Client) execute(ctx context.Context, command string, contentBody any, retry bool) (*Response, error) {
+	if err := c.lock(ctx); err != nil {
+		return nil, err
+	}
+	c.checkMuxUnlocked()
+	if c.mux != nil {
+		return c.executeMux(ctx, command, contentBody, retry)
+	}
+	defer c.unlock()
+	return c.executeUnlocked(ctx, command, contentBody)
+}
+
+// BatchCommand is one command of an ExecuteBatch call
+type BatchCommand struct {
+	Command     string
+	ContentBody any
+}
+
+// BatchResult is the outcome of one BatchCommand; exactly one of Response and Err is set
+type BatchResult struct {
+	Response *Response
+	Err      error
+}
+
+// ExecuteBatch runs commands in order while holding the connection, so no other caller's
+// commands are interleaved. With stopOnError it stops after the first command that fails
+// or returns a non-200 status, and the results cover only the commands that ran.
+func (c *Client) ExecuteBatch(ctx context.Context, commands []BatchCommand, stopOnError bool) ([]BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "rcon batch", trace.WithAttributes(attribute.Int("rcon.batch_size", len(commands))))
+	defer span.End()
+
+	if err := c.lock(ctx); err != nil {
+		recordOutcome(span, nil, err)
+		return nil, err
+	}
+	defer c.unlock()
+
+	results := make([]BatchResult, 0, len(commands))
+	for _, cmd := range commands {
+		cmdCtx, cmdSpan := c.startCommandSpan(ctx, cmd.Command)
+		resp, err := c.executeUnlocked(cmdCtx, cmd.Command, cmd.ContentBody)
+		recordOutcome(cmdSpan, resp, err)
+		cmdSpan.End()
+		results = append(results, BatchResult{Response: resp, Err: err})
+		if stopOnError && (err != nil || resp.StatusCode != 200) {
+			break
+		}
+	}
+	return results, nil
+}
+
+// executeUnlocked is ExecuteContext without locking (caller must hold lock)
+func (c *Client) executeUnlocked(ctx context.Context, command string, contentBody any) (*Response, error) {
+	if c.closed {
 		return nil, fmt.Errorf("not connected")
 	}
+	c.checkMuxUnlocked()
+	if c.conn == nil {
+		if err := c.reconnectUnlocked(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	slog.Debug("Executing RCON command", "command", command)
-	resp, err := c.exchangeUnlocked(command, contentBody)
+	resp, err := c.exchangeContext(ctx, command, contentBody)
+
+	var ce *connError
+	if errors.As(err, &ce) && ctx.Err() == nil {
+		slog.Warn("RCON connection lost", "command", command, "error", err)
+		if rerr := c.reconnectUnlocked(ctx); rerr != nil {
+			return nil, fmt.Errorf("%w (%v)", err, rerr)
+		}
+		if !ce.sent || IsReadOnly(command) {
+			slog.Debug("Retrying RCON command", "command", command)
+			resp, err = c.exchangeContext(ctx, command, contentBody)
+		}
+	}
 	if err != nil {
 		slog.Error("RCON command failed", "command", command, "error", err)
 		return nil, err
@@ -134,17 +453,111 @@ func (c *Client) Execute(command string, contentBody any) (*Response, error) {
 	return resp, nil
 }
 
+// exchangeContext runs one exchange and reports it to the observer and recorder (caller must hold lock)
+func (c *Client) exchangeContext(ctx context.Context, command string, contentBody any) (*Response, error) {
+	start := time.Now()
+	var resp *Response
+	var err error
+	if c.mux != nil {
+		var call *muxCall
+		if call, err = c.sendMux(ctx, command, contentBody); err == nil {
+			resp, err = call.wait(ctx)
+		}
+	} else {
+		resp, err = c.exchangeDeadline(ctx, command, contentBody)
+	}
+	c.observeExchange(command, contentBody, start, resp, err)
+	return resp, err
+}
+
+// observeExchange reports a finished exchange to the observer and recorder
+func (c *Client) observeExchange(command string, contentBody any, start time.Time, resp *Response, err error) {
+	if c.observer != nil {
+		c.observer.ObserveExchange(command, time.Since(start), resp, err)
+	}
+	c.record(command, contentBody, start, resp, err)
+}
+
+// exchangeDeadline wraps exchangeUnlocked with connection deadlines derived from ctx and
+// the command timeout (caller must hold lock). If the exchange is interrupted or the
+// stream is broken the connection is closed, since a late response would desynchronise it.
+func (c *Client) exchangeDeadline(ctx context.Context, command string, contentBody any) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.timeouts.For(command))
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		c.closeUnlocked()
+		return nil, &connError{err: fmt.Errorf("failed to set deadline: %w", err)}
+	}
+
+	// Unblock in-flight I/O as soon as ctx is cancelled
+	conn := c.conn
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+
+	resp, err := c.exchangeUnlocked(ctx, command, contentBody)
+	stop()
+	if err != nil {
+		var ce *connError
+		if !errors.As(err, &ce) {
+			return nil, err
+		}
+		c.closeUnlocked()
+		if c.State() == StateConnected {
+			c.setState(StateDisconnected, err)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, &connError{err: fmt.Errorf("%s: %w", command, ctxErr), sent: ce.sent}
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return nil, &connError{err: fmt.Errorf("%s timed out: %w", command, context.DeadlineExceeded), sent: ce.sent}
+		}
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Time{})
+	return resp, nil
+}
+
+// IsReadOnly reports whether a command only reads server state and can be safely repeated
+func IsReadOnly(command string) bool {
+	return strings.HasPrefix(command, "Get")
+}
+
 // exchangeUnlocked performs send/receive without locking (caller must hold lock)
-func (c *Client) exchangeUnlocked(command string, contentBody any) (*Response, error) {
-	// Pack request
-	data, requestID, err := PackRequest(c.authToken, command, contentBody)
+func (c *Client) exchangeUnlocked(ctx context.Context, command string, contentBody any) (*Response, error) {
+	requestID, err := c.writeRequest(ctx, command, contentBody)
 	if err != nil {
 		return nil, err
 	}
+	return c.readResponse(ctx, requestID)
+}
+
+// writeRequest packs, encrypts and sends a request, returning its ID
+func (c *Client) writeRequest(ctx context.Context, command string, contentBody any) (uint32, error) {
+	data, requestID, err := c.packRequest(command, contentBody)
+	if err != nil {
+		return 0, err
+	}
+	return requestID, c.sendRequest(ctx, data, requestID)
+}
+
+// packRequest frames and encrypts a request
+func (c *Client) packRequest(command string, contentBody any) ([]byte, uint32, error) {
+	data, requestID, err := PackRequest(c.authToken, command, contentBody)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	// Validate request size
 	if len(data) > c.maxRequestSize {
-		return nil, fmt.Errorf("request size %d exceeds maximum %d bytes", len(data), c.maxRequestSize)
+		return nil, 0, fmt.Errorf("request size %d exceeds maximum %d bytes", len(data), c.maxRequestSize)
 	}
 
 	// XOR encrypt the body (not the header)
@@ -152,50 +565,59 @@ func (c *Client) exchangeUnlocked(command string, contentBody any) (*Response, e
 		encryptedBody := XOR(data[HeaderSize:], c.xorKey)
 		data = append(data[:HeaderSize], encryptedBody...)
 	}
+	return data, requestID, nil
+}
 
-	// Send request
-	if _, err := c.conn.Write(data); err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-
-	// Receive response header
-	header := make([]byte, HeaderSize)
-	if _, err := io.ReadFull(c.conn, header); err != nil {
-		return nil, fmt.Errorf("failed to read response header: %w", err)
-	}
+// sendRequest writes a packed request to the connection
+func (c *Client) sendRequest(ctx context.Context, data []byte, requestID uint32) (err error) {
+	_, span := tracer.Start(ctx, "rcon.write")
+	span.SetAttributes(attribute.Int64("rcon.request_id", int64(requestID)), attribute.Int("rcon.request_bytes", len(data)))
+	defer func() {
+		recordOutcome(span, nil, err)
+		span.End()
+	}()
 
-	magic := binary.LittleEndian.Uint32(header[0:4])
-	if magic != HeaderMagic {
-		return nil, fmt.Errorf("invalid header magic: expected 0x%08X, got 0x%08X", HeaderMagic, magic)
+	// A partial write may still have reached the server
+	if n, err := c.conn.Write(data); err != nil {
+		return &connError{err: fmt.Errorf("failed to send request: %w", err), sent: n > 0}
 	}
-	respID := binary.LittleEndian.Uint32(header[4:8])
-	contentLength := binary.LittleEndian.Uint32(header[8:12])
+	return nil
+}
 
-	// Validate response size
-	if int(contentLength) > c.maxResponseSize {
-		return nil, fmt.Errorf("response size %d exceeds maximum %d bytes", contentLength, c.maxResponseSize)
-	}
+// maxStaleFrames bounds how many responses to earlier requests are skipped while waiting
+// for the current one before the connection is considered desynchronised
+const maxStaleFrames = 8
 
-	// Receive response body
-	body := make([]byte, contentLength)
-	if _, err := io.ReadFull(c.conn, body); err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
+// readResponse receives and decrypts the response to requestID. Responses to earlier
+// requests are discarded; anything else forces a reconnect.
+func (c *Client) readResponse(ctx context.Context, requestID uint32) (_ *Response, err error) {
+	_, span := tracer.Start(ctx, "rcon.read")
+	defer func() {
+		recordOutcome(span, nil, err)
+		span.End()
+	}()
 
-	// XOR decrypt the body
-	if len(c.xorKey) > 0 {
-		body = XOR(body, c.xorKey)
-	}
+	for skipped := 0; ; skipped++ {
+		frame, err := ReadFrame(c.conn, c.maxResponseSize)
+		if err != nil {
+			return nil, &connError{err: fmt.Errorf("failed to read response: %w", err), sent: true}
+		}
+		span.SetAttributes(attribute.Int64("rcon.response_bytes", int64(HeaderSize+len(frame.Body))))
 
-	// Unmarshal response
-	var resp Response
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
+		if frame.ID != requestID {
+			// IDs increase per request; compare as serial numbers so wraparound is handled
+			if int32(frame.ID-requestID) < 0 && skipped < maxStaleFrames {
+				slog.Warn("Discarding stale RCON response", "expected", requestID, "got", frame.ID)
+				span.AddEvent("rcon.stale_response", trace.WithAttributes(attribute.Int64("rcon.response_id", int64(frame.ID))))
+				continue
+			}
+			return nil, &connError{err: fmt.Errorf("response ID mismatch: expected %d, got %d", requestID, frame.ID), sent: true}
+		}
 
-	if respID != requestID {
-		return nil, fmt.Errorf("response ID mismatch: expected %d, got %d", requestID, respID)
+		body := frame.Body
+		if len(c.xorKey) > 0 {
+			body = XOR(body, c.xorKey)
+		}
+		return decodeResponse(body)
 	}
-
-	return &resp, nil
 }
@@ -0,0 +1,196 @@
+package rcon_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/rcon/rcontest"
+)
+
+// newTestClient connects a client to srv with short timeouts and a fast reconnect
+func newTestClient(t *testing.T, srv *rcontest.Server) *rcon.Client {
+	t.Helper()
+	client := rcon.NewClient(srv.Host(), srv.Port(), srv.Password, time.Second, 1<<20, 1<<20)
+	client.SetTimeouts(rcon.Timeouts{Default: 2 * time.Second})
+	client.SetReconnectPolicy(rcon.ReconnectPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func newTestServer(t *testing.T) *rcontest.Server {
+	t.Helper()
+	srv := rcontest.NewServer("secret")
+	t.Cleanup(srv.Close)
+	srv.SetResponse("GetServerChangelist", "12345")
+	srv.SetResponse("ServerBroadcast", "")
+	return srv
+}
+
+func TestClientHandshake(t *testing.T) {
+	srv := newTestServer(t)
+	client := newTestClient(t, srv)
+
+	if got := client.State(); got != rcon.StateConnected {
+		t.Fatalf("state = %v, want connected", got)
+	}
+	changelist, err := client.GetServerChangelist(context.Background())
+	if err != nil {
+		t.Fatalf("GetServerChangelist: %v", err)
+	}
+	if changelist != "12345" {
+		t.Errorf("changelist = %q, want 12345", changelist)
+	}
+	if n := srv.Logins(); n != 1 {
+		t.Errorf("logins = %d, want 1", n)
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(requests))
+	}
+	for i, name := range []string{"ServerConnect", "Login", "GetServerChangelist"} {
+		if requests[i].Name != name {
+			t.Errorf("request %d = %s, want %s", i, requests[i].Name, name)
+		}
+	}
+	if requests[1].ContentBody != "secret" {
+		t.Errorf("Login sent %q, want the password", requests[1].ContentBody)
+	}
+	if requests[2].AuthToken == "" {
+		t.Error("command sent without the auth token from Login")
+	}
+}
+
+func TestClientWrongPassword(t *testing.T) {
+	srv := newTestServer(t)
+	client := rcon.NewClient(srv.Host(), srv.Port(), "wrong", time.Second, 1<<20, 1<<20)
+	defer client.Close()
+
+	if err := client.Connect(); err == nil {
+		t.Fatal("Connect succeeded with a wrong password")
+	}
+	if got := client.State(); got != rcon.StateDisconnected {
+		t.Errorf("state = %v, want disconnected", got)
+	}
+}
+
+func TestClientReconnectsAfterDisconnect(t *testing.T) {
+	srv := newTestServer(t)
+	client := newTestClient(t, srv)
+
+	srv.InjectFault("GetServerChangelist", rcontest.Fault{Kind: rcontest.FaultDisconnect})
+	changelist, err := client.GetServerChangelist(context.Background())
+	if err != nil {
+		t.Fatalf("read-only command was not retried after reconnect: %v", err)
+	}
+	if changelist != "12345" {
+		t.Errorf("changelist = %q, want 12345", changelist)
+	}
+	if n := srv.Logins(); n != 2 {
+		t.Errorf("logins = %d, want 2", n)
+	}
+}
+
+func TestClientDoesNotRetryWritesAfterDisconnect(t *testing.T) {
+	srv := newTestServer(t)
+	client := newTestClient(t, srv)
+
+	srv.InjectFault("ServerBroadcast", rcontest.Fault{Kind: rcontest.FaultDisconnect})
+	err := client.ServerBroadcast(context.Background(), "hello")
+	if !errors.Is(err, rcon.ErrConnectionLost) {
+		t.Fatalf("err = %v, want ErrConnectionLost", err)
+	}
+
+	broadcasts := 0
+	for _, req := range srv.Requests() {
+		if req.Name == "ServerBroadcast" {
+			broadcasts++
+		}
+	}
+	if broadcasts != 1 {
+		t.Errorf("ServerBroadcast sent %d times, want 1", broadcasts)
+	}
+
+	// The connection was re-established for the next command
+	if err := client.ServerBroadcast(context.Background(), "again"); err != nil {
+		t.Fatalf("command after reconnect: %v", err)
+	}
+}
+
+func TestClientRejectsCorruptFrames(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault rcontest.FaultKind
+		want  error
+	}{
+		{"oversized", rcontest.FaultOversized, rcon.ErrFrameTooLarge},
+		{"bad magic", rcontest.FaultBadMagic, rcon.ErrInvalidMagic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			client := newTestClient(t, srv)
+
+			// A write is not retried, so the frame error surfaces
+			srv.InjectFault("ServerBroadcast", rcontest.Fault{Kind: tt.fault})
+			err := client.ServerBroadcast(context.Background(), "hello")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if !errors.Is(err, rcon.ErrConnectionLost) {
+				t.Errorf("err = %v, want the connection dropped", err)
+			}
+
+			if _, err := client.GetServerChangelist(context.Background()); err != nil {
+				t.Fatalf("client did not recover: %v", err)
+			}
+			if n := srv.Logins(); n != 2 {
+				t.Errorf("logins = %d, want 2", n)
+			}
+		})
+	}
+}
+
+func TestClientSkipsStaleResponse(t *testing.T) {
+	srv := newTestServer(t)
+	client := newTestClient(t, srv)
+
+	srv.InjectFault("ServerBroadcast", rcontest.Fault{Kind: rcontest.FaultStale})
+	if err := client.ServerBroadcast(context.Background(), "hello"); err != nil {
+		t.Fatalf("stale frame before the response: %v", err)
+	}
+	if _, err := client.GetServerChangelist(context.Background()); err != nil {
+		t.Fatalf("command after a stale frame: %v", err)
+	}
+	if n := srv.Logins(); n != 1 {
+		t.Errorf("logins = %d, want 1: the client reconnected instead of resyncing", n)
+	}
+}
+
+func TestClientReconnectsOnWrongID(t *testing.T) {
+	srv := newTestServer(t)
+	client := newTestClient(t, srv)
+
+	srv.InjectFault("GetServerChangelist", rcontest.Fault{Kind: rcontest.FaultWrongID})
+	changelist, err := client.GetServerChangelist(context.Background())
+	if err != nil {
+		t.Fatalf("read-only command was not retried after a wrong ID: %v", err)
+	}
+	if changelist != "12345" {
+		t.Errorf("changelist = %q, want 12345", changelist)
+	}
+	if n := srv.Logins(); n != 2 {
+		t.Errorf("logins = %d, want 2", n)
+	}
+
+	srv.InjectFault("ServerBroadcast", rcontest.Fault{Kind: rcontest.FaultWrongID})
+	if err := client.ServerBroadcast(context.Background(), "hello"); !errors.Is(err, rcon.ErrConnectionLost) {
+		t.Fatalf("write with a wrong ID: err = %v, want ErrConnectionLost", err)
+	}
+}
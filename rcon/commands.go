@@ -0,0 +1,547 @@
+package rcon
+
+import (
+	"context"
+	"fmt"
+)
+
+// StatusError is returned by typed commands when the server answers with a non-200 status
+type StatusError struct {
+	Command       string
+	StatusCode    int
+	StatusMessage string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s failed with status %d: %s", e.Command, e.StatusCode, e.StatusMessage)
+}
+
+// ForceMode selects when ForceTeamSwitch moves the player
+type ForceMode int
+
+const (
+	ForceOnDeath     ForceMode = 0
+	ForceImmediately ForceMode = 1
+)
+
+// Request bodies, named after the command they belong to. Field tags are the
+// parameter names expected by the server.
+
+type ServerInformationRequest struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+type AdminLogRequest struct {
+	LogBackTrackTime int    `json:"LogBackTrackTime"` // Seconds
+	Filters          string `json:"Filters"`
+}
+
+type MessageRequest struct {
+	Message string `json:"Message"`
+}
+
+type PlayerRequest struct {
+	PlayerID string `json:"PlayerId"`
+}
+
+type PlayerReasonRequest struct {
+	PlayerID string `json:"PlayerId"`
+	Reason   string `json:"Reason"`
+}
+
+type MessagePlayerRequest struct {
+	PlayerID string `json:"PlayerId"`
+	Message  string `json:"Message"`
+}
+
+type TemporaryBanRequest struct {
+	PlayerID  string `json:"PlayerId"`
+	Duration  int    `json:"Duration"` // Hours
+	Reason    string `json:"Reason"`
+	AdminName string `json:"AdminName"`
+}
+
+type PermanentBanRequest struct {
+	PlayerID  string `json:"PlayerId"`
+	Reason    string `json:"Reason"`
+	AdminName string `json:"AdminName"`
+}
+
+type AddVipRequest struct {
+	PlayerID string `json:"PlayerId"`
+	Comment  string `json:"Comment"`
+}
+
+type AddAdminRequest struct {
+	PlayerID   string `json:"PlayerId"`
+	AdminGroup string `json:"AdminGroup"`
+	Comment    string `json:"Comment"`
+}
+
+type ForceTeamSwitchRequest struct {
+	PlayerID  string    `json:"PlayerId"`
+	ForceMode ForceMode `json:"ForceMode"`
+}
+
+type DisbandPlatoonRequest struct {
+	TeamIndex  int    `json:"TeamIndex"`
+	SquadIndex int    `json:"SquadIndex"`
+	Reason     string `json:"Reason"`
+}
+
+type MapRequest struct {
+	MapName string `json:"MapName"`
+}
+
+type MapIndexRequest struct {
+	MapName string `json:"MapName"`
+	Index   int    `json:"Index"`
+}
+
+type IndexRequest struct {
+	Index int `json:"Index"`
+}
+
+type MoveMapRequest struct {
+	CurrentIndex int `json:"CurrentIndex"`
+	NewIndex     int `json:"NewIndex"`
+}
+
+type EnableRequest struct {
+	Enable bool `json:"Enable"`
+}
+
+type SectorLayoutRequest struct {
+	Sector1 string `json:"Sector_1"`
+	Sector2 string `json:"Sector_2"`
+	Sector3 string `json:"Sector_3"`
+	Sector4 string `json:"Sector_4"`
+	Sector5 string `json:"Sector_5"`
+}
+
+type TeamSwitchCooldownRequest struct {
+	TeamSwitchTimer int `json:"TeamSwitchTimer"` // Minutes
+}
+
+type MaxQueuedPlayersRequest struct {
+	MaxQueuedPlayers int `json:"MaxQueuedPlayers"`
+}
+
+type IdleKickDurationRequest struct {
+	IdleTimeoutMinutes int `json:"IdleTimeoutMinutes"`
+}
+
+type HighPingThresholdRequest struct {
+	HighPingThresholdMs int `json:"HighPingThresholdMs"`
+}
+
+type VipSlotCountRequest struct {
+	VipSlotCount int `json:"VipSlotCount"`
+}
+
+type AutoBalanceThresholdRequest struct {
+	AutoBalanceThreshold int `json:"AutoBalanceThreshold"`
+}
+
+type VoteKickThresholdRequest struct {
+	ThresholdValue string `json:"ThresholdValue"` // Comma-separated player-count,threshold pairs
+}
+
+type MatchTimerRequest struct {
+	GameMode    string `json:"GameMode"`
+	MatchLength int    `json:"MatchLength"` // Minutes
+}
+
+type WarmupTimerRequest struct {
+	GameMode     string `json:"GameMode"`
+	WarmupLength int    `json:"WarmupLength"` // Minutes
+}
+
+type GameModeRequest struct {
+	GameMode string `json:"GameMode"`
+}
+
+type DynamicWeatherRequest struct {
+	MapID  string `json:"MapId"`
+	Enable bool   `json:"Enable"`
+}
+
+type BannedWordsRequest struct {
+	BannedWords string `json:"BannedWords"` // Comma-separated
+}
+
+// call executes a command and converts non-200 statuses into *StatusError
+func (c *Client) call(ctx context.Context, command string, contentBody any) (*Response, error) {
+	resp, err := c.ExecuteContext(ctx, command, contentBody)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, &StatusError{Command: command, StatusCode: resp.StatusCode, StatusMessage: resp.StatusMessage}
+	}
+	return resp, nil
+}
+
+// query executes a command and decodes its content body into v
+func (c *Client) query(ctx context.Context, command string, contentBody any, v any) error {
+	resp, err := c.call(ctx, command, contentBody)
+	if err != nil {
+		return err
+	}
+	if err := DecodeContent(resp.ContentBody, v); err != nil {
+		return fmt.Errorf("%s: %w", command, err)
+	}
+	return nil
+}
+
+// do executes a command whose response carries no content
+func (c *Client) do(ctx context.Context, command string, contentBody any) error {
+	_, err := c.call(ctx, command, contentBody)
+	return err
+}
+
+// Server information
+
+// GetServerInformation decodes an arbitrary GetServerInformation type into v
+func (c *Client) GetServerInformation(ctx context.Context, name, value string, v any) error {
+	return c.query(ctx, "GetServerInformation", ServerInformationRequest{Name: name, Value: value}, v)
+}
+
+// GetServerSession returns details of the current match
+func (c *Client) GetServerSession(ctx context.Context) (*SessionInfo, error) {
+	var info SessionInfo
+	if err := c.GetServerInformation(ctx, "session", "", &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetPlayers returns all connected players
+func (c *Client) GetPlayers(ctx context.Context) ([]Player, error) {
+	var list PlayerList
+	if err := c.GetServerInformation(ctx, "players", "", &list); err != nil {
+		return nil, err
+	}
+	return list.Players, nil
+}
+
+// GetPlayer returns a single connected player
+func (c *Client) GetPlayer(ctx context.Context, playerID string) (*Player, error) {
+	var player Player
+	if err := c.GetServerInformation(ctx, "player", playerID, &player); err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
+
+// GetAdminLog returns log entries from the last backTrackSeconds, optionally filtered
+func (c *Client) GetAdminLog(ctx context.Context, backTrackSeconds int, filters string) ([]LogEntry, error) {
+	var resp struct {
+		Entries []LogEntry `json:"entries"`
+	}
+	if err := c.query(ctx, "GetAdminLog", AdminLogRequest{LogBackTrackTime: backTrackSeconds, Filters: filters}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Entries, nil
+}
+
+// GetDisplayableCommands lists the commands the server supports
+func (c *Client) GetDisplayableCommands(ctx context.Context) ([]CommandInfo, error) {
+	var resp struct {
+		Entries []CommandInfo `json:"entries"`
+	}
+	if err := c.query(ctx, "GetDisplayableCommands", "", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Entries, nil
+}
+
+// GetClientReferenceData describes the parameters of a command
+func (c *Client) GetClientReferenceData(ctx context.Context, command string) (*CommandReference, error) {
+	var ref CommandReference
+	if err := c.query(ctx, "GetClientReferenceData", command, &ref); err != nil {
+		return nil, err
+	}
+	return &ref, nil
+}
+
+// GetServerChangelist returns the server build changelist as reported by the server
+func (c *Client) GetServerChangelist(ctx context.Context) (string, error) {
+	resp, err := c.call(ctx, "GetServerChangelist", "")
+	if err != nil {
+		return "", err
+	}
+	changelist, ok := resp.ContentBody.(string)
+	if !ok {
+		return fmt.Sprint(resp.ContentBody), nil
+	}
+	return changelist, nil
+}
+
+// Messaging
+
+// ServerBroadcast shows a message to every player
+func (c *Client) ServerBroadcast(ctx context.Context, message string) error {
+	return c.do(ctx, "ServerBroadcast", MessageRequest{Message: message})
+}
+
+// SetWelcomeMessage sets the message shown to joining players
+func (c *Client) SetWelcomeMessage(ctx context.Context, message string) error {
+	return c.do(ctx, "SetWelcomeMessage", MessageRequest{Message: message})
+}
+
+// MessagePlayer sends a direct message to a player
+func (c *Client) MessagePlayer(ctx context.Context, playerID, message string) error {
+	return c.do(ctx, "MessagePlayer", MessagePlayerRequest{PlayerID: playerID, Message: message})
+}
+
+// Moderation
+
+// KickPlayer removes a player from the server
+func (c *Client) KickPlayer(ctx context.Context, playerID, reason string) error {
+	return c.do(ctx, "KickPlayer", PlayerReasonRequest{PlayerID: playerID, Reason: reason})
+}
+
+// PunishPlayer kills a player in game
+func (c *Client) PunishPlayer(ctx context.Context, playerID, reason string) error {
+	return c.do(ctx, "PunishPlayer", PlayerReasonRequest{PlayerID: playerID, Reason: reason})
+}
+
+// TemporaryBanPlayer bans a player for the given number of hours
+func (c *Client) TemporaryBanPlayer(ctx context.Context, playerID string, hours int, reason, adminName string) error {
+	return c.do(ctx, "TemporaryBanPlayer", TemporaryBanRequest{PlayerID: playerID, Duration: hours, Reason: reason, AdminName: adminName})
+}
+
+// PermanentBanPlayer bans a player permanently
+func (c *Client) PermanentBanPlayer(ctx context.Context, playerID, reason, adminName string) error {
+	return c.do(ctx, "PermanentBanPlayer", PermanentBanRequest{PlayerID: playerID, Reason: reason, AdminName: adminName})
+}
+
+// RemoveTemporaryBan lifts a temporary ban
+func (c *Client) RemoveTemporaryBan(ctx context.Context, playerID string) error {
+	return c.do(ctx, "RemoveTemporaryBan", PlayerRequest{PlayerID: playerID})
+}
+
+// RemovePermanentBan lifts a permanent ban
+func (c *Client) RemovePermanentBan(ctx context.Context, playerID string) error {
+	return c.do(ctx, "RemovePermanentBan", PlayerRequest{PlayerID: playerID})
+}
+
+// GetTemporaryBans lists temporary bans
+func (c *Client) GetTemporaryBans(ctx context.Context) ([]Ban, error) {
+	return c.getBans(ctx, "GetTemporaryBans")
+}
+
+// GetPermanentBans lists permanent bans
+func (c *Client) GetPermanentBans(ctx context.Context) ([]Ban, error) {
+	return c.getBans(ctx, "GetPermanentBans")
+}
+
+func (c *Client) getBans(ctx context.Context, command string) ([]Ban, error) {
+	var resp struct {
+		BanList []Ban `json:"ban_list"`
+	}
+	if err := c.query(ctx, command, "", &resp); err != nil {
+		return nil, err
+	}
+	return resp.BanList, nil
+}
+
+// Squads and teams
+
+// ForceTeamSwitch moves a player to the other team
+func (c *Client) ForceTeamSwitch(ctx context.Context, playerID string, mode ForceMode) error {
+	return c.do(ctx, "ForceTeamSwitch", ForceTeamSwitchRequest{PlayerID: playerID, ForceMode: mode})
+}
+
+// RemovePlayerFromPlatoon removes a player from their squad
+func (c *Client) RemovePlayerFromPlatoon(ctx context.Context, playerID, reason string) error {
+	return c.do(ctx, "RemovePlayerFromPlatoon", PlayerReasonRequest{PlayerID: playerID, Reason: reason})
+}
+
+// DisbandPlatoon disbands a squad
+func (c *Client) DisbandPlatoon(ctx context.Context, teamIndex, squadIndex int, reason string) error {
+	return c.do(ctx, "DisbandPlatoon", DisbandPlatoonRequest{TeamIndex: teamIndex, SquadIndex: squadIndex, Reason: reason})
+}
+
+// VIPs and admins
+
+// AddVip grants VIP to a player
+func (c *Client) AddVip(ctx context.Context, playerID, comment string) error {
+	return c.do(ctx, "AddVip", AddVipRequest{PlayerID: playerID, Comment: comment})
+}
+
+// RemoveVip revokes VIP from a player
+func (c *Client) RemoveVip(ctx context.Context, playerID string) error {
+	return c.do(ctx, "RemoveVip", PlayerRequest{PlayerID: playerID})
+}
+
+// SetVipSlotCount sets the number of reserved VIP slots
+func (c *Client) SetVipSlotCount(ctx context.Context, count int) error {
+	return c.do(ctx, "SetVipSlotCount", VipSlotCountRequest{VipSlotCount: count})
+}
+
+// GetAdminUsers lists admins
+func (c *Client) GetAdminUsers(ctx context.Context) ([]AdminUser, error) {
+	var resp struct {
+		AdminUsers []AdminUser `json:"admin_users"`
+	}
+	if err := c.query(ctx, "GetAdminUsers", "", &resp); err != nil {
+		return nil, err
+	}
+	return resp.AdminUsers, nil
+}
+
+// GetAdminGroups lists the admin group names
+func (c *Client) GetAdminGroups(ctx context.Context) ([]string, error) {
+	var resp struct {
+		GroupNames []string `json:"group_names"`
+	}
+	if err := c.query(ctx, "GetAdminGroups", "", &resp); err != nil {
+		return nil, err
+	}
+	return resp.GroupNames, nil
+}
+
+// AddAdmin adds a player to an admin group
+func (c *Client) AddAdmin(ctx context.Context, playerID, adminGroup, comment string) error {
+	return c.do(ctx, "AddAdmin", AddAdminRequest{PlayerID: playerID, AdminGroup: adminGroup, Comment: comment})
+}
+
+// RemoveAdmin removes a player's admin rights
+func (c *Client) RemoveAdmin(ctx context.Context, playerID string) error {
+	return c.do(ctx, "RemoveAdmin", PlayerRequest{PlayerID: playerID})
+}
+
+// Maps
+
+// ChangeMap switches to the given map immediately
+func (c *Client) ChangeMap(ctx context.Context, mapName string) error {
+	return c.do(ctx, "ChangeMap", MapRequest{MapName: mapName})
+}
+
+// AddMapToRotation inserts a map into the rotation at index
+func (c *Client) AddMapToRotation(ctx context.Context, mapName string, index int) error {
+	return c.do(ctx, "AddMapToRotation", MapIndexRequest{MapName: mapName, Index: index})
+}
+
+// RemoveMapFromRotation removes the map at index from the rotation
+func (c *Client) RemoveMapFromRotation(ctx context.Context, index int) error {
+	return c.do(ctx, "RemoveMapFromRotation", IndexRequest{Index: index})
+}
+
+// AddMapToSequence inserts a map into the sequence at index
+func (c *Client) AddMapToSequence(ctx context.Context, mapName string, index int) error {
+	return c.do(ctx, "AddMapToSequence", MapIndexRequest{MapName: mapName, Index: index})
+}
+
+// RemoveMapFromSequence removes the map at index from the sequence
+func (c *Client) RemoveMapFromSequence(ctx context.Context, index int) error {
+	return c.do(ctx, "RemoveMapFromSequence", IndexRequest{Index: index})
+}
+
+// MoveMapInSequence moves a map within the sequence
+func (c *Client) MoveMapInSequence(ctx context.Context, currentIndex, newIndex int) error {
+	return c.do(ctx, "MoveMapInSequence", MoveMapRequest{CurrentIndex: currentIndex, NewIndex: newIndex})
+}
+
+// SetMapShuffleEnabled toggles map sequence shuffling
+func (c *Client) SetMapShuffleEnabled(ctx context.Context, enable bool) error {
+	return c.do(ctx, "SetMapShuffleEnabled", EnableRequest{Enable: enable})
+}
+
+// SetSectorLayout sets the objective for each of the five sectors
+func (c *Client) SetSectorLayout(ctx context.Context, sectors [5]string) error {
+	return c.do(ctx, "SetSectorLayout", SectorLayoutRequest{
+		Sector1: sectors[0],
+		Sector2: sectors[1],
+		Sector3: sectors[2],
+		Sector4: sectors[3],
+		Sector5: sectors[4],
+	})
+}
+
+// SetDynamicWeatherEnabled toggles dynamic weather for a map
+func (c *Client) SetDynamicWeatherEnabled(ctx context.Context, mapID string, enable bool) error {
+	return c.do(ctx, "SetDynamicWeatherEnabled", DynamicWeatherRequest{MapID: mapID, Enable: enable})
+}
+
+// Server settings
+
+// SetTeamSwitchCooldown sets the team switch cooldown in minutes
+func (c *Client) SetTeamSwitchCooldown(ctx context.Context, minutes int) error {
+	return c.do(ctx, "SetTeamSwitchCooldown", TeamSwitchCooldownRequest{TeamSwitchTimer: minutes})
+}
+
+// SetMaxQueuedPlayers sets the join queue length
+func (c *Client) SetMaxQueuedPlayers(ctx context.Context, count int) error {
+	return c.do(ctx, "SetMaxQueuedPlayers", MaxQueuedPlayersRequest{MaxQueuedPlayers: count})
+}
+
+// SetIdleKickDuration sets the idle kick time in minutes
+func (c *Client) SetIdleKickDuration(ctx context.Context, minutes int) error {
+	return c.do(ctx, "SetIdleKickDuration", IdleKickDurationRequest{IdleTimeoutMinutes: minutes})
+}
+
+// SetHighPingThreshold sets the ping in milliseconds above which players are kicked
+func (c *Client) SetHighPingThreshold(ctx context.Context, ms int) error {
+	return c.do(ctx, "SetHighPingThreshold", HighPingThresholdRequest{HighPingThresholdMs: ms})
+}
+
+// SetAutoBalanceEnabled toggles team auto balance
+func (c *Client) SetAutoBalanceEnabled(ctx context.Context, enable bool) error {
+	return c.do(ctx, "SetAutoBalanceEnabled", EnableRequest{Enable: enable})
+}
+
+// SetAutoBalanceThreshold sets the player difference that triggers auto balance
+func (c *Client) SetAutoBalanceThreshold(ctx context.Context, threshold int) error {
+	return c.do(ctx, "SetAutoBalanceThreshold", AutoBalanceThresholdRequest{AutoBalanceThreshold: threshold})
+}
+
+// SetVoteKickEnabled toggles vote kicking
+func (c *Client) SetVoteKickEnabled(ctx context.Context, enable bool) error {
+	return c.do(ctx, "SetVoteKickEnabled", EnableRequest{Enable: enable})
+}
+
+// SetVoteKickThreshold sets the vote kick thresholds
+func (c *Client) SetVoteKickThreshold(ctx context.Context, thresholdValue string) error {
+	return c.do(ctx, "SetVoteKickThreshold", VoteKickThresholdRequest{ThresholdValue: thresholdValue})
+}
+
+// ResetVoteKickThreshold restores the default vote kick thresholds
+func (c *Client) ResetVoteKickThreshold(ctx context.Context) error {
+	return c.do(ctx, "ResetVoteKickThreshold", struct{}{})
+}
+
+// SetMatchTimer sets the match length in minutes for a game mode
+func (c *Client) SetMatchTimer(ctx context.Context, gameMode string, minutes int) error {
+	return c.do(ctx, "SetMatchTimer", MatchTimerRequest{GameMode: gameMode, MatchLength: minutes})
+}
+
+// RemoveMatchTimer restores the default match length for a game mode
+func (c *Client) RemoveMatchTimer(ctx context.Context, gameMode string) error {
+	return c.do(ctx, "RemoveMatchTimer", GameModeRequest{GameMode: gameMode})
+}
+
+// SetWarmupTimer sets the warmup length in minutes for a game mode
+func (c *Client) SetWarmupTimer(ctx context.Context, gameMode string, minutes int) error {
+	return c.do(ctx, "SetWarmupTimer", WarmupTimerRequest{GameMode: gameMode, WarmupLength: minutes})
+}
+
+// RemoveWarmupTimer restores the default warmup length for a game mode
+func (c *Client) RemoveWarmupTimer(ctx context.Context, gameMode string) error {
+	return c.do(ctx, "RemoveWarmupTimer", GameModeRequest{GameMode: gameMode})
+}
+
+// Profanity filter
+
+// AddBannedWords adds comma-separated words to the chat filter
+func (c *Client) AddBannedWords(ctx context.Context, words string) error {
+	return c.do(ctx, "AddBannedWords", BannedWordsRequest{BannedWords: words})
+}
+
+// RemoveBannedWords removes comma-separated words from the chat filter
+func (c *Client) RemoveBannedWords(ctx context.Context, words string) error {
+	return c.do(ctx, "RemoveBannedWords", BannedWordsRequest{BannedWords: words})
+}
@@ -0,0 +1,133 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Unmarshal decodes server JSON into v. Object keys are matched to v's json tags
+// ignoring case and underscores, so "clanTag", "ClanTag" and "clan_tag" all decode
+// into a field tagged `json:"clan_tag"` regardless of how the server spells it.
+func Unmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	normalized, err := json.Marshal(normalizeKeys(raw, reflect.TypeOf(v)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(normalized, v)
+}
+
+// DecodeContent decodes a response content body, which the server sends as a JSON
+// string, into v
+func DecodeContent(contentBody any, v any) error {
+	var data []byte
+	switch body := contentBody.(type) {
+	case string:
+		if body == "" {
+			return fmt.Errorf("empty content body")
+		}
+		data = []byte(body)
+	default:
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to re-encode content body: %w", err)
+		}
+		data = encoded
+	}
+
+	if err := Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode content body: %w", err)
+	}
+	return nil
+}
+
+// normalizeKeys rewrites object keys in raw to the json tag names of t's fields
+func normalizeKeys(raw any, t reflect.Type) any {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return raw
+	}
+
+	switch val := raw.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := structFields(t)
+			out := make(map[string]any, len(val))
+			for key, item := range val {
+				if f, ok := fields[foldKey(key)]; ok {
+					out[f.name] = normalizeKeys(item, f.typ)
+				} else {
+					out[key] = item
+				}
+			}
+			return out
+		case reflect.Map:
+			for key, item := range val {
+				val[key] = normalizeKeys(item, t.Elem())
+			}
+		}
+	case json.Number:
+		// Numeric IDs and build numbers decode into string fields
+		if t.Kind() == reflect.String {
+			return val.String()
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range val {
+				val[i] = normalizeKeys(item, t.Elem())
+			}
+		}
+	}
+	return raw
+}
+
+type taggedField struct {
+	name string
+	typ  reflect.Type
+}
+
+var fieldCache sync.Map // reflect.Type -> map[string]taggedField
+
+// structFields indexes t's json field names by their folded form
+func structFields(t reflect.Type) map[string]taggedField {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.(map[string]taggedField)
+	}
+
+	fields := make(map[string]taggedField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[foldKey(name)] = taggedField{name: name, typ: f.Type}
+	}
+
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+func foldKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
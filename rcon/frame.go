@@ -0,0 +1,68 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrFrameTooLarge is matched by errors for a frame whose advertised length exceeds the limit
+var ErrFrameTooLarge = errors.New("frame too large")
+
+// frameReadChunk caps how much body buffer is allocated ahead of the bytes actually received
+const frameReadChunk = 64 << 10
+
+// Frame is one message on the wire: a 12 byte header followed by the (possibly XORed) body
+type Frame struct {
+	ID   uint32
+	Body []byte
+}
+
+// ParseHeader validates a frame header and returns the request ID and body length
+func ParseHeader(header []byte) (id, length uint32, err error) {
+	if len(header) < HeaderSize {
+		return 0, 0, fmt.Errorf("header too short: %d bytes", len(header))
+	}
+	magic := binary.LittleEndian.Uint32(header[0:4])
+	if magic != HeaderMagic {
+		return 0, 0, fmt.Errorf("%w: expected 0x%08X, got 0x%08X", ErrInvalidMagic, HeaderMagic, magic)
+	}
+	return binary.LittleEndian.Uint32(header[4:8]), binary.LittleEndian.Uint32(header[8:12]), nil
+}
+
+// EncodeFrame returns the header and body of a frame as one buffer
+func EncodeFrame(id uint32, body []byte) []byte {
+	data := make([]byte, HeaderSize, HeaderSize+len(body))
+	binary.LittleEndian.PutUint32(data[0:4], HeaderMagic)
+	binary.LittleEndian.PutUint32(data[4:8], id)
+	binary.LittleEndian.PutUint32(data[8:12], uint32(len(body)))
+	return append(data, body...)
+}
+
+// ReadFrame reads one frame from r, rejecting bodies over maxSize bytes. The body buffer
+// grows as data arrives, so a bogus length costs at most what the peer actually sends.
+func ReadFrame(r io.Reader, maxSize int) (Frame, error) {
+	header := make([]byte, HeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return Frame{}, fmt.Errorf("failed to read header: %w", err)
+	}
+	id, length, err := ParseHeader(header)
+	if err != nil {
+		return Frame{}, err
+	}
+	if int64(length) > int64(maxSize) {
+		return Frame{ID: id}, fmt.Errorf("%w: %d bytes exceeds maximum %d", ErrFrameTooLarge, length, maxSize)
+	}
+
+	var body bytes.Buffer
+	body.Grow(min(int(length), frameReadChunk))
+	if n, err := io.CopyN(&body, r, int64(length)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Frame{ID: id}, fmt.Errorf("failed to read body (%d of %d bytes): %w", n, length, err)
+	}
+	return Frame{ID: id, Body: body.Bytes()}, nil
+}
@@ -0,0 +1,116 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+)
+
+// header builds a frame header with the given magic, ID and advertised length
+func header(magic, id, length uint32) []byte {
+	h := make([]byte, HeaderSize)
+	binary.LittleEndian.PutUint32(h[0:4], magic)
+	binary.LittleEndian.PutUint32(h[4:8], id)
+	binary.LittleEndian.PutUint32(h[8:12], length)
+	return h
+}
+
+// frameSeeds are a valid frame, a bad magic, a truncated body and an oversized length
+func frameSeeds() [][]byte {
+	valid := EncodeFrame(7, []byte(`{"statusCode":200}`))
+	return [][]byte{
+		valid,
+		append(header(0xBADC0DE, 7, 2), "{}"...),
+		valid[:len(valid)-5],
+		append(header(HeaderMagic, 7, 0xFFFFFFF0), "{}"...),
+		EncodeFrame(0, nil),
+		valid[:HeaderSize-1],
+	}
+}
+
+func FuzzParseHeader(f *testing.F) {
+	for _, seed := range frameSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		id, length, err := ParseHeader(data)
+		if len(data) < HeaderSize {
+			if err == nil {
+				t.Fatalf("accepted a %d byte header", len(data))
+			}
+			return
+		}
+		if binary.LittleEndian.Uint32(data[0:4]) != HeaderMagic {
+			if !errors.Is(err, ErrInvalidMagic) {
+				t.Fatalf("bad magic: err = %v", err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("valid header rejected: %v", err)
+		}
+		if id != binary.LittleEndian.Uint32(data[4:8]) || length != binary.LittleEndian.Uint32(data[8:12]) {
+			t.Fatalf("got id %d length %d", id, length)
+		}
+	})
+}
+
+func FuzzReadFrame(f *testing.F) {
+	for _, seed := range frameSeeds() {
+		f.Add(seed, 1<<10)
+	}
+	f.Fuzz(func(t *testing.T, data []byte, maxSize int) {
+		maxSize = max(maxSize%(1<<20), 0)
+		frame, err := ReadFrame(bytes.NewReader(data), maxSize)
+		if len(data) < HeaderSize {
+			if err == nil {
+				t.Fatal("read a frame without a full header")
+			}
+			return
+		}
+
+		_, length, headerErr := ParseHeader(data)
+		switch {
+		case headerErr != nil:
+			if !errors.Is(err, ErrInvalidMagic) {
+				t.Fatalf("bad magic: err = %v", err)
+			}
+		case int64(length) > int64(maxSize):
+			if !errors.Is(err, ErrFrameTooLarge) {
+				t.Fatalf("%d byte frame over a %d limit: err = %v", length, maxSize, err)
+			}
+		case int64(length) > int64(len(data)-HeaderSize):
+			if !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Fatalf("truncated body: err = %v", err)
+			}
+		default:
+			if err != nil {
+				t.Fatalf("valid frame rejected: %v", err)
+			}
+			if !bytes.Equal(frame.Body, data[HeaderSize:HeaderSize+int(length)]) {
+				t.Fatal("body does not match the input")
+			}
+		}
+	})
+}
+
+func TestReadFrameBogusLengthDoesNotAllocate(t *testing.T) {
+	const advertised = 512 << 20
+	data := append(header(HeaderMagic, 1, advertised), "only a few bytes"...)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	_, err := ReadFrame(bytes.NewReader(data), 1<<30)
+	runtime.ReadMemStats(&after)
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("err = %v, want unexpected EOF", err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 4*frameReadChunk {
+		t.Errorf("allocated %d bytes for a %d byte frame that sent %d", allocated, advertised, len(data)-HeaderSize)
+	}
+}
@@ -0,0 +1,326 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxAbandonedFactor bounds unanswered requests on a multiplexed connection, as a
+// multiple of the in-flight limit, before the connection is considered hung
+const maxAbandonedFactor = 4
+
+// ErrPipeliningUnsupported is matched by errors from a connection whose server mishandled
+// pipelined requests; the client falls back to serial mode after it
+var ErrPipeliningUnsupported = errors.New("server does not support pipelined requests")
+
+// SetPipelining lets up to maxInFlight requests share the connection, with responses
+// matched to callers by request ID, so a slow command no longer holds up quick ones.
+// Values below 2 keep the serial mode. It takes effect on the next connect.
+func (c *Client) SetPipelining(maxInFlight int) {
+	c.maxInFlight = maxInFlight
+}
+
+// muxResult is a response delivered to a waiting caller
+type muxResult struct {
+	resp *Response
+	err  error
+}
+
+// mux owns the read side of a connection in pipelined mode and dispatches responses to
+// callers by request ID
+type mux struct {
+	conn    net.Conn
+	xorKey  []byte
+	maxSize int
+	slots   chan struct{} // Bounds requests in flight
+	done    chan struct{} // Closed once the reader has stopped
+
+	mu        sync.Mutex
+	waiters   map[uint32]chan muxResult
+	abandoned map[uint32]struct{} // Requests given up on whose responses may still arrive
+	proven    bool                // A response arrived while another request was outstanding
+	err       error               // Why the reader stopped
+}
+
+func newMux(conn net.Conn, xorKey []byte, maxSize, maxInFlight int) *mux {
+	m := &mux{
+		conn:      conn,
+		xorKey:    xorKey,
+		maxSize:   maxSize,
+		slots:     make(chan struct{}, maxInFlight),
+		done:      make(chan struct{}),
+		waiters:   make(map[uint32]chan muxResult),
+		abandoned: make(map[uint32]struct{}),
+	}
+	go m.read()
+	return m
+}
+
+// read dispatches frames until the connection fails
+func (m *mux) read() {
+	for {
+		frame, err := ReadFrame(m.conn, m.maxSize)
+		if err != nil {
+			m.fail(fmt.Errorf("failed to read response: %w", err))
+			return
+		}
+		m.dispatch(frame)
+	}
+}
+
+// dispatch delivers a frame to the caller waiting for its ID
+func (m *mux) dispatch(frame Frame) {
+	m.mu.Lock()
+	ch, waiting := m.waiters[frame.ID]
+	if waiting {
+		delete(m.waiters, frame.ID)
+		if len(m.waiters) > 0 {
+			m.proven = true
+		}
+	}
+	_, abandoned := m.abandoned[frame.ID]
+	delete(m.abandoned, frame.ID)
+	proven := m.proven
+	m.mu.Unlock()
+
+	switch {
+	case waiting:
+		body := frame.Body
+		if len(m.xorKey) > 0 {
+			body = XOR(body, m.xorKey)
+		}
+		resp, err := decodeResponse(body)
+		ch <- muxResult{resp: resp, err: err}
+	case abandoned:
+		slog.Debug("Discarding late RCON response", "id", frame.ID)
+	case proven:
+		slog.Warn("Discarding unexpected RCON response", "id", frame.ID)
+	default:
+		// Before the server has answered concurrent requests correctly, an unknown ID
+		// most likely means it does not echo request IDs under concurrency
+		m.fail(fmt.Errorf("%w: unexpected response ID %d", ErrPipeliningUnsupported, frame.ID))
+	}
+}
+
+// fail stops the mux, closing the connection and failing every waiting caller
+func (m *mux) fail(err error) {
+	m.mu.Lock()
+	if m.err != nil {
+		m.mu.Unlock()
+		return
+	}
+	if len(m.waiters) > 1 && !m.proven && !errors.Is(err, ErrPipeliningUnsupported) {
+		err = fmt.Errorf("%w: connection lost with %d requests in flight: %w", ErrPipeliningUnsupported, len(m.waiters), err)
+	}
+	m.err = err
+	waiters := m.waiters
+	m.waiters = nil
+	m.mu.Unlock()
+
+	m.conn.Close()
+	close(m.done)
+	for _, ch := range waiters {
+		ch <- muxResult{err: &connError{err: err, sent: true}}
+	}
+}
+
+// failure returns why the mux stopped, or nil while it is running
+func (m *mux) failure() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+// acquire takes an in-flight slot, waiting for one to free up
+func (m *mux) acquire(ctx context.Context) error {
+	select {
+	case m.slots <- struct{}{}:
+		return nil
+	case <-m.done:
+		return &connError{err: m.failure()}
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for a pipeline slot: %w", ctx.Err())
+	}
+}
+
+func (m *mux) release() {
+	<-m.slots
+}
+
+// register prepares to receive the response to id; it must precede sending the request
+func (m *mux) register(id uint32) (chan muxResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, &connError{err: m.err}
+	}
+	ch := make(chan muxResult, 1)
+	m.waiters[id] = ch
+	return ch, nil
+}
+
+// abandon stops waiting for id, discarding its response if it still arrives
+func (m *mux) abandon(id uint32) {
+	m.mu.Lock()
+	if _, ok := m.waiters[id]; !ok {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.waiters, id)
+	m.abandoned[id] = struct{}{}
+	hung := len(m.abandoned) > maxAbandonedFactor*cap(m.slots)
+	m.mu.Unlock()
+
+	if hung {
+		m.fail(errors.New("too many unanswered requests"))
+	}
+}
+
+// muxCall is a request sent on a multiplexed connection, awaiting its response
+type muxCall struct {
+	m        *mux
+	id       uint32
+	ch       chan muxResult
+	command  string
+	deadline time.Time
+}
+
+// sendMux sends a request on the multiplexed connection without waiting for the response
+// (caller must hold lock)
+func (c *Client) sendMux(ctx context.Context, command string, contentBody any) (*muxCall, error) {
+	m := c.mux
+	if err := m.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	data, requestID, err := c.packRequest(command, contentBody)
+	if err != nil {
+		m.release()
+		return nil, err
+	}
+	ch, err := m.register(requestID)
+	if err != nil {
+		m.release()
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.timeouts.For(command))
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn := c.conn
+	conn.SetWriteDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetWriteDeadline(time.Now())
+	})
+	err = c.sendRequest(ctx, data, requestID)
+	stop()
+	if err != nil {
+		// A partial write leaves the stream unusable for every caller
+		m.fail(err)
+		m.release()
+		return nil, err
+	}
+	conn.SetWriteDeadline(time.Time{})
+
+	return &muxCall{m: m, id: requestID, ch: ch, command: command, deadline: deadline}, nil
+}
+
+// wait blocks until the response arrives, the deadline passes or ctx is done. A timeout
+// leaves the connection up; the late response is discarded when it arrives.
+func (call *muxCall) wait(ctx context.Context) (_ *Response, err error) {
+	_, span := tracer.Start(ctx, "rcon.read")
+	span.SetAttributes(attribute.Int64("rcon.request_id", int64(call.id)))
+	defer func() {
+		recordOutcome(span, nil, err)
+		span.End()
+		call.m.release()
+	}()
+
+	timer := time.NewTimer(time.Until(call.deadline))
+	defer timer.Stop()
+
+	select {
+	case r := <-call.ch:
+		return r.resp, r.err
+	case <-timer.C:
+		call.m.abandon(call.id)
+		return nil, fmt.Errorf("%s timed out: %w", call.command, context.DeadlineExceeded)
+	case <-ctx.Done():
+		call.m.abandon(call.id)
+		return nil, fmt.Errorf("%s: %w", call.command, ctx.Err())
+	}
+}
+
+// executeMux sends a command on the multiplexed connection and waits for its response
+// with the lock released, so other callers can send theirs meanwhile. The caller must
+// hold the lock; it is released before executeMux returns. If the connection is lost,
+// the lock is only held to reconnect, and a retry goes through execute again so it
+// waits for its response without the lock too.
+func (c *Client) executeMux(ctx context.Context, command string, contentBody any, retry bool) (*Response, error) {
+	slog.Debug("Executing RCON command", "command", command, "pipelined", true)
+	start := time.Now()
+	call, err := c.sendMux(ctx, command, contentBody)
+	c.unlock()
+
+	var resp *Response
+	if err == nil {
+		resp, err = call.wait(ctx)
+	}
+	c.observeExchange(command, contentBody, start, resp, err)
+
+	var ce *connError
+	if errors.As(err, &ce) && ctx.Err() == nil {
+		slog.Warn("RCON connection lost", "command", command, "error", err)
+		if lerr := c.lock(ctx); lerr != nil {
+			return nil, lerr
+		}
+		c.checkMuxUnlocked()
+		var rerr error
+		if c.conn == nil && !c.closed {
+			rerr = c.reconnectUnlocked(ctx)
+		}
+		c.unlock()
+		if rerr != nil {
+			return nil, fmt.Errorf("%w (%v)", err, rerr)
+		}
+		if retry && (!ce.sent || IsReadOnly(command)) {
+			slog.Debug("Retrying RCON command", "command", command)
+			return c.execute(ctx, command, contentBody, false)
+		}
+	}
+	if err != nil {
+		slog.Error("RCON command failed", "command", command, "error", err)
+		return nil, err
+	}
+	slog.Debug("RCON command completed", "command", command, "status", resp.StatusCode)
+	return resp, nil
+}
+
+// checkMuxUnlocked drops the connection once its reader has stopped, falling back to
+// serial mode if the server mishandled pipelined requests (caller must hold lock)
+func (c *Client) checkMuxUnlocked() {
+	if c.mux == nil {
+		return
+	}
+	err := c.mux.failure()
+	if err == nil {
+		return
+	}
+	if errors.Is(err, ErrPipeliningUnsupported) && c.maxInFlight > 1 {
+		slog.Warn("RCON server mishandled pipelined requests; falling back to serial mode",
+			"server", net.JoinHostPort(c.host, fmt.Sprint(c.port)), "error", err)
+		c.maxInFlight = 0
+	}
+	c.closeUnlocked()
+	if c.State() == StateConnected {
+		c.setState(StateDisconnected, err)
+	}
+}
@@ -0,0 +1,132 @@
+package rcon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+// pipeMux runs a mux on one end of an in-memory connection and returns the other end,
+// from which the test answers as the server
+func pipeMux(t *testing.T, maxInFlight int) (*mux, net.Conn) {
+	t.Helper()
+	client, server := net.Pipe()
+	m := newMux(client, nil, 1<<20, maxInFlight)
+	t.Cleanup(func() {
+		server.Close()
+		<-m.done
+	})
+	return m, server
+}
+
+// answer writes a response frame for id naming command
+func answer(t *testing.T, server net.Conn, id uint32, command string) {
+	t.Helper()
+	body := fmt.Sprintf(`{"statusCode":200,"statusMessage":"OK","version":2,"name":%q,"contentBody":""}`, command)
+	if _, err := server.Write(EncodeFrame(id, []byte(body))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func register(t *testing.T, m *mux, id uint32) chan muxResult {
+	t.Helper()
+	ch, err := m.register(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ch
+}
+
+// result waits for a dispatched response
+func result(t *testing.T, ch chan muxResult) muxResult {
+	t.Helper()
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(2 * time.Second):
+		t.Fatal("no response dispatched")
+		return muxResult{}
+	}
+}
+
+func TestMuxDispatchesOutOfOrderResponsesByID(t *testing.T) {
+	m, server := pipeMux(t, 4)
+	slow, quick := register(t, m, 1), register(t, m, 2)
+
+	answer(t, server, 2, "Quick")
+	if r := result(t, quick); r.err != nil || r.resp.Name != "Quick" {
+		t.Fatalf("request 2 got %+v", r)
+	}
+	answer(t, server, 1, "Slow")
+	if r := result(t, slow); r.err != nil || r.resp.Name != "Slow" {
+		t.Fatalf("request 1 got %+v", r)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.proven {
+		t.Error("answering one request while another was outstanding did not prove pipelining")
+	}
+}
+
+func TestMuxSlotLimit(t *testing.T) {
+	m, _ := pipeMux(t, 2)
+	for range 2 {
+		if err := m.acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("third acquire = %v, want it to wait for a slot", err)
+	}
+
+	m.release()
+	if err := m.acquire(context.Background()); err != nil {
+		t.Errorf("acquire after a release: %v", err)
+	}
+}
+
+func TestMuxDiscardsLateResponse(t *testing.T) {
+	m, server := pipeMux(t, 4)
+	register(t, m, 1)
+	kept := register(t, m, 2)
+	m.abandon(1)
+
+	answer(t, server, 1, "Late")
+	answer(t, server, 2, "Kept")
+	if r := result(t, kept); r.err != nil || r.resp.Name != "Kept" {
+		t.Fatalf("request 2 got %+v", r)
+	}
+	if err := m.failure(); err != nil {
+		t.Errorf("late response stopped the mux: %v", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.abandoned) != 0 {
+		t.Errorf("abandoned = %v after its response arrived", m.abandoned)
+	}
+}
+
+func TestMuxUnknownIDFallsBackToSerial(t *testing.T) {
+	m, server := pipeMux(t, 4)
+	waiting := register(t, m, 1)
+
+	answer(t, server, 99, "Stray")
+	r := result(t, waiting)
+	var ce *connError
+	if !errors.As(r.err, &ce) || !errors.Is(r.err, ErrPipeliningUnsupported) {
+		t.Fatalf("waiting caller got %v, want a connection error for unsupported pipelining", r.err)
+	}
+
+	c := &Client{maxInFlight: 4, conn: m.conn, mux: m}
+	c.checkMuxUnlocked()
+	if c.maxInFlight != 0 || c.mux != nil || c.conn != nil {
+		t.Errorf("after the failure maxInFlight = %d, mux = %v, conn = %v; want serial and disconnected", c.maxInFlight, c.mux, c.conn)
+	}
+}
@@ -0,0 +1,73 @@
+package rcon_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/rcon/rcontest"
+)
+
+func TestPipelinedRetryDoesNotHoldConnection(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetConcurrent(true)
+	srv.SetLatency("GetServerChangelist", 300*time.Millisecond)
+	srv.InjectFault("GetServerChangelist", rcontest.Fault{Kind: rcontest.FaultDisconnect})
+
+	client := rcon.NewClient(srv.Host(), srv.Port(), srv.Password, time.Second, 1<<20, 1<<20)
+	client.SetTimeouts(rcon.Timeouts{Default: 2 * time.Second})
+	client.SetReconnectPolicy(rcon.ReconnectPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+	client.SetPipelining(4)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+
+	// The first attempt is dropped after 300ms and the retry answered 300ms later; a
+	// broadcast sent meanwhile must not wait for the retry
+	done := make(chan string, 2)
+	go func() {
+		if _, err := client.GetServerChangelist(context.Background()); err != nil {
+			t.Errorf("GetServerChangelist: %v", err)
+		}
+		done <- "changelist"
+	}()
+	time.Sleep(450 * time.Millisecond)
+	go func() {
+		if err := client.ServerBroadcast(context.Background(), "hello"); err != nil {
+			t.Errorf("ServerBroadcast: %v", err)
+		}
+		done <- "broadcast"
+	}()
+
+	if first := <-done; first != "broadcast" {
+		t.Errorf("%s finished first; the retry held the connection", first)
+	}
+	<-done
+	if n := srv.Logins(); n != 2 {
+		t.Errorf("logins = %d, want 2", n)
+	}
+}
+
+func TestPipelinedUnknownIDRetriesSerially(t *testing.T) {
+	srv := newTestServer(t)
+	srv.SetResponse("GetServerChangelist", "12345")
+	srv.InjectFault("GetServerChangelist", rcontest.Fault{Kind: rcontest.FaultWrongID})
+
+	client := rcon.NewClient(srv.Host(), srv.Port(), srv.Password, time.Second, 1<<20, 1<<20)
+	client.SetTimeouts(rcon.Timeouts{Default: 2 * time.Second})
+	client.SetReconnectPolicy(rcon.ReconnectPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+	client.SetPipelining(4)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetServerChangelist(context.Background()); err != nil {
+		t.Fatalf("GetServerChangelist: %v", err)
+	}
+	if n := srv.Logins(); n != 2 {
+		t.Errorf("logins = %d, want a reconnect after the unknown ID", n)
+	}
+}
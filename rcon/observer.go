@@ -0,0 +1,46 @@
+package rcon
+
+import (
+	"net"
+	"time"
+)
+
+// Observer receives measurements of a client's traffic, e.g. for metrics. Methods are
+// called synchronously, concurrently when the client is pipelined, and must not block or
+// call back into the client.
+type Observer interface {
+	// ObserveExchange is called after every request/response round trip, including the
+	// ServerConnect and Login handshake. resp is nil when err is set.
+	ObserveExchange(command string, duration time.Duration, resp *Response, err error)
+	// ObserveBytes is called for every read and write on the connection
+	ObserveBytes(sent, received int)
+	// ObserveReconnect is called once per reconnect with its outcome
+	ObserveReconnect(err error)
+}
+
+// SetObserver registers an observer for the client's traffic
+func (c *Client) SetObserver(o Observer) {
+	c.observer = o
+}
+
+// countingConn reports bytes moved over a connection to an observer
+type countingConn struct {
+	net.Conn
+	observer Observer
+}
+
+func (cc *countingConn) Read(b []byte) (int, error) {
+	n, err := cc.Conn.Read(b)
+	if n > 0 {
+		cc.observer.ObserveBytes(0, n)
+	}
+	return n, err
+}
+
+func (cc *countingConn) Write(b []byte) (int, error) {
+	n, err := cc.Conn.Write(b)
+	if n > 0 {
+		cc.observer.ObserveBytes(n, 0)
+	}
+	return n, err
+}
@@ -1,10 +1,11 @@
 package rcon
 
 import (
+	"bytes"
 	"encoding/base64"
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sync/atomic"
 )
 
@@ -37,21 +38,9 @@ type Response struct {
 func PackRequest(authToken, command string, contentBody any) ([]byte, uint32, error) {
 	requestID := atomic.AddUint32(&requestIDCounter, 1)
 
-	// ContentBody must always be a string
-	// If it's not a string, JSON-encode it to a string first
-	var contentBodyStr string
-	switch v := contentBody.(type) {
-	case string:
-		contentBodyStr = v
-	case nil:
-		contentBodyStr = ""
-	default:
-		// JSON-encode non-string content to a string
-		encoded, err := json.Marshal(contentBody)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to marshal contentBody: %w", err)
-		}
-		contentBodyStr = string(encoded)
+	contentBodyStr, err := encodeContentBody(contentBody)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	req := Request{
@@ -66,39 +55,48 @@ func PackRequest(authToken, command string, contentBody any) ([]byte, uint32, er
 		return nil, 0, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	// Create header: [magic (4 bytes)][requestID (4 bytes)][contentLength (4 bytes)]
-	header := make([]byte, HeaderSize)
-	binary.LittleEndian.PutUint32(header[0:4], HeaderMagic)
-	binary.LittleEndian.PutUint32(header[4:8], requestID)
-	binary.LittleEndian.PutUint32(header[8:12], uint32(len(jsonBody)))
+	// Header: [magic (4 bytes)][requestID (4 bytes)][contentLength (4 bytes)]
+	return EncodeFrame(requestID, jsonBody), requestID, nil
+}
 
-	return append(header, jsonBody...), requestID, nil
+// encodeContentBody converts a content body to the string sent on the wire. ContentBody
+// must always be a string; anything else is JSON-encoded first.
+func encodeContentBody(contentBody any) (string, error) {
+	switch v := contentBody.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	default:
+		encoded, err := json.Marshal(contentBody)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal contentBody: %w", err)
+		}
+		return string(encoded), nil
+	}
 }
 
 // UnpackResponse deserializes a response from bytes
 func UnpackResponse(data []byte) (*Response, uint32, error) {
-	if len(data) < HeaderSize {
-		return nil, 0, fmt.Errorf("response too short: %d bytes", len(data))
-	}
-
-	magic := binary.LittleEndian.Uint32(data[0:4])
-	if magic != HeaderMagic {
-		return nil, 0, fmt.Errorf("invalid header magic: expected 0x%08X, got 0x%08X", HeaderMagic, magic)
+	// The length is bounded by data itself; a longer claim reads as truncated
+	frame, err := ReadFrame(bytes.NewReader(data), math.MaxInt32)
+	if err != nil {
+		return nil, 0, err
 	}
-
-	requestID := binary.LittleEndian.Uint32(data[4:8])
-	contentLength := binary.LittleEndian.Uint32(data[8:12])
-
-	if len(data) < HeaderSize+int(contentLength) {
-		return nil, 0, fmt.Errorf("incomplete response body")
+	resp, err := decodeResponse(frame.Body)
+	if err != nil {
+		return nil, 0, err
 	}
+	return resp, frame.ID, nil
+}
 
+// decodeResponse unmarshals a decrypted response body
+func decodeResponse(body []byte) (*Response, error) {
 	var resp Response
-	if err := json.Unmarshal(data[HeaderSize:HeaderSize+contentLength], &resp); err != nil {
-		return nil, 0, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
-
-	return &resp, requestID, nil
+	return &resp, nil
 }
 
 // XOR encrypts/decrypts data with the given key
@@ -0,0 +1,83 @@
+package rcontest
+
+import (
+	"sync"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+// replayQueue hands out recorded responses in order, repeating the last one
+type replayQueue struct {
+	responses []rcon.Response
+	next      int
+}
+
+func (q *replayQueue) pop() rcon.Response {
+	resp := q.responses[min(q.next, len(q.responses)-1)]
+	q.next++
+	return resp
+}
+
+// Replay scripts the server with the responses recorded in a cassette. A request is
+// answered with the responses recorded for the same command and content body, in order,
+// repeating the last one once they run out; a content body that was never recorded gets
+// the command's responses in recorded order instead. The handshake and exchanges that
+// failed without a response are not replayed.
+func (s *Server) Replay(interactions []rcon.Interaction) {
+	type track struct {
+		mu     sync.Mutex
+		byBody map[string]*replayQueue
+		all    replayQueue
+	}
+
+	tracks := make(map[string]*track)
+	for _, i := range interactions {
+		if i.Response == nil || i.Command == "ServerConnect" || i.Command == "Login" {
+			continue
+		}
+		t, ok := tracks[i.Command]
+		if !ok {
+			t = &track{byBody: make(map[string]*replayQueue)}
+			tracks[i.Command] = t
+		}
+
+		resp := *i.Response
+		resp.ContentBody = encodeContent(resp.ContentBody)
+		if resp.Version == 0 {
+			resp.Version = rcon.Version
+		}
+		if resp.Name == "" {
+			resp.Name = i.Command
+		}
+
+		q, ok := t.byBody[i.ContentBody]
+		if !ok {
+			q = &replayQueue{}
+			t.byBody[i.ContentBody] = q
+		}
+		q.responses = append(q.responses, resp)
+		t.all.responses = append(t.all.responses, resp)
+	}
+
+	for command, t := range tracks {
+		s.Handle(command, func(contentBody string) rcon.Response {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if q, ok := t.byBody[contentBody]; ok {
+				return q.pop()
+			}
+			return t.all.pop()
+		})
+	}
+}
+
+// NewReplayServer starts a server that replays the cassette at path
+func NewReplayServer(password, path string) (*Server, error) {
+	interactions, err := rcon.LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	s := NewServer(password)
+	s.Replay(interactions)
+	return s, nil
+}
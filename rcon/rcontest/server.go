@@ -0,0 +1,377 @@
+// Package rcontest provides a scriptable in-process HLL RCON V2 server for
+// exercising rcon.Client and the layers built on it without a game server.
+package rcontest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+// HandlerFunc produces the response for a command given its decoded content body
+type HandlerFunc func(contentBody string) rcon.Response
+
+// FaultKind selects how the server misbehaves for a faulted command
+type FaultKind int
+
+const (
+	FaultNone       FaultKind = iota
+	FaultDisconnect           // Close the connection instead of responding
+	FaultBadMagic             // Respond with a corrupted header magic
+	FaultWrongID              // Respond with a request ID that does not match
+	FaultOversized            // Advertise a content length larger than any client limit
+	FaultTruncated            // Send the header and half of the body, then close
+	FaultGarbage              // Send a well-framed body that is not JSON
+	FaultStale                // Send a response to the previous request ID before the real one
+)
+
+// maxRequestSize bounds request frames read by the fake server
+const maxRequestSize = 16 << 20
+
+// Fault describes a one-shot misbehaviour for the next matching command
+type Fault struct {
+	Kind  FaultKind
+	Delay time.Duration // Extra latency before the fault (or response) is applied
+}
+
+// Server is a fake RCON V2 server listening on a loopback address
+type Server struct {
+	Password string
+
+	listener net.Listener
+	xorKey   []byte
+
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc
+	faults   map[string][]Fault
+	latency  map[string]time.Duration
+	conns    map[net.Conn]struct{}
+	requests []rcon.Request
+	logins   int
+	parallel bool
+
+	wg sync.WaitGroup
+}
+
+// NewServer starts a fake server that accepts the given password
+func NewServer(password string) *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("rcontest: failed to listen: %v", err))
+	}
+
+	key := make([]byte, 16)
+	rand.Read(key)
+
+	s := &Server{
+		Password: password,
+		listener: ln,
+		xorKey:   key,
+		handlers: make(map[string]HandlerFunc),
+		faults:   make(map[string][]Fault),
+		latency:  make(map[string]time.Duration),
+		conns:    make(map[net.Conn]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return s
+}
+
+// Addr returns the host:port the server listens on
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Host returns the listening host
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.Addr())
+	return host
+}
+
+// Port returns the listening port
+func (s *Server) Port() int {
+	_, port, _ := net.SplitHostPort(s.Addr())
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+// Handle registers a handler for a command, replacing any previous one
+func (s *Server) Handle(command string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[command] = h
+}
+
+// SetResponse registers a canned 200 response. Non-string content is JSON-encoded
+// into a string, as the game server does.
+func (s *Server) SetResponse(command string, contentBody any) {
+	s.SetStatus(command, 200, "OK", contentBody)
+}
+
+// SetStatus registers a canned response with an explicit status
+func (s *Server) SetStatus(command string, statusCode int, statusMessage string, contentBody any) {
+	body := encodeContent(contentBody)
+	s.Handle(command, func(string) rcon.Response {
+		return rcon.Response{
+			StatusCode:    statusCode,
+			StatusMessage: statusMessage,
+			Version:       rcon.Version,
+			Name:          command,
+			ContentBody:   body,
+		}
+	})
+}
+
+// SetLatency delays every response to command by d
+func (s *Server) SetLatency(command string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency[command] = d
+}
+
+// SetConcurrent makes the server answer each request after the handshake in its own
+// goroutine, so a slow command does not hold back later ones, like a server that
+// supports pipelined requests
+func (s *Server) SetConcurrent(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parallel = on
+}
+
+// InjectFault queues a fault for the next occurrence of command. Faults queue up
+// and are consumed in order.
+func (s *Server) InjectFault(command string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[command] = append(s.faults[command], f)
+}
+
+// Requests returns every request received so far, decrypted
+func (s *Server) Requests() []rcon.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]rcon.Request(nil), s.requests...)
+}
+
+// Logins returns the number of successful Login handshakes
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// DropConnections closes every open client connection, simulating a server restart
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+		delete(s.conns, conn)
+	}
+}
+
+// Close stops the listener and drops all connections
+func (s *Server) Close() {
+	s.listener.Close()
+	s.DropConnections()
+	s.wg.Wait()
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serve(conn)
+		}()
+	}
+}
+
+// connState is the per-connection handshake state
+type connState struct {
+	encrypted bool
+	authToken string
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	var st connState
+	var writeMu sync.Mutex
+	for {
+		frame, err := rcon.ReadFrame(conn, maxRequestSize)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				slog.Debug("rcontest: bad request frame", "error", err)
+			}
+			return
+		}
+		requestID, body := frame.ID, frame.Body
+		if st.encrypted {
+			body = rcon.XOR(body, s.xorKey)
+		}
+
+		var req rcon.Request
+		if err := json.Unmarshal(body, &req); err != nil {
+			slog.Debug("rcontest: bad request body", "error", err)
+			return
+		}
+
+		s.mu.Lock()
+		parallel := s.parallel && st.authToken != ""
+		s.mu.Unlock()
+		resp, fault, delay := s.dispatch(&st, req)
+		if parallel {
+			go func() {
+				time.Sleep(delay)
+				writeMu.Lock()
+				defer writeMu.Unlock()
+				if err := s.respond(conn, &st, requestID, resp, fault); err != nil {
+					conn.Close()
+				}
+			}()
+			continue
+		}
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		writeMu.Lock()
+		err = s.respond(conn, &st, requestID, resp, fault)
+		writeMu.Unlock()
+		if err != nil {
+			return
+		}
+		// The key applies to everything after the ServerConnect exchange
+		if req.Name == "ServerConnect" {
+			st.encrypted = true
+		}
+	}
+}
+
+// dispatch resolves the response, pending fault and latency for a request
+func (s *Server) dispatch(st *connState, req rcon.Request) (rcon.Response, FaultKind, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+
+	delay := s.latency[req.Name]
+	fault := FaultNone
+	if queue := s.faults[req.Name]; len(queue) > 0 {
+		fault = queue[0].Kind
+		delay += queue[0].Delay
+		s.faults[req.Name] = queue[1:]
+	}
+
+	resp := rcon.Response{Version: rcon.Version, Name: req.Name}
+	switch {
+	case req.Name == "ServerConnect":
+		resp.StatusCode, resp.StatusMessage = 200, "OK"
+		resp.ContentBody = base64.StdEncoding.EncodeToString(s.xorKey)
+	case req.Name == "Login":
+		if req.ContentBody != s.Password {
+			resp.StatusCode, resp.StatusMessage = 401, "Invalid password"
+			resp.ContentBody = ""
+			break
+		}
+		token := make([]byte, 16)
+		rand.Read(token)
+		st.authToken = hex.EncodeToString(token)
+		s.logins++
+		resp.StatusCode, resp.StatusMessage = 200, "OK"
+		resp.ContentBody = st.authToken
+	case st.authToken == "" || req.AuthToken != st.authToken:
+		resp.StatusCode, resp.StatusMessage = 401, "Unauthorized"
+		resp.ContentBody = ""
+	default:
+		h, ok := s.handlers[req.Name]
+		if !ok {
+			resp.StatusCode, resp.StatusMessage = 400, "Unknown command"
+			resp.ContentBody = ""
+			break
+		}
+		resp = h(req.ContentBody)
+	}
+	return resp, fault, delay
+}
+
+func (s *Server) respond(conn net.Conn, st *connState, requestID uint32, resp rcon.Response, fault FaultKind) error {
+	if fault == FaultDisconnect {
+		return errors.New("fault: disconnect")
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	if fault == FaultGarbage {
+		body = []byte("not json {")
+	}
+	if st.encrypted {
+		body = rcon.XOR(body, s.xorKey)
+	}
+
+	data := rcon.EncodeFrame(requestID, body)
+	switch fault {
+	case FaultBadMagic:
+		binary.LittleEndian.PutUint32(data[0:4], 0xBADC0DE)
+	case FaultWrongID:
+		binary.LittleEndian.PutUint32(data[4:8], requestID+1000)
+	case FaultOversized:
+		binary.LittleEndian.PutUint32(data[8:12], 0xFFFFFFF0)
+	case FaultTruncated:
+		conn.Write(data[:rcon.HeaderSize+len(body)/2])
+		return errors.New("fault: truncated")
+	case FaultStale:
+		// A duplicate of an earlier response precedes the real one
+		if _, err := conn.Write(rcon.EncodeFrame(requestID-1, body)); err != nil {
+			return err
+		}
+	}
+
+	_, err = conn.Write(data)
+	return err
+}
+
+// encodeContent mirrors the server's habit of returning JSON payloads as strings
+func encodeContent(contentBody any) string {
+	switch v := contentBody.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			panic(fmt.Sprintf("rcontest: failed to encode content body: %v", err))
+		}
+		return string(encoded)
+	}
+}
@@ -0,0 +1,111 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidateContent checks a content body against the command's dialogue parameters and
+// returns it with keys normalised to the parameter IDs. Every parameter must be present;
+// unknown keys and values of the wrong type are rejected.
+func (r *CommandReference) ValidateContent(body map[string]any) (map[string]any, error) {
+	params := make(map[string]DialogueParameter, len(r.DialogueParameters))
+	for _, p := range r.DialogueParameters {
+		params[strings.ToLower(p.ID)] = p
+	}
+
+	normalized := make(map[string]any, len(body))
+	for key, value := range body {
+		p, ok := params[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown parameter %q", r.Name, key)
+		}
+		if err := p.check(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Name, err)
+		}
+		normalized[p.ID] = value
+	}
+
+	for _, p := range r.DialogueParameters {
+		if _, ok := normalized[p.ID]; !ok {
+			return nil, fmt.Errorf("%s: missing parameter %q", r.Name, p.ID)
+		}
+	}
+	return normalized, nil
+}
+
+// check validates a single value against the parameter's declared type
+func (p DialogueParameter) check(value any) error {
+	switch strings.ToLower(p.Type) {
+	case "number", "int", "integer", "float", "slider":
+		switch v := value.(type) {
+		case float64, json.Number:
+			return nil
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("parameter %q must be a number", p.ID)
+	case "bool", "boolean", "checkbox", "toggle":
+		switch v := value.(type) {
+		case bool:
+			return nil
+		case string:
+			if _, err := strconv.ParseBool(v); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("parameter %q must be a boolean", p.ID)
+	default:
+		// Text and list-backed parameters; the server does its own validation
+		switch value.(type) {
+		case string, float64, json.Number, bool:
+			return nil
+		}
+		return fmt.Errorf("parameter %q must be a scalar value", p.ID)
+	}
+}
+
+// ParseContent validates a raw JSON body, an object of parameters or a plain string, and
+// converts it to the content body to send
+func (r *CommandReference) ParseContent(raw json.RawMessage) (any, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		raw = []byte("{}")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid body: %w", err)
+	}
+
+	switch body := value.(type) {
+	case map[string]any:
+		if len(body) == 0 && len(r.DialogueParameters) == 0 {
+			return "", nil
+		}
+		return r.ValidateContent(body)
+	case string:
+		if len(r.DialogueParameters) > 0 {
+			return nil, fmt.Errorf("%s expects an object with parameters %s", r.Name, r.parameterIDs())
+		}
+		return body, nil
+	default:
+		return nil, fmt.Errorf("body must be an object or a string")
+	}
+}
+
+func (r *CommandReference) parameterIDs() string {
+	ids := make([]string, len(r.DialogueParameters))
+	for i, p := range r.DialogueParameters {
+		ids[i] = p.ID
+	}
+	return strings.Join(ids, ", ")
+}
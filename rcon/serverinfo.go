@@ -0,0 +1,144 @@
+package rcon
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// PlayerList is the GetServerInformation players payload
+type PlayerList struct {
+	Players []Player `json:"players"`
+}
+
+// MapEntry is a map in the rotation or sequence
+type MapEntry struct {
+	Name      string `json:"name"`
+	GameMode  string `json:"game_mode"`
+	TimeOfDay string `json:"time_of_day"`
+	ID        string `json:"id"`
+	Position  int    `json:"position"`
+}
+
+// MapList is the GetServerInformation maprotation payload
+type MapList struct {
+	Maps []MapEntry `json:"maps"`
+}
+
+// MapSequence is the GetServerInformation mapsequence payload
+type MapSequence struct {
+	Maps         []MapEntry `json:"maps"`
+	CurrentIndex int        `json:"current_index"` // Index in Maps of the map being played
+}
+
+// ServerConfig is the GetServerInformation serverconfig payload
+type ServerConfig struct {
+	ServerName         string   `json:"server_name"`
+	BuildNumber        string   `json:"build_number"`
+	BuildRevision      string   `json:"build_revision"`
+	SupportedPlatforms []string `json:"supported_platforms"`
+	PasswordProtected  bool     `json:"password_protected"`
+}
+
+// VIPPlayer is an entry of the GetServerInformation vipplayers payload
+type VIPPlayer struct {
+	ID      string `json:"id"`
+	Comment string `json:"comment"`
+}
+
+// VIPList is the GetServerInformation vipplayers payload
+type VIPList struct {
+	VIPPlayers []VIPPlayer `json:"vip_players"`
+}
+
+// BannedWordList is the GetServerInformation bannedwords payload
+type BannedWordList struct {
+	BannedWords []string `json:"banned_words"`
+}
+
+// serverInfoTypes maps each GetServerInformation type to the payload it decodes into
+var serverInfoTypes = map[string]func() any{
+	"session":      func() any { return new(SessionInfo) },
+	"players":      func() any { return new(PlayerList) },
+	"player":       func() any { return new(Player) },
+	"maprotation":  func() any { return new(MapList) },
+	"mapsequence":  func() any { return new(MapSequence) },
+	"serverconfig": func() any { return new(ServerConfig) },
+	"vipplayers":   func() any { return new(VIPList) },
+	"bannedwords":  func() any { return new(BannedWordList) },
+}
+
+// ServerInfoTypes returns the known GetServerInformation types in sorted order
+func ServerInfoTypes() []string {
+	types := make([]string, 0, len(serverInfoTypes))
+	for name := range serverInfoTypes {
+		types = append(types, name)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// IsServerInfoType reports whether name is a known GetServerInformation type
+func IsServerInfoType(name string) bool {
+	_, ok := serverInfoTypes[name]
+	return ok
+}
+
+// DecodeServerInfo decodes a GetServerInformation content body of the given type into
+// its typed payload, e.g. *SessionInfo for "session"
+func DecodeServerInfo(infoType string, contentBody any) (any, error) {
+	newPayload, ok := serverInfoTypes[infoType]
+	if !ok {
+		return nil, fmt.Errorf("unknown server information type %q", infoType)
+	}
+	payload := newPayload()
+	if err := DecodeContent(contentBody, payload); err != nil {
+		return nil, fmt.Errorf("%s: %w", infoType, err)
+	}
+	return payload, nil
+}
+
+// GetMapRotation returns the maps in the rotation
+func (c *Client) GetMapRotation(ctx context.Context) ([]MapEntry, error) {
+	var list MapList
+	if err := c.GetServerInformation(ctx, "maprotation", "", &list); err != nil {
+		return nil, err
+	}
+	return list.Maps, nil
+}
+
+// GetMapSequence returns the map sequence and where the server is in it
+func (c *Client) GetMapSequence(ctx context.Context) (*MapSequence, error) {
+	var sequence MapSequence
+	if err := c.GetServerInformation(ctx, "mapsequence", "", &sequence); err != nil {
+		return nil, err
+	}
+	return &sequence, nil
+}
+
+// GetServerConfig returns the server name, build and supported platforms
+func (c *Client) GetServerConfig(ctx context.Context) (*ServerConfig, error) {
+	var config ServerConfig
+	if err := c.GetServerInformation(ctx, "serverconfig", "", &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetVIPPlayers returns the VIP list
+func (c *Client) GetVIPPlayers(ctx context.Context) ([]VIPPlayer, error) {
+	var list VIPList
+	if err := c.GetServerInformation(ctx, "vipplayers", "", &list); err != nil {
+		return nil, err
+	}
+	return list.VIPPlayers, nil
+}
+
+// GetBannedWords returns the chat profanity filter
+func (c *Client) GetBannedWords(ctx context.Context) ([]string, error) {
+	var list BannedWordList
+	if err := c.GetServerInformation(ctx, "bannedwords", "", &list); err != nil {
+		return nil, err
+	}
+	return list.BannedWords, nil
+}
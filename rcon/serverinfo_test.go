@@ -0,0 +1,137 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// serverInfoPayloads holds a fully populated snake_case payload per information type
+var serverInfoPayloads = map[string]string{
+	"session": `{"server_name":"EU #1","map_name":"CARENTAN","map_id":"carentan_warfare","game_mode":"Warfare",
+		"remaining_match_time":3600,"match_time":5400,"allied_faction":0,"axis_faction":1,"allied_score":2,"axis_score":3,
+		"max_player_count":100,"player_count":98,"allied_player_count":49,"axis_player_count":49,
+		"max_queue_count":6,"queue_count":4,"max_vip_queue_count":2,"vip_queue_count":1}`,
+	"players": `{"players":[{"name":"Able","clan_tag":"[7TH]","id":"76561198000000001","platform":"steam","eos_id":"e1",
+		"level":120,"team":0,"role":3,"platoon":"ABLE","loadout":"Rifleman","kills":12,"deaths":4,
+		"score_data":{"combat":140,"offense":60,"defense":20,"support":10},"world_position":{"x":1.5,"y":-2,"z":0.25}}]}`,
+	"player": `{"name":"Able","clan_tag":"[7TH]","id":"76561198000000001","platform":"steam","eos_id":"e1",
+		"level":120,"team":1,"role":3,"platoon":"ABLE","loadout":"Rifleman","kills":12,"deaths":4,
+		"score_data":{"combat":140,"offense":60,"defense":20,"support":10},"world_position":{"x":1.5,"y":-2,"z":0.25}}`,
+	"maprotation":  `{"maps":[{"name":"CARENTAN","game_mode":"Warfare","time_of_day":"Day","id":"carentan_warfare","position":0}]}`,
+	"mapsequence":  `{"maps":[{"name":"FOY","game_mode":"Offensive","time_of_day":"Night","id":"foy_offensive_ger","position":1}],"current_index":0}`,
+	"serverconfig": `{"server_name":"EU #1","build_number":"1234","build_revision":"abc","supported_platforms":["steam","epic"],"password_protected":true}`,
+	"vipplayers":   `{"vip_players":[{"id":"76561198000000001","comment":"donor"}]}`,
+	"bannedwords":  `{"banned_words":["foo","bar"]}`,
+}
+
+// rekey rewrites every object key of decoded JSON with fn
+func rekey(v any, fn func(string) string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, inner := range t {
+			out[fn(k)] = rekey(inner, fn)
+		}
+		return out
+	case []any:
+		for i, inner := range t {
+			t[i] = rekey(inner, fn)
+		}
+	}
+	return v
+}
+
+func pascalCase(key string) string {
+	parts := strings.Split(key, "_")
+	for i, p := range parts {
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+func camelCase(key string) string {
+	pascal := pascalCase(key)
+	return strings.ToLower(pascal[:1]) + pascal[1:]
+}
+
+// canonical re-encodes JSON with sorted keys and no whitespace
+func canonical(t *testing.T, data []byte) string {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestDecodeServerInfoKeyStyles(t *testing.T) {
+	for _, infoType := range ServerInfoTypes() {
+		snake, ok := serverInfoPayloads[infoType]
+		if !ok {
+			t.Errorf("no test payload for %s", infoType)
+			continue
+		}
+		want := canonical(t, []byte(snake))
+
+		styles := map[string]func(string) string{
+			"snake":  func(k string) string { return k },
+			"camel":  camelCase,
+			"pascal": pascalCase,
+		}
+		for style, fn := range styles {
+			t.Run(infoType+"/"+style, func(t *testing.T) {
+				var raw any
+				if err := json.Unmarshal([]byte(snake), &raw); err != nil {
+					t.Fatal(err)
+				}
+				body, err := json.Marshal(rekey(raw, fn))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				payload, err := DecodeServerInfo(infoType, string(body))
+				if err != nil {
+					t.Fatalf("DecodeServerInfo: %v", err)
+				}
+				got, err := json.Marshal(payload)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if canonical(t, got) != want {
+					t.Errorf("decoded %s\n got %s\nwant %s", body, canonical(t, got), want)
+				}
+			})
+		}
+	}
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(`{"Players":[{"iD":76561198000000001,"ClanTag":"[7TH]","scoreData":{"COMBAT":5},"extraField":1}]}`)))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(normalizeKeys(raw, reflect.TypeOf(&PlayerList{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"players":[{"clan_tag":"[7TH]","extraField":1,"id":"76561198000000001","score_data":{"combat":5}}]}`
+	if string(got) != want {
+		t.Errorf("normalizeKeys\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestDecodeServerInfoUnknownType(t *testing.T) {
+	if _, err := DecodeServerInfo("nonsense", "{}"); err == nil {
+		t.Error("decoded an unknown type")
+	}
+}
@@ -0,0 +1,43 @@
+package rcon
+
+import (
+	"context"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records spans through the global provider; they are dropped unless the
+// application installs one
+var tracer = otel.Tracer("github.com/Sledro/hllrcon/rcon")
+
+func (c *Client) serverAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("server.address", c.host),
+		attribute.Int("server.port", c.port),
+	}
+}
+
+// startCommandSpan starts the span covering one command, including the wait for the connection
+func (c *Client) startCommandSpan(ctx context.Context, command string) (context.Context, trace.Span) {
+	attrs := append(c.serverAttributes(), attribute.String("rcon.command", command))
+	return tracer.Start(ctx, "rcon "+command, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// recordOutcome records a command's outcome on span without ending it
+func recordOutcome(span trace.Span, resp *Response, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	if resp != nil {
+		span.SetAttributes(attribute.Int("rcon.status_code", resp.StatusCode))
+		if resp.StatusCode != 200 {
+			span.SetStatus(codes.Error, strconv.Itoa(resp.StatusCode)+" "+resp.StatusMessage)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package rcon
+
+// Player is a connected player as reported by GetServerInformation players/player
+type Player struct {
+	Name          string        `json:"name"`
+	ClanTag       string        `json:"clan_tag"`
+	ID            string        `json:"id"`
+	Platform      string        `json:"platform"`
+	EOSID         string        `json:"eos_id"`
+	Level         int           `json:"level"`
+	Team          int           `json:"team"` // 0=Allies, 1=Axis
+	Role          int           `json:"role"`
+	Platoon       string        `json:"platoon"`
+	Loadout       string        `json:"loadout"`
+	Kills         int           `json:"kills"`
+	Deaths        int           `json:"deaths"`
+	ScoreData     ScoreData     `json:"score_data"`
+	WorldPosition WorldPosition `json:"world_position"`
+}
+
+// ScoreData holds a player's per-category score
+type ScoreData struct {
+	Combat  int `json:"combat"`
+	Offense int `json:"offense"`
+	Defense int `json:"defense"`
+	Support int `json:"support"`
+}
+
+// WorldPosition is a player's location on the map
+type WorldPosition struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// SessionInfo describes the current match as reported by GetServerInformation session
+type SessionInfo struct {
+	ServerName         string `json:"server_name"`
+	MapName            string `json:"map_name"`
+	MapID              string `json:"map_id"`
+	GameMode           string `json:"game_mode"`
+	RemainingMatchTime int    `json:"remaining_match_time"` // Seconds
+	MatchTime          int    `json:"match_time"`           // Seconds
+	AlliedFaction      int    `json:"allied_faction"`
+	AxisFaction        int    `json:"axis_faction"`
+	AlliedScore        int    `json:"allied_score"`
+	AxisScore          int    `json:"axis_score"`
+	MaxPlayerCount     int    `json:"max_player_count"`
+	PlayerCount        int    `json:"player_count"`
+	AlliedPlayerCount  int    `json:"allied_player_count"`
+	AxisPlayerCount    int    `json:"axis_player_count"`
+	MaxQueueCount      int    `json:"max_queue_count"`
+	QueueCount         int    `json:"queue_count"`
+	MaxVIPQueueCount   int    `json:"max_vip_queue_count"`
+	VIPQueueCount      int    `json:"vip_queue_count"`
+}
+
+// LogEntry is a single GetAdminLog line
+type LogEntry struct {
+	Timestamp string `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// CommandInfo is an entry of GetDisplayableCommands
+type CommandInfo struct {
+	ID                string `json:"id"`
+	FriendlyName      string `json:"friendly_name"`
+	IsClientSupported bool   `json:"is_client_supported"`
+}
+
+// CommandReference describes a command's parameters as returned by GetClientReferenceData
+type CommandReference struct {
+	Name               string              `json:"name"`
+	Text               string              `json:"text"`
+	Description        string              `json:"description"`
+	DialogueParameters []DialogueParameter `json:"dialogue_parameters"`
+}
+
+// DialogueParameter describes one parameter of a command
+type DialogueParameter struct {
+	Type          string `json:"type"`
+	Name          string `json:"name"`
+	ID            string `json:"id"`
+	DisplayMember string `json:"display_member"`
+	ValueMember   string `json:"value_member"`
+}
+
+// AdminUser is an entry of GetAdminUsers
+type AdminUser struct {
+	UserID  string `json:"user_id"`
+	Group   string `json:"group"`
+	Comment string `json:"comment"`
+}
+
+// Ban is an entry of GetTemporaryBans or GetPermanentBans
+type Ban struct {
+	UserID        string `json:"user_id"`
+	UserName      string `json:"user_name"`
+	TimeOfBanning string `json:"time_of_banning"`
+	DurationHours int    `json:"duration_hours"`
+	BanReason     string `json:"ban_reason"`
+	AdminName     string `json:"admin_name"`
+}
@@ -0,0 +1,294 @@
+package schedule
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/internal/fsutil"
+)
+
+var ErrNotFound = errors.New("scheduled broadcast not found")
+
+// Broadcast actions
+const (
+	ActionBroadcast      = "broadcast"       // ServerBroadcast
+	ActionWelcomeMessage = "welcome_message" // SetWelcomeMessage
+)
+
+const (
+	// maxRuns bounds the run history kept per broadcast
+	maxRuns = 50
+
+	minInterval = 10 * time.Second
+)
+
+// Broadcast is a message sent to a saved server on a cron or interval schedule
+type Broadcast struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Profile         string     `json:"profile"`
+	Action          string     `json:"action"`
+	Message         string     `json:"message"`                    // Template, see TemplateVars
+	Cron            string     `json:"cron,omitempty"`             // Five-field cron expression
+	Timezone        string     `json:"timezone,omitempty"`         // IANA name the cron expression is matched in; empty uses the scheduler default
+	IntervalSeconds int        `json:"interval_seconds,omitempty"` // Used when Cron is empty
+	Enabled         bool       `json:"enabled"`
+	Author          string     `json:"author,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	LastRun         *time.Time `json:"last_run,omitempty"`
+	NextRun         *time.Time `json:"next_run,omitempty"`
+}
+
+// Run is one execution of a broadcast
+type Run struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message,omitempty"` // As sent, with placeholders filled
+	Error   string    `json:"error,omitempty"`
+}
+
+// Validate checks the action, message, schedule and timezone of a broadcast
+func (b Broadcast) Validate() error {
+	if b.Action != ActionBroadcast && b.Action != ActionWelcomeMessage {
+		return fmt.Errorf("action must be %q or %q", ActionBroadcast, ActionWelcomeMessage)
+	}
+	if b.Message == "" {
+		return errors.New("message is required")
+	}
+	sched, err := b.Schedule()
+	if err != nil {
+		return err
+	}
+	loc, err := b.Location(time.UTC)
+	if err != nil {
+		return err
+	}
+	if sched.Next(time.Now().In(loc)).IsZero() {
+		return errors.New("cron expression never matches")
+	}
+	return nil
+}
+
+// Schedule returns the parsed cron expression or interval
+func (b Broadcast) Schedule() (Schedule, error) {
+	switch {
+	case b.Cron != "" && b.IntervalSeconds != 0:
+		return nil, errors.New("set either cron or interval_seconds, not both")
+	case b.Cron != "":
+		return ParseCron(b.Cron)
+	case time.Duration(b.IntervalSeconds)*time.Second >= minInterval:
+		return Interval(time.Duration(b.IntervalSeconds) * time.Second), nil
+	case b.IntervalSeconds != 0:
+		return nil, fmt.Errorf("interval_seconds must be at least %d", int(minInterval.Seconds()))
+	default:
+		return nil, errors.New("cron or interval_seconds is required")
+	}
+}
+
+// Location returns the timezone the broadcast's cron expression is matched in, def if unset
+func (b Broadcast) Location(def *time.Location) (*time.Location, error) {
+	return loadLocation(b.Timezone, def)
+}
+
+// broadcastRecord is the on-disk form of a broadcast
+type broadcastRecord struct {
+	Broadcast
+	Runs []Run `json:"runs"` // Most recent last
+}
+
+// BroadcastStore persists scheduled broadcasts and their run history to a JSON file
+type BroadcastStore struct {
+	path string
+
+	mu      sync.RWMutex
+	records map[string]*broadcastRecord
+}
+
+// OpenBroadcasts loads the store at path, creating it on first write
+func OpenBroadcasts(path string) (*BroadcastStore, error) {
+	s := &BroadcastStore{
+		path:    path,
+		records: make(map[string]*broadcastRecord),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scheduled broadcasts: %w", err)
+	}
+
+	var records []*broadcastRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse scheduled broadcasts: %w", err)
+	}
+	for _, r := range records {
+		s.records[r.ID] = r
+	}
+	return s, nil
+}
+
+// List returns all broadcasts sorted by name
+func (s *BroadcastStore) List() []Broadcast {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	broadcasts := make([]Broadcast, 0, len(s.records))
+	for _, r := range s.records {
+		broadcasts = append(broadcasts, r.Broadcast)
+	}
+	sort.Slice(broadcasts, func(i, j int) bool {
+		if broadcasts[i].Name != broadcasts[j].Name {
+			return broadcasts[i].Name < broadcasts[j].Name
+		}
+		return broadcasts[i].ID < broadcasts[j].ID
+	})
+	return broadcasts
+}
+
+// Get returns a single broadcast
+func (s *BroadcastStore) Get(id string) (Broadcast, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return Broadcast{}, ErrNotFound
+	}
+	return r.Broadcast, nil
+}
+
+// Runs returns the run history of a broadcast, most recent first
+func (s *BroadcastStore) Runs(id string) ([]Run, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	runs := make([]Run, len(r.Runs))
+	for i, run := range r.Runs {
+		runs[len(runs)-1-i] = run
+	}
+	return runs, nil
+}
+
+// Create adds a broadcast with a new ID
+func (s *BroadcastStore) Create(b Broadcast) (Broadcast, error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return Broadcast{}, fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b.ID = hex.EncodeToString(id)
+	b.CreatedAt = time.Now().UTC()
+	b.UpdatedAt = b.CreatedAt
+	b.LastRun, b.NextRun = nil, nil
+	s.records[b.ID] = &broadcastRecord{Broadcast: b}
+
+	if err := s.saveLocked(); err != nil {
+		delete(s.records, b.ID)
+		return Broadcast{}, err
+	}
+	return b, nil
+}
+
+// Update replaces the definition of a broadcast, keeping its history
+func (s *BroadcastStore) Update(id string, b Broadcast) (Broadcast, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return Broadcast{}, ErrNotFound
+	}
+	old := r.Broadcast
+	b.ID = id
+	b.Author = old.Author
+	b.CreatedAt = old.CreatedAt
+	b.UpdatedAt = time.Now().UTC()
+	b.LastRun, b.NextRun = old.LastRun, nil
+	r.Broadcast = b
+
+	if err := s.saveLocked(); err != nil {
+		r.Broadcast = old
+		return Broadcast{}, err
+	}
+	return b, nil
+}
+
+// Delete removes a broadcast and its history
+func (s *BroadcastStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.records, id)
+
+	if err := s.saveLocked(); err != nil {
+		s.records[id] = r
+		return err
+	}
+	return nil
+}
+
+// setNextRun records when the runner plans to run a broadcast next, zero for never.
+// It is not saved.
+func (s *BroadcastStore) setNextRun(id string, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[id]
+	switch {
+	case !ok:
+	case next.IsZero():
+		r.NextRun = nil
+	default:
+		r.NextRun = &next
+	}
+}
+
+// recordRun appends a run to the history of a broadcast unless it was deleted meanwhile
+func (s *BroadcastStore) recordRun(id string, run Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return nil
+	}
+	r.LastRun = &run.Time
+	r.Runs = append(r.Runs, run)
+	if len(r.Runs) > maxRuns {
+		r.Runs = r.Runs[len(r.Runs)-maxRuns:]
+	}
+	return s.saveLocked()
+}
+
+func (s *BroadcastStore) saveLocked() error {
+	records := make([]*broadcastRecord, 0, len(s.records))
+	for _, r := range s.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scheduled broadcasts: %w", err)
+	}
+	return fsutil.WriteFileAtomic(s.path, data)
+}
@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// A Saturday
+	from := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2026, 10, 17, 12, 1, 0, 0, time.UTC)},
+		{"step", "*/15 * * * *", time.Date(2026, 10, 17, 12, 15, 0, 0, time.UTC)},
+		{"stepped range", "0 9-17/2 * * *", time.Date(2026, 10, 17, 13, 0, 0, 0, time.UTC)},
+		{"step from a value", "0 20/2 * * *", time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)},
+		{"list", "0 6,18 * * *", time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC)},
+		{"same minute is not next", "0 12 * * *", time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)},
+		{"weekday names", "30 8 * * MON-fri", time.Date(2026, 10, 19, 8, 30, 0, 0, time.UTC)},
+		{"sunday as 0", "0 0 * * 0", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"range ending on 7", "0 0 * * 6-7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"month names", "0 12 1 jan,JUL *", time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{"day of month", "0 0 13 * *", time.Date(2026, 11, 13, 0, 0, 0, 0, time.UTC)},
+		{"either day field", "0 0 13 * fri", time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)},
+		{"either day field, month day first", "0 0 20 * fri", time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+		{"stepped day of month needs both", "0 0 */10 * sun", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"stepped day of week needs both", "0 0 1 * */7", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"hourly", "@hourly", time.Date(2026, 10, 17, 13, 0, 0, 0, time.UTC)},
+		{"daily", "@daily", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"weekly", "@WEEKLY", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"monthly", "@monthly", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"yearly", "@yearly", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"annually", " @annually ", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 feb *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 30 feb *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+			if got := c.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"* * * * mon-",
+		"1,,2 * * * *",
+		"@reboot",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) accepted an invalid expression", expr)
+		}
+	}
+}
+
+func TestCronNextAcrossDSTGap(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("no timezone database:", err)
+	}
+	// Clocks go from 01:00 GMT to 02:00 BST on 29 March 2026
+	from := time.Date(2026, 3, 28, 12, 0, 0, 0, london)
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{"time in the gap is skipped that day", "30 1 * * *", time.Date(2026, 3, 30, 1, 30, 0, 0, london)},
+		{"first hour after the gap", "30 2 * * *", time.Date(2026, 3, 29, 1, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+
+	c, err := ParseCron("0 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 3, 29, 0, 0, 0, 0, london)
+	for _, want := range []time.Time{
+		time.Date(2026, 3, 29, 1, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 29, 2, 0, 0, 0, time.UTC),
+	} {
+		at = c.Next(at)
+		if !at.Equal(want) {
+			t.Fatalf("hourly across the gap = %v, want %v", at, want)
+		}
+		if h := at.In(london).Hour(); h == 1 {
+			t.Errorf("hourly run at %v, a wall time that does not exist", at.In(london))
+		}
+	}
+}
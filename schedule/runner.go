@@ -0,0 +1,408 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/audit"
+	"github.com/Sledro/hllrcon/profile"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/session"
+)
+
+const (
+	// tickInterval is how often due jobs are looked for
+	tickInterval = time.Second
+
+	runTimeout = 30 * time.Second
+)
+
+// Config holds the runner options shared by all jobs
+type Config struct {
+	Audit    *audit.Log     // Records the commands jobs send; nil disables
+	Location *time.Location // Timezone of broadcast and task cron expressions without their own
+}
+
+// job is a scheduled broadcast or task as the runner plans it
+type job struct {
+	key     string // Unique across stores
+	enabled bool
+	updated time.Time
+	lastRun *time.Time
+	sched   Schedule // nil when the definition is invalid
+	loc     *time.Location
+	catchUp bool // Run once on start if a run was missed while down
+	setNext func(time.Time)
+	run     func(ctx context.Context, catchUp bool)
+}
+
+// plan is when a job runs next, for the definition last updated at updated
+type plan struct {
+	next    time.Time
+	updated time.Time
+	catchUp bool
+}
+
+// Runner sends scheduled broadcasts and runs scheduled tasks through pooled
+// connections to their profiles. Runs missed while the service was down are skipped,
+// unless a task asks to run once on start.
+type Runner struct {
+	broadcasts *BroadcastStore // nil when disabled
+	tasks      *TaskStore      // nil when disabled
+	profiles   *profile.Store
+	pool       *session.Pool
+	cfg        Config
+
+	mu      sync.Mutex
+	plans   map[string]plan
+	running map[string]bool
+}
+
+func NewRunner(broadcasts *BroadcastStore, tasks *TaskStore, profiles *profile.Store, pool *session.Pool, cfg Config) *Runner {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	return &Runner{
+		broadcasts: broadcasts,
+		tasks:      tasks,
+		profiles:   profiles,
+		pool:       pool,
+		cfg:        cfg,
+		plans:      make(map[string]plan),
+		running:    make(map[string]bool),
+	}
+}
+
+// Run starts due jobs until ctx is done, then waits for those in progress
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now().UTC()
+		for _, d := range r.due(now) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					r.mu.Lock()
+					delete(r.running, d.key)
+					r.mu.Unlock()
+				}()
+				d.run(ctx, d.catchUp)
+			}()
+		}
+	}
+}
+
+// dueJob is a job to start now
+type dueJob struct {
+	job
+	catchUp bool
+}
+
+// due plans new and changed jobs and returns those whose time has come, marking them
+// running so a slow run is not started twice
+func (r *Runner) due(now time.Time) []dueJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []dueJob
+	seen := make(map[string]bool)
+	for _, j := range r.jobs() {
+		seen[j.key] = true
+		if !j.enabled || j.sched == nil {
+			delete(r.plans, j.key)
+			j.setNext(time.Time{})
+			continue
+		}
+
+		p, ok := r.plans[j.key]
+		if !ok || !p.updated.Equal(j.updated) {
+			p = firstRun(j, now, !ok)
+		} else if !now.Before(p.next) && !r.running[j.key] {
+			r.running[j.key] = true
+			due = append(due, dueJob{job: j, catchUp: p.catchUp})
+			p.next, p.catchUp = j.sched.Next(now.In(j.loc)).UTC(), false
+		}
+		if p.next.IsZero() {
+			delete(r.plans, j.key)
+		} else {
+			r.plans[j.key] = p
+		}
+		j.setNext(p.next)
+	}
+	for key := range r.plans {
+		if !seen[key] {
+			delete(r.plans, key)
+		}
+	}
+	return due
+}
+
+// jobs lists the broadcasts and tasks of both stores
+func (r *Runner) jobs() []job {
+	var jobs []job
+	if r.broadcasts != nil {
+		for _, b := range r.broadcasts.List() {
+			j := job{
+				key:     "broadcast:" + b.ID,
+				enabled: b.Enabled,
+				updated: b.UpdatedAt,
+				lastRun: b.LastRun,
+				setNext: func(t time.Time) { r.broadcasts.setNextRun(b.ID, t) },
+				run:     func(ctx context.Context, _ bool) { r.runBroadcast(ctx, b) },
+			}
+			if loc, err := b.Location(r.cfg.Location); err == nil {
+				j.loc = loc
+				j.sched, _ = b.Schedule()
+			}
+			jobs = append(jobs, j)
+		}
+	}
+	if r.tasks != nil {
+		for _, t := range r.tasks.List() {
+			j := job{
+				key:     "task:" + t.ID,
+				enabled: t.Enabled,
+				updated: t.UpdatedAt,
+				lastRun: t.LastRun,
+				catchUp: t.MissedRuns == MissedRunOnce,
+				setNext: func(next time.Time) { r.tasks.setNextRun(t.ID, next) },
+				run:     func(ctx context.Context, catchUp bool) { r.runTask(ctx, t, catchUp) },
+			}
+			if loc, err := t.Location(r.cfg.Location); err == nil {
+				j.loc = loc
+				if sched, err := ParseCron(t.Cron); err == nil {
+					j.sched = sched
+				}
+			}
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+// firstRun plans a job after now. Intervals stay aligned to the last run, or to the last
+// change if it never ran. On start, a job that runs once after downtime is due straight
+// away if a run came due since it last ran.
+func firstRun(j job, now time.Time, starting bool) plan {
+	p := plan{updated: j.updated}
+	anchor := j.updated
+	if j.lastRun != nil && j.lastRun.After(anchor) {
+		anchor = *j.lastRun
+	}
+
+	if interval, ok := j.sched.(Interval); ok {
+		p.next = anchor.Add(time.Duration(interval))
+		if !p.next.After(now) {
+			p.next = now.Add(time.Duration(interval) - now.Sub(anchor)%time.Duration(interval))
+		}
+		return p
+	}
+
+	if starting && j.catchUp {
+		if missed := j.sched.Next(anchor.In(j.loc)); !missed.IsZero() && !missed.After(now) {
+			p.next, p.catchUp = now, true
+			return p
+		}
+	}
+	p.next = j.sched.Next(now.In(j.loc)).UTC()
+	return p
+}
+
+// runBroadcast sends one broadcast and records the outcome
+func (r *Runner) runBroadcast(ctx context.Context, b Broadcast) {
+	ctx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	run := Run{Time: time.Now().UTC()}
+	message, err := r.sendBroadcast(ctx, b)
+	run.Message = message
+	if err != nil {
+		run.Error = err.Error()
+		slog.Warn("Scheduled broadcast failed", "id", b.ID, "name", b.Name, "profile", b.Profile, "error", err)
+	} else {
+		slog.Info("Scheduled broadcast sent", "id", b.ID, "name", b.Name, "profile", b.Profile, "action", b.Action)
+	}
+	if err := r.broadcasts.recordRun(b.ID, run); err != nil {
+		slog.Error("Failed to record scheduled broadcast run", "id", b.ID, "error", err)
+	}
+}
+
+// sendBroadcast fills the message template and sends it to the broadcast's server
+func (r *Runner) sendBroadcast(ctx context.Context, b Broadcast) (string, error) {
+	host, port, password, err := r.profiles.Credentials(b.Profile)
+	if err != nil {
+		return "", err
+	}
+	client, release, err := r.pool.Acquire(ctx, host, port, password)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	message, err := Render(ctx, client, b.Message)
+	if err != nil {
+		return "", err
+	}
+
+	var command string
+	switch b.Action {
+	case ActionBroadcast:
+		command = "ServerBroadcast"
+		err = client.ServerBroadcast(ctx, message)
+	case ActionWelcomeMessage:
+		command = "SetWelcomeMessage"
+		err = client.SetWelcomeMessage(ctx, message)
+	default:
+		return message, errors.New("unknown action " + b.Action)
+	}
+
+	entry := audit.Entry{
+		User:       "broadcast:" + b.ID,
+		Server:     fmt.Sprintf("%s:%d", host, port),
+		Profile:    b.Profile,
+		Command:    command,
+		Body:       audit.RedactBody(rcon.MessageRequest{Message: message}),
+		StatusCode: http.StatusOK,
+	}
+	var statusErr *rcon.StatusError
+	if errors.As(err, &statusErr) {
+		entry.StatusCode, entry.StatusMessage = statusErr.StatusCode, statusErr.StatusMessage
+	} else if err != nil {
+		entry.StatusCode, entry.Error = 0, err.Error()
+	}
+	r.record(entry)
+	return message, err
+}
+
+// runTask runs one task, or checks it for a dry run, and records the outcome
+func (r *Runner) runTask(ctx context.Context, t Task, catchUp bool) {
+	ctx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	run := TaskRun{Time: time.Now().UTC(), DryRun: t.DryRun, CatchUp: catchUp}
+	steps, err := r.execTask(ctx, t)
+	run.Steps = steps
+	if err == nil {
+		err = stepsError(steps)
+	}
+	if err != nil {
+		run.Error = err.Error()
+		slog.Warn("Scheduled task failed", "id", t.ID, "name", t.Name, "profile", t.Profile, "dry_run", t.DryRun, "error", err)
+	} else {
+		slog.Info("Scheduled task ran", "id", t.ID, "name", t.Name, "profile", t.Profile, "dry_run", t.DryRun, "catch_up", catchUp)
+	}
+	if err := r.tasks.recordRun(t.ID, run); err != nil {
+		slog.Error("Failed to record scheduled task run", "id", t.ID, "error", err)
+	}
+}
+
+// execTask runs the steps of a task in order on one connection, stopping at the first
+// failure. A dry run only checks each step against the server's reference data.
+func (r *Runner) execTask(ctx context.Context, t Task) ([]StepResult, error) {
+	host, port, password, err := r.profiles.Credentials(t.Profile)
+	if err != nil {
+		return nil, err
+	}
+	client, release, err := r.pool.Acquire(ctx, host, port, password)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	results := make([]StepResult, len(t.Steps))
+	if t.DryRun {
+		for i, step := range t.Steps {
+			results[i].Name = step.Name
+			ref, err := client.GetClientReferenceData(ctx, step.Name)
+			if err == nil {
+				_, err = ref.ParseContent(step.Body)
+			}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}
+		return results, nil
+	}
+
+	commands := make([]rcon.BatchCommand, len(t.Steps))
+	for i, step := range t.Steps {
+		content, err := step.content()
+		if err != nil {
+			return nil, err
+		}
+		commands[i] = rcon.BatchCommand{Command: step.Name, ContentBody: content}
+	}
+	batch, err := client.ExecuteBatch(ctx, commands, true)
+	if err != nil {
+		return nil, err
+	}
+
+	server := fmt.Sprintf("%s:%d", host, port)
+	for i, cmd := range commands {
+		res := &results[i]
+		res.Name = cmd.Command
+		switch {
+		case i >= len(batch):
+			res.Skipped = true
+			continue
+		case batch[i].Err != nil:
+			res.Error = batch[i].Err.Error()
+		default:
+			res.StatusCode = batch[i].Response.StatusCode
+			res.StatusMessage = batch[i].Response.StatusMessage
+		}
+		if !rcon.IsReadOnly(cmd.Command) {
+			r.record(audit.Entry{
+				User:          "task:" + t.ID,
+				Server:        server,
+				Profile:       t.Profile,
+				Command:       cmd.Command,
+				Body:          audit.RedactBody(cmd.ContentBody),
+				StatusCode:    res.StatusCode,
+				StatusMessage: res.StatusMessage,
+				Error:         res.Error,
+			})
+		}
+	}
+	return results, nil
+}
+
+// stepsError describes the first step that failed, if any
+func stepsError(steps []StepResult) error {
+	for i, s := range steps {
+		switch {
+		case s.Error != "":
+			return fmt.Errorf("step %d (%s): %s", i+1, s.Name, s.Error)
+		case s.StatusCode != 0 && s.StatusCode != http.StatusOK:
+			return fmt.Errorf("step %d (%s): status %d %s", i+1, s.Name, s.StatusCode, s.StatusMessage)
+		}
+	}
+	return nil
+}
+
+// record appends a command sent by a job to the audit log
+func (r *Runner) record(entry audit.Entry) {
+	if r.cfg.Audit == nil {
+		return
+	}
+	if _, err := r.cfg.Audit.Append(entry); err != nil {
+		slog.Error("Failed to write audit entry", "command", entry.Command, "error", err)
+	}
+}
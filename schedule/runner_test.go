@@ -0,0 +1,274 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/audit"
+	"github.com/Sledro/hllrcon/profile"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/rcon/rcontest"
+	"github.com/Sledro/hllrcon/session"
+)
+
+func TestBroadcastCronTimezone(t *testing.T) {
+	store, err := OpenBroadcasts(filepath.Join(t.TempDir(), "broadcasts.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("no timezone database:", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no timezone database:", err)
+	}
+
+	for _, b := range []Broadcast{
+		{Name: "own", Timezone: "America/New_York"},
+		{Name: "default"},
+	} {
+		b.Profile, b.Action, b.Message, b.Cron, b.Enabled = "main", ActionBroadcast, "hi", "0 18 * * *", true
+		if _, err := store.Create(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRunner(store, nil, nil, nil, Config{Location: london})
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	want := map[string]time.Time{
+		"own":     time.Date(2026, 10, 17, 18, 0, 0, 0, newYork).UTC(),
+		"default": time.Date(2026, 10, 17, 18, 0, 0, 0, london).UTC(),
+	}
+	jobs := r.jobs()
+	for i, b := range store.List() {
+		if got := firstRun(jobs[i], now, true).next; !got.Equal(want[b.Name]) {
+			t.Errorf("%s broadcast next run = %v, want %v", b.Name, got, want[b.Name])
+		}
+	}
+
+	if err := (Broadcast{Action: ActionBroadcast, Message: "hi", Cron: "0 18 * * *", Timezone: "Mars/Olympus"}).Validate(); err == nil {
+		t.Error("accepted an unknown timezone")
+	}
+}
+
+func TestFirstRunMissedRuns(t *testing.T) {
+	daily, err := ParseCron("0 4 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	updated := now.AddDate(0, -1, 0)
+	at := func(t time.Time) *time.Time { return &t }
+	missed := at(time.Date(2026, 10, 15, 4, 0, 0, 0, time.UTC))
+	current := at(time.Date(2026, 10, 17, 4, 0, 0, 0, time.UTC))
+	tomorrow := time.Date(2026, 10, 18, 4, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		catchUp     bool
+		lastRun     *time.Time
+		starting    bool
+		want        time.Time
+		wantCatchUp bool
+	}{
+		{"run once after downtime", true, missed, true, now, true},
+		{"run once, never ran since the change", true, nil, true, now, true},
+		{"run once, nothing missed", true, current, true, tomorrow, false},
+		{"run once, edited while running", true, missed, false, tomorrow, false},
+		{"skip after downtime", false, missed, true, tomorrow, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := job{updated: updated, lastRun: tt.lastRun, sched: daily, loc: time.UTC, catchUp: tt.catchUp}
+			p := firstRun(j, now, tt.starting)
+			if !p.next.Equal(tt.want) || p.catchUp != tt.wantCatchUp {
+				t.Errorf("firstRun = %v (catch up %v), want %v (catch up %v)", p.next, p.catchUp, tt.want, tt.wantCatchUp)
+			}
+		})
+	}
+
+	j := job{updated: updated, lastRun: at(now.Add(-25 * time.Minute)), sched: Interval(10 * time.Minute), loc: time.UTC, catchUp: true}
+	if p := firstRun(j, now, true); !p.next.Equal(now.Add(5*time.Minute)) || p.catchUp {
+		t.Errorf("interval firstRun = %v (catch up %v), want aligned to the last run", p.next, p.catchUp)
+	}
+}
+
+func TestDueMissedRuns(t *testing.T) {
+	tasks, err := OpenTasks(filepath.Join(t.TempDir(), "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, policy := range []string{MissedRunOnce, MissedSkip} {
+		task := Task{Name: policy, Profile: "main", Steps: []Step{{Name: "GetServerConfig"}}, Cron: "0 4 * * *", MissedRuns: policy, Enabled: true}
+		if _, err := tasks.Create(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRunner(nil, tasks, nil, nil, Config{})
+	later := time.Now().Add(48 * time.Hour)
+	if due := r.due(later); len(due) != 0 {
+		t.Fatalf("due while planning = %+v", due)
+	}
+	due := r.due(later)
+	if len(due) != 1 || !due[0].catchUp || !strings.HasSuffix(due[0].key, mustTaskID(t, tasks, MissedRunOnce)) {
+		t.Fatalf("due on start = %+v, want only the run_once task, as a catch-up", due)
+	}
+	r.mu.Lock()
+	clear(r.running)
+	r.mu.Unlock()
+	if again := r.due(later.Add(time.Minute)); len(again) != 0 {
+		t.Errorf("due after the catch-up = %+v, want nothing until the next scheduled run", again)
+	}
+}
+
+// mustTaskID returns the ID of the task named name
+func mustTaskID(t *testing.T, tasks *TaskStore, name string) string {
+	t.Helper()
+	for _, task := range tasks.List() {
+		if task.Name == name {
+			return task.ID
+		}
+	}
+	t.Fatalf("no task %q", name)
+	return ""
+}
+
+// newTaskRunner runs tasks against srv through the profile main, auditing to a fresh log
+func newTaskRunner(t *testing.T, srv *rcontest.Server) (*Runner, *TaskStore, *audit.Log) {
+	t.Helper()
+	dir := t.TempDir()
+	cipher, err := profile.NewCipher("test encryption key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := profile.Open(filepath.Join(dir, "profiles.json"), cipher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := profiles.Create(profile.Profile{Name: "main", Host: srv.Host(), Port: srv.Port()}, srv.Password); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := OpenTasks(filepath.Join(dir, "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := audit.Open(filepath.Join(dir, "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { log.Close() })
+
+	pool := session.NewPool(func(host string, port int, password string) *rcon.Client {
+		return rcon.NewClient(host, port, password, time.Second, 1<<20, 1<<20)
+	}, session.PoolConfig{MaxConnsPerServer: 1, IdleTimeout: time.Minute, HealthCheckInterval: time.Minute})
+	t.Cleanup(pool.Close)
+	return NewRunner(nil, tasks, profiles, pool, Config{Audit: log}), tasks, log
+}
+
+// newTaskServer answers reference data for the steps of testSteps
+func newTaskServer(t *testing.T) *rcontest.Server {
+	t.Helper()
+	srv := rcontest.NewServer("secret")
+	t.Cleanup(srv.Close)
+	refs := map[string]rcon.CommandReference{
+		"GetServerConfig":       {Name: "GetServerConfig"},
+		"SetAutoBalanceEnabled": {Name: "SetAutoBalanceEnabled", DialogueParameters: []rcon.DialogueParameter{{ID: "Enable", Type: "bool"}}},
+		"ServerBroadcast":       {Name: "ServerBroadcast", DialogueParameters: []rcon.DialogueParameter{{ID: "Message", Type: "text"}}},
+	}
+	srv.Handle("GetClientReferenceData", func(body string) rcon.Response {
+		resp := rcon.Response{StatusCode: 200, StatusMessage: "OK", Version: rcon.Version, Name: "GetClientReferenceData"}
+		ref, ok := refs[body]
+		if !ok {
+			resp.StatusCode, resp.StatusMessage = 400, "Unknown command"
+			return resp
+		}
+		data, _ := json.Marshal(ref)
+		resp.ContentBody = string(data)
+		return resp
+	})
+	srv.SetResponse("GetServerConfig", map[string]any{"server_name": "Test"})
+	srv.SetResponse("SetAutoBalanceEnabled", "")
+	srv.SetStatus("ServerBroadcast", 500, "Broadcast failed", "")
+	return srv
+}
+
+var testSteps = []Step{
+	{Name: "GetServerConfig"},
+	{Name: "SetAutoBalanceEnabled", Body: json.RawMessage(`{"Enable": true}`)},
+	{Name: "ServerBroadcast", Body: json.RawMessage(`{"Message": "restarting"}`)},
+	{Name: "SetAutoBalanceEnabled", Body: json.RawMessage(`{"Enable": false}`)},
+}
+
+func TestRunTaskAuditsEachStep(t *testing.T) {
+	srv := newTaskServer(t)
+	r, tasks, log := newTaskRunner(t, srv)
+	task, err := tasks.Create(Task{Name: "restart", Profile: "main", Steps: testSteps, Cron: "0 4 * * *", MissedRuns: MissedRunOnce, Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.runTask(context.Background(), task, true)
+	runs, err := tasks.Runs(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || !runs[0].CatchUp || !strings.Contains(runs[0].Error, "step 3 (ServerBroadcast)") {
+		t.Fatalf("runs = %+v, want one catch-up run failing at step 3", runs)
+	}
+	if steps := runs[0].Steps; steps[1].StatusCode != 200 || steps[2].StatusCode != 500 || !steps[3].Skipped {
+		t.Errorf("steps = %+v, want the last one skipped after the failure", steps)
+	}
+
+	entries, err := log.Query(audit.Query{User: "task:" + task.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		if e.Profile != "main" || e.Server != srv.Addr() {
+			t.Errorf("entry %+v not attributed to the profile's server", e)
+		}
+		got = append(got, e.Command+" "+string(e.Body))
+	}
+	want := []string{`SetAutoBalanceEnabled {"Enable":true}`, `ServerBroadcast {"Message":"restarting"}`}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("audit entries = %q, want one per state-changing step that ran: %q", got, want)
+	}
+}
+
+func TestRunTaskDryRun(t *testing.T) {
+	srv := newTaskServer(t)
+	r, tasks, log := newTaskRunner(t, srv)
+	steps := append([]Step{}, testSteps...)
+	steps[3].Body = json.RawMessage(`{"Enable": "maybe"}`)
+	task, err := tasks.Create(Task{Name: "check", Profile: "main", Steps: steps, Cron: "0 4 * * *", MissedRuns: MissedSkip, DryRun: true, Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.runTask(context.Background(), task, false)
+	runs, err := tasks.Runs(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || !runs[0].DryRun || !strings.Contains(runs[0].Error, "step 4") {
+		t.Fatalf("runs = %+v, want one dry run reporting the invalid body of step 4", runs)
+	}
+	for _, req := range srv.Requests() {
+		switch req.Name {
+		case "ServerConnect", "Login", "GetClientReferenceData":
+		default:
+			t.Errorf("dry run sent %s", req.Name)
+		}
+	}
+	if entries, err := log.Query(audit.Query{}); err != nil || len(entries) != 0 {
+		t.Errorf("dry run audited %d entries (%v)", len(entries), err)
+	}
+}
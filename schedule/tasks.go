@@ -0,0 +1,318 @@
+package schedule
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/internal/fsutil"
+)
+
+var ErrTaskNotFound = errors.New("scheduled task not found")
+
+// Missed run policies, applied when the service starts after a run came due while it was down
+const (
+	MissedSkip    = "skip"     // Wait for the next scheduled time
+	MissedRunOnce = "run_once" // Run once straight away, however many runs were missed
+)
+
+// Task runs a list of RCON commands against a saved server on a cron schedule
+type Task struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Profile    string     `json:"profile"`
+	Steps      []Step     `json:"steps"`
+	Cron       string     `json:"cron"`
+	Timezone   string     `json:"timezone,omitempty"` // IANA name; empty uses the scheduler default
+	MissedRuns string     `json:"missed_runs"`
+	DryRun     bool       `json:"dry_run"` // Validate the steps against the server without running them
+	Enabled    bool       `json:"enabled"`
+	Author     string     `json:"author,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	LastRun    *time.Time `json:"last_run,omitempty"`
+	NextRun    *time.Time `json:"next_run,omitempty"`
+}
+
+// Step is one command of a task, with its content body as validated when the task was saved
+type Step struct {
+	Name string          `json:"name"`
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// content decodes the step's body into the content body to send
+func (s Step) content() (any, error) {
+	if len(s.Body) == 0 {
+		return "", nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(s.Body))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid body for %s: %w", s.Name, err)
+	}
+	return value, nil
+}
+
+// TaskRun is one execution of a task
+type TaskRun struct {
+	Time    time.Time    `json:"time"`
+	DryRun  bool         `json:"dry_run,omitempty"`
+	CatchUp bool         `json:"catch_up,omitempty"` // Made up for a run missed while the service was down
+	Steps   []StepResult `json:"steps,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// StepResult is the outcome of one step; steps after a failure are skipped
+type StepResult struct {
+	Name          string `json:"name"`
+	StatusCode    int    `json:"status_code,omitempty"`
+	StatusMessage string `json:"status_message,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Skipped       bool   `json:"skipped,omitempty"`
+}
+
+// Validate checks the steps, schedule, timezone and missed run policy of a task
+func (t Task) Validate() error {
+	if len(t.Steps) == 0 {
+		return errors.New("at least one step is required")
+	}
+	if t.MissedRuns != MissedSkip && t.MissedRuns != MissedRunOnce {
+		return fmt.Errorf("missed_runs must be %q or %q", MissedSkip, MissedRunOnce)
+	}
+	if t.Cron == "" {
+		return errors.New("cron is required")
+	}
+	sched, err := ParseCron(t.Cron)
+	if err != nil {
+		return err
+	}
+	loc, err := t.Location(time.UTC)
+	if err != nil {
+		return err
+	}
+	if sched.Next(time.Now().In(loc)).IsZero() {
+		return errors.New("cron expression never matches")
+	}
+	return nil
+}
+
+// Location returns the timezone the task's cron expression is matched in, def if unset
+func (t Task) Location(def *time.Location) (*time.Location, error) {
+	return loadLocation(t.Timezone, def)
+}
+
+// loadLocation loads an IANA timezone by name, returning def for an empty name
+func loadLocation(name string, def *time.Location) (*time.Location, error) {
+	if name == "" {
+		return def, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
+
+// taskRecord is the on-disk form of a task
+type taskRecord struct {
+	Task
+	Runs []TaskRun `json:"runs"` // Most recent last
+}
+
+// TaskStore persists scheduled tasks and their run history to a JSON file
+type TaskStore struct {
+	path string
+
+	mu      sync.RWMutex
+	records map[string]*taskRecord
+}
+
+// OpenTasks loads the store at path, creating it on first write
+func OpenTasks(path string) (*TaskStore, error) {
+	s := &TaskStore{
+		path:    path,
+		records: make(map[string]*taskRecord),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scheduled tasks: %w", err)
+	}
+
+	var records []*taskRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse scheduled tasks: %w", err)
+	}
+	for _, r := range records {
+		s.records[r.ID] = r
+	}
+	return s, nil
+}
+
+// List returns all tasks sorted by name
+func (s *TaskStore) List() []Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := make([]Task, 0, len(s.records))
+	for _, r := range s.records {
+		tasks = append(tasks, r.Task)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Name != tasks[j].Name {
+			return tasks[i].Name < tasks[j].Name
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks
+}
+
+// Get returns a single task
+func (s *TaskStore) Get(id string) (Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return Task{}, ErrTaskNotFound
+	}
+	return r.Task, nil
+}
+
+// Runs returns the run history of a task, most recent first
+func (s *TaskStore) Runs(id string) ([]TaskRun, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	runs := make([]TaskRun, len(r.Runs))
+	for i, run := range r.Runs {
+		runs[len(runs)-1-i] = run
+	}
+	return runs, nil
+}
+
+// Create adds a task with a new ID
+func (s *TaskStore) Create(t Task) (Task, error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return Task{}, fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.ID = hex.EncodeToString(id)
+	t.CreatedAt = time.Now().UTC()
+	t.UpdatedAt = t.CreatedAt
+	t.LastRun, t.NextRun = nil, nil
+	s.records[t.ID] = &taskRecord{Task: t}
+
+	if err := s.saveLocked(); err != nil {
+		delete(s.records, t.ID)
+		return Task{}, err
+	}
+	return t, nil
+}
+
+// Update replaces the definition of a task, keeping its history
+func (s *TaskStore) Update(id string, t Task) (Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return Task{}, ErrTaskNotFound
+	}
+	old := r.Task
+	t.ID = id
+	t.Author = old.Author
+	t.CreatedAt = old.CreatedAt
+	t.UpdatedAt = time.Now().UTC()
+	t.LastRun, t.NextRun = old.LastRun, nil
+	r.Task = t
+
+	if err := s.saveLocked(); err != nil {
+		r.Task = old
+		return Task{}, err
+	}
+	return t, nil
+}
+
+// Delete removes a task and its history
+func (s *TaskStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return ErrTaskNotFound
+	}
+	delete(s.records, id)
+
+	if err := s.saveLocked(); err != nil {
+		s.records[id] = r
+		return err
+	}
+	return nil
+}
+
+// setNextRun records when the runner plans to run a task next, zero for never.
+// It is not saved.
+func (s *TaskStore) setNextRun(id string, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[id]
+	switch {
+	case !ok:
+	case next.IsZero():
+		r.NextRun = nil
+	default:
+		r.NextRun = &next
+	}
+}
+
+// recordRun appends a run to the history of a task unless it was deleted meanwhile
+func (s *TaskStore) recordRun(id string, run TaskRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok {
+		return nil
+	}
+	r.LastRun = &run.Time
+	r.Runs = append(r.Runs, run)
+	if len(r.Runs) > maxRuns {
+		r.Runs = r.Runs[len(r.Runs)-maxRuns:]
+	}
+	return s.saveLocked()
+}
+
+func (s *TaskStore) saveLocked() error {
+	records := make([]*taskRecord, 0, len(s.records))
+	for _, r := range s.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scheduled tasks: %w", err)
+	}
+	return fsutil.WriteFileAtomic(s.path, data)
+}
@@ -0,0 +1,72 @@
+package schedule
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+var templateVarRe = regexp.MustCompile(`\{(\w+)\}`)
+
+// TemplateVars are the placeholders filled in messages; others are left as written
+var TemplateVars = map[string]string{
+	"map":         "Current map",
+	"next_map":    "Next map in the sequence",
+	"players":     "Players online",
+	"max_players": "Player slots",
+	"server_name": "Server name",
+}
+
+// Render fills the placeholders of a message template from the server, fetching
+// only the information the template uses
+func Render(ctx context.Context, client *rcon.Client, tmpl string) (string, error) {
+	used := make(map[string]bool)
+	for _, m := range templateVarRe.FindAllStringSubmatch(tmpl, -1) {
+		if _, ok := TemplateVars[m[1]]; ok {
+			used[m[1]] = true
+		}
+	}
+	if len(used) == 0 {
+		return tmpl, nil
+	}
+
+	info, err := client.GetServerSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	values := map[string]string{
+		"map":         info.MapName,
+		"players":     strconv.Itoa(info.PlayerCount),
+		"max_players": strconv.Itoa(info.MaxPlayerCount),
+		"server_name": info.ServerName,
+	}
+	if used["next_map"] {
+		sequence, err := client.GetMapSequence(ctx)
+		if err != nil {
+			return "", err
+		}
+		values["next_map"] = nextMap(sequence)
+	}
+
+	return templateVarRe.ReplaceAllStringFunc(tmpl, func(match string) string {
+		if v, ok := values[match[1:len(match)-1]]; ok {
+			return v
+		}
+		return match
+	}), nil
+}
+
+// nextMap returns the name of the entry after the server's position in the sequence,
+// wrapping around. A map can appear more than once, so its ID does not say where the
+// sequence is.
+func nextMap(sequence *rcon.MapSequence) string {
+	if len(sequence.Maps) == 0 {
+		return ""
+	}
+	if sequence.CurrentIndex < 0 || sequence.CurrentIndex >= len(sequence.Maps) {
+		return sequence.Maps[0].Name
+	}
+	return sequence.Maps[(sequence.CurrentIndex+1)%len(sequence.Maps)].Name
+}
@@ -0,0 +1,34 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+func TestNextMap(t *testing.T) {
+	maps := []rcon.MapEntry{
+		{Name: "CARENTAN", ID: "carentan_warfare"},
+		{Name: "FOY", ID: "foy_warfare"},
+		{Name: "CARENTAN", ID: "carentan_warfare"},
+		{Name: "KURSK", ID: "kursk_warfare"},
+	}
+	tests := []struct {
+		name     string
+		sequence rcon.MapSequence
+		want     string
+	}{
+		{"first", rcon.MapSequence{Maps: maps, CurrentIndex: 0}, "FOY"},
+		{"repeated map", rcon.MapSequence{Maps: maps, CurrentIndex: 2}, "KURSK"},
+		{"wraps", rcon.MapSequence{Maps: maps, CurrentIndex: 3}, "CARENTAN"},
+		{"out of range", rcon.MapSequence{Maps: maps, CurrentIndex: 9}, "CARENTAN"},
+		{"empty", rcon.MapSequence{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextMap(&tt.sequence); got != tt.want {
+				t.Errorf("nextMap = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
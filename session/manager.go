@@ -1,6 +1,7 @@
 package session
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"sync"
@@ -11,23 +12,27 @@ import (
 
 type Session struct {
 	ID        string
-	Client    *rcon.Client
+	Client    *rcon.Client // Shared with other sessions on the same server
 	Host      string
 	Port      int
+	Profile   string // Saved server profile the session was opened from, if any
 	CreatedAt time.Time
 	LastUsed  time.Time
+	release   func()
 }
 
 type Manager struct {
 	sessions map[string]*Session
 	mu       sync.RWMutex
 	timeout  time.Duration
+	pool     *Pool
 }
 
-func NewManager(timeout time.Duration) *Manager {
+func NewManager(timeout time.Duration, pool *Pool) *Manager {
 	m := &Manager{
 		sessions: make(map[string]*Session),
 		timeout:  timeout,
+		pool:     pool,
 	}
 
 	// Start cleanup goroutine
@@ -36,10 +41,19 @@ func NewManager(timeout time.Duration) *Manager {
 	return m
 }
 
-func (m *Manager) Create(host string, port int, password string, dialTimeout, maxRequestSize, maxResponseSize int) (*Session, error) {
-	client := rcon.NewClient(host, port, password, time.Duration(dialTimeout)*time.Second, maxRequestSize, maxResponseSize)
+// Create registers a new session backed by a pooled connection to the server
+func (m *Manager) Create(ctx context.Context, host string, port int, password string) (*Session, error) {
+	return m.create(ctx, "", host, port, password)
+}
+
+// CreateForProfile is like Create but records the saved profile the credentials came from
+func (m *Manager) CreateForProfile(ctx context.Context, profile, host string, port int, password string) (*Session, error) {
+	return m.create(ctx, profile, host, port, password)
+}
 
-	if err := client.Connect(); err != nil {
+func (m *Manager) create(ctx context.Context, profile, host string, port int, password string) (*Session, error) {
+	client, release, err := m.pool.Acquire(ctx, host, port, password)
+	if err != nil {
 		return nil, err
 	}
 
@@ -49,8 +63,10 @@ func (m *Manager) Create(host string, port int, password string, dialTimeout, ma
 		Client:    client,
 		Host:      host,
 		Port:      port,
+		Profile:   profile,
 		CreatedAt: time.Now(),
 		LastUsed:  time.Now(),
+		release:   release,
 	}
 
 	m.mu.Lock()
@@ -76,11 +92,23 @@ func (m *Manager) Remove(sessionID string) {
 	defer m.mu.Unlock()
 
 	if session, exists := m.sessions[sessionID]; exists {
-		session.Client.Close()
+		session.release()
 		delete(m.sessions, sessionID)
 	}
 }
 
+// Count returns the number of active sessions
+func (m *Manager) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.sessions)
+}
+
+// Pool returns the connection pool backing the sessions
+func (m *Manager) Pool() *Pool {
+	return m.pool
+}
+
 func (m *Manager) cleanupLoop() {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
@@ -97,7 +125,7 @@ func (m *Manager) cleanup() {
 	now := time.Now()
 	for id, session := range m.sessions {
 		if now.Sub(session.LastUsed) > m.timeout {
-			session.Client.Close()
+			session.release()
 			delete(m.sessions, id)
 		}
 	}
@@ -0,0 +1,382 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+// ClientFactory builds an unconnected RCON client for a server
+type ClientFactory func(host string, port int, password string) *rcon.Client
+
+// PoolConfig bounds and maintains pooled connections
+type PoolConfig struct {
+	MaxConnsPerServer   int           // Authenticated connections per server and credential
+	IdleTimeout         time.Duration // Unreferenced connections are closed after this long
+	HealthCheckInterval time.Duration // How often idle connections are probed
+}
+
+// Pool shares authenticated RCON connections between sessions. Connections are keyed
+// by server address and a hash of the password, so a session can only join a
+// connection that was opened with the same credentials.
+type Pool struct {
+	newClient ClientFactory
+	cfg       PoolConfig
+
+	mu      sync.Mutex
+	servers map[string]*serverPool
+
+	closeOnce sync.Once
+	done      chan struct{} // Closed by Close to stop the health checks
+}
+
+type serverPool struct {
+	host    string
+	port    int
+	conns   []*pooledConn
+	pending int           // Connections being established, counted against the limit
+	dialed  chan struct{} // Closed and replaced whenever a pending connection finishes
+}
+
+type pooledConn struct {
+	client   *rcon.Client
+	refs     int
+	lastUsed time.Time
+}
+
+// ServerStats reports pool usage for one server
+type ServerStats struct {
+	Host        string `json:"host"`
+	Port        int    `json:"port"`
+	Connections int    `json:"connections"`
+	InUse       int    `json:"in_use"`
+	Idle        int    `json:"idle"`
+	Sessions    int    `json:"sessions"`
+}
+
+// PoolStats reports pool usage across all servers
+type PoolStats struct {
+	Servers     int `json:"servers"`
+	Connections int `json:"connections"`
+	InUse       int `json:"in_use"`
+	Idle        int `json:"idle"`
+	Sessions    int `json:"sessions"`
+}
+
+func NewPool(newClient ClientFactory, cfg PoolConfig) *Pool {
+	if cfg.MaxConnsPerServer <= 0 {
+		cfg.MaxConnsPerServer = 1
+	}
+
+	p := &Pool{
+		newClient: newClient,
+		cfg:       cfg,
+		servers:   make(map[string]*serverPool),
+		done:      make(chan struct{}),
+	}
+
+	// Start health check goroutine
+	if cfg.HealthCheckInterval > 0 {
+		go p.healthLoop()
+	}
+
+	return p
+}
+
+// Acquire returns a shared authenticated client for the server, opening a new
+// connection if every existing one is busy and the limit allows. While the limit is
+// taken up by connections still being opened, Acquire waits for one of them rather
+// than dialing another. The returned release func must be called once the caller no
+// longer needs the client.
+func (p *Pool) Acquire(ctx context.Context, host string, port int, password string) (*rcon.Client, func(), error) {
+	key := poolKey(host, port, password)
+
+	p.mu.Lock()
+	var sp *serverPool
+	for {
+		var ok bool
+		sp, ok = p.servers[key]
+		if !ok {
+			sp = &serverPool{host: host, port: port, dialed: make(chan struct{})}
+			p.servers[key] = sp
+		}
+
+		least := sp.leastUsed()
+		full := len(sp.conns)+sp.pending >= p.cfg.MaxConnsPerServer
+		if least != nil && (least.refs == 0 || full) {
+			least.refs++
+			p.mu.Unlock()
+			return least.client, p.releaseFunc(least), nil
+		}
+		if !full {
+			break
+		}
+
+		// No connection is open yet and the limit is taken by dials in progress
+		dialed := sp.dialed
+		p.mu.Unlock()
+		select {
+		case <-dialed:
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("waiting for connection: %w", ctx.Err())
+		}
+		p.mu.Lock()
+	}
+	sp.pending++
+	p.mu.Unlock()
+
+	client := p.newClient(host, port, password)
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	client.OnStateChange(func(state rcon.State, err error) {
+		if err != nil {
+			slog.Warn("RCON connection state changed", "server", addr, "state", state, "error", err)
+			return
+		}
+		slog.Info("RCON connection state changed", "server", addr, "state", state)
+	})
+	err := client.ConnectContext(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sp.pending--
+	close(sp.dialed)
+	sp.dialed = make(chan struct{})
+	if err != nil {
+		if len(sp.conns) == 0 && sp.pending == 0 {
+			delete(p.servers, key)
+		}
+		return nil, nil, err
+	}
+
+	pc := &pooledConn{client: client, refs: 1, lastUsed: time.Now()}
+	sp.conns = append(sp.conns, pc)
+	slog.Debug("Opened pooled RCON connection", "server", addr, "connections", len(sp.conns))
+	return client, p.releaseFunc(pc), nil
+}
+
+// Retain adds a reference to a pooled client so it stays open while in use outside a
+// session. It returns the matching release, or false if the client is not pooled.
+func (p *Pool) Retain(client *rcon.Client) (func(), bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, sp := range p.servers {
+		for _, pc := range sp.conns {
+			if pc.client == client {
+				pc.refs++
+				return p.releaseFunc(pc), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// BorrowedClient is a pooled client lent out by Borrow
+type BorrowedClient struct {
+	Host    string
+	Port    int
+	Client  *rcon.Client
+	Release func()
+}
+
+// Borrow retains one in-use client per server for background work such as sampling.
+// Servers without sessions are skipped so idle connections can still expire.
+func (p *Pool) Borrow() []BorrowedClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var borrowed []BorrowedClient
+	for _, sp := range p.servers {
+		addr := net.JoinHostPort(sp.host, strconv.Itoa(sp.port))
+		if seen[addr] {
+			continue
+		}
+		for _, pc := range sp.conns {
+			if pc.refs == 0 {
+				continue
+			}
+			pc.refs++
+			seen[addr] = true
+			borrowed = append(borrowed, BorrowedClient{Host: sp.host, Port: sp.port, Client: pc.client, Release: p.releaseFunc(pc)})
+			break
+		}
+	}
+	return borrowed
+}
+
+// releaseFunc returns an idempotent release for pc
+func (p *Pool) releaseFunc(pc *pooledConn) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			pc.refs--
+			pc.lastUsed = time.Now()
+		})
+	}
+}
+
+// leastUsed returns the connection with the fewest references
+func (sp *serverPool) leastUsed() *pooledConn {
+	var least *pooledConn
+	for _, pc := range sp.conns {
+		if least == nil || pc.refs < least.refs {
+			least = pc
+		}
+	}
+	return least
+}
+
+// Stats returns usage across all servers
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := PoolStats{Servers: len(p.servers)}
+	for _, sp := range p.servers {
+		s := sp.stats()
+		stats.Connections += s.Connections
+		stats.InUse += s.InUse
+		stats.Idle += s.Idle
+		stats.Sessions += s.Sessions
+	}
+	return stats
+}
+
+// ServerStats returns usage for every connection to host:port, across credentials
+func (p *Pool) ServerStats(host string, port int) ServerStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := ServerStats{Host: host, Port: port}
+	for _, sp := range p.servers {
+		if sp.host != host || sp.port != port {
+			continue
+		}
+		s := sp.stats()
+		stats.Connections += s.Connections
+		stats.InUse += s.InUse
+		stats.Idle += s.Idle
+		stats.Sessions += s.Sessions
+	}
+	return stats
+}
+
+func (sp *serverPool) stats() ServerStats {
+	s := ServerStats{Host: sp.host, Port: sp.port, Connections: len(sp.conns)}
+	for _, pc := range sp.conns {
+		s.Sessions += pc.refs
+		if pc.refs > 0 {
+			s.InUse++
+		} else {
+			s.Idle++
+		}
+	}
+	return s
+}
+
+// Close stops the health checks and closes every pooled connection
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() { close(p.done) })
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, sp := range p.servers {
+		for _, pc := range sp.conns {
+			pc.client.Close()
+		}
+		delete(p.servers, key)
+	}
+}
+
+func (p *Pool) healthLoop() {
+	ticker := time.NewTicker(p.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.healthCheck()
+		}
+	}
+}
+
+// healthCheck closes expired idle connections and probes the remaining idle ones
+func (p *Pool) healthCheck() {
+	now := time.Now()
+	var probe []*pooledConn
+
+	p.mu.Lock()
+	for key, sp := range p.servers {
+		kept := sp.conns[:0]
+		for _, pc := range sp.conns {
+			switch {
+			case pc.refs > 0:
+				kept = append(kept, pc)
+			case now.Sub(pc.lastUsed) > p.cfg.IdleTimeout:
+				slog.Debug("Closing idle RCON connection", "server", net.JoinHostPort(sp.host, strconv.Itoa(sp.port)))
+				pc.client.Close()
+			default:
+				kept = append(kept, pc)
+				probe = append(probe, pc)
+			}
+		}
+		sp.conns = kept
+		if len(sp.conns) == 0 && sp.pending == 0 {
+			delete(p.servers, key)
+		}
+	}
+	p.mu.Unlock()
+
+	// Probe outside the pool lock; the client reconnects on its own if it can
+	for _, pc := range probe {
+		ctx, cancel := context.WithTimeout(context.Background(), p.cfg.HealthCheckInterval/2)
+		if _, err := pc.client.ExecuteContext(ctx, "GetServerChangelist", ""); err != nil {
+			slog.Warn("Pooled RCON connection failed health check", "error", err)
+			p.evict(pc)
+		}
+		cancel()
+	}
+}
+
+// evict removes an unreferenced connection from the pool and closes it
+func (p *Pool) evict(target *pooledConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if target.refs > 0 {
+		return
+	}
+	for key, sp := range p.servers {
+		for i, pc := range sp.conns {
+			if pc != target {
+				continue
+			}
+			sp.conns = append(sp.conns[:i], sp.conns[i+1:]...)
+			if len(sp.conns) == 0 && sp.pending == 0 {
+				delete(p.servers, key)
+			}
+			pc.client.Close()
+			return
+		}
+	}
+}
+
+// poolKey identifies a server and credential without retaining the password
+func poolKey(host string, port int, password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return fmt.Sprintf("%s#%s", net.JoinHostPort(host, strconv.Itoa(port)), hex.EncodeToString(sum[:8]))
+}
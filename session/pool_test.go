@@ -0,0 +1,97 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/rcon/rcontest"
+)
+
+func newTestPool(t *testing.T, maxConns int) *Pool {
+	t.Helper()
+	pool := NewPool(func(host string, port int, password string) *rcon.Client {
+		return rcon.NewClient(host, port, password, time.Second, 1<<20, 1<<20)
+	}, PoolConfig{MaxConnsPerServer: maxConns, IdleTimeout: time.Minute, HealthCheckInterval: time.Minute})
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// acquireConcurrently acquires n clients at once and returns them with their errors
+func acquireConcurrently(pool *Pool, srv *rcontest.Server, password string, n int) ([]*rcon.Client, []error) {
+	clients := make([]*rcon.Client, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			clients[i], _, errs[i] = pool.Acquire(ctx, srv.Host(), srv.Port(), password)
+		}()
+	}
+	wg.Wait()
+	return clients, errs
+}
+
+func TestAcquireHonoursLimitOnFirstUse(t *testing.T) {
+	for _, maxConns := range []int{1, 2} {
+		srv := rcontest.NewServer("secret")
+		defer srv.Close()
+		srv.SetLatency("Login", 50*time.Millisecond)
+		pool := newTestPool(t, maxConns)
+
+		clients, errs := acquireConcurrently(pool, srv, "secret", 10)
+		distinct := make(map[*rcon.Client]bool)
+		for i, err := range errs {
+			if err != nil {
+				t.Fatalf("max %d: acquire %d: %v", maxConns, i, err)
+			}
+			distinct[clients[i]] = true
+		}
+		if n := srv.Logins(); n > maxConns {
+			t.Errorf("max %d: %d logins", maxConns, n)
+		}
+		if len(distinct) > maxConns {
+			t.Errorf("max %d: %d distinct clients", maxConns, len(distinct))
+		}
+		if stats := pool.Stats(); stats.Connections > maxConns || stats.Sessions != 10 {
+			t.Errorf("max %d: stats = %+v", maxConns, stats)
+		}
+	}
+}
+
+func TestAcquireWaitersSeeFailedDial(t *testing.T) {
+	srv := rcontest.NewServer("secret")
+	defer srv.Close()
+	pool := newTestPool(t, 1)
+
+	_, errs := acquireConcurrently(pool, srv, "wrong", 5)
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("acquire %d succeeded with a wrong password", i)
+		}
+	}
+	if stats := pool.Stats(); stats.Servers != 0 {
+		t.Errorf("failed server left in the pool: %+v", stats)
+	}
+}
+
+func TestAcquireWaitHonoursContext(t *testing.T) {
+	srv := rcontest.NewServer("secret")
+	defer srv.Close()
+	srv.SetLatency("Login", 500*time.Millisecond)
+	pool := newTestPool(t, 1)
+
+	go pool.Acquire(context.Background(), srv.Host(), srv.Port(), "secret")
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := pool.Acquire(ctx, srv.Host(), srv.Port(), "secret"); err == nil {
+		t.Fatal("acquire waiting on a pending dial ignored its context")
+	}
+}
@@ -0,0 +1,256 @@
+package watchlist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/logparse"
+	"github.com/Sledro/hllrcon/logstream"
+	"github.com/Sledro/hllrcon/observe"
+	"github.com/Sledro/hllrcon/rcon"
+	"github.com/Sledro/hllrcon/session"
+)
+
+const (
+	// maxEventAge skips connection lines replayed from the log backlog, so a restart
+	// neither alerts for players who joined minutes ago nor forgets ones just listed
+	maxEventAge = 2 * time.Minute
+
+	// joinGrace is how long a connect from the log counts as present before the player
+	// list shows the player, which lags behind the log
+	joinGrace = time.Minute
+
+	notifyTimeout = 10 * time.Second
+)
+
+// Config selects how alerts are delivered besides the SSE stream
+type Config struct {
+	WebhookURL    string // Receives each alert as a JSON POST (empty disables)
+	MessageAdmins bool   // Message admins from GetAdminUsers who are online on the server
+}
+
+// Monitor watches every server with an open session for watched players: player list
+// samples and admin log connects from the shared observation feed are checked against
+// the watchlist. A player is alerted once per stay on a server.
+type Monitor struct {
+	store  *Store
+	pool   *session.Pool
+	cfg    Config
+	client *http.Client
+
+	mu      sync.Mutex
+	present map[string]map[string]time.Time // Server -> watched player online -> connect time from the log, zero once listed
+}
+
+func NewMonitor(store *Store, pool *session.Pool, cfg Config) *Monitor {
+	return &Monitor{
+		store:   store,
+		pool:    pool,
+		cfg:     cfg,
+		client:  &http.Client{Timeout: notifyTimeout},
+		present: make(map[string]map[string]time.Time),
+	}
+}
+
+// Consumer returns the feed consumer that raises alerts
+func (m *Monitor) Consumer() observe.Consumer {
+	return observe.Consumer{Players: true, Round: m.observeRound, Log: m.watchLog}
+}
+
+// observeRound checks the player list of each server that answered; servers whose
+// sessions have all ended are forgotten
+func (m *Monitor) observeRound(round observe.Round) {
+	active := make(map[string]bool, len(round.Servers))
+	for _, s := range round.Servers {
+		active[s.Addr] = true
+		if s.PlayersErr == nil {
+			m.observe(s.Addr, s.Client, s.Players)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for server := range m.present {
+		if !active[server] {
+			delete(m.present, server)
+		}
+	}
+}
+
+// observe alerts for watched players listed on server who were not known to be there
+func (m *Monitor) observe(server string, client *rcon.Client, players []rcon.Player) {
+	var alerts []Alert
+	m.mu.Lock()
+	seen := m.present[server]
+	now := make(map[string]time.Time)
+	for _, p := range players {
+		entry, err := m.store.Get(p.ID)
+		if err != nil {
+			continue
+		}
+		now[p.ID] = time.Time{}
+		if _, ok := seen[p.ID]; !ok {
+			alerts = append(alerts, newAlert(entry, p.Name, server, "player_list"))
+		}
+	}
+	for id, connected := range seen {
+		if _, ok := now[id]; !ok && !connected.IsZero() && time.Since(connected) < joinGrace {
+			now[id] = connected
+		}
+	}
+	m.present[server] = now
+	m.mu.Unlock()
+
+	for _, alert := range alerts {
+		m.raise(client, alert)
+	}
+}
+
+// watchLog tracks connects and disconnects of watched players from a batch of the
+// admin log of server
+func (m *Monitor) watchLog(server string, client *rcon.Client, batch []logstream.Entry) error {
+	for _, e := range batch {
+		conn, ok := logparse.ParseEntry(rcon.LogEntry{Timestamp: e.Timestamp, Message: e.Message}).(logparse.Connection)
+		if !ok || conn.Player.ID == "" || eventAge(e, conn) > maxEventAge {
+			continue
+		}
+		entry, err := m.store.Get(conn.Player.ID)
+		if err != nil {
+			continue
+		}
+
+		m.mu.Lock()
+		seen := m.present[server]
+		if seen == nil {
+			seen = make(map[string]time.Time)
+			m.present[server] = seen
+		}
+		_, online := seen[conn.Player.ID]
+		alert := false
+		switch {
+		case conn.Type == logparse.TypeDisconnected:
+			delete(seen, conn.Player.ID)
+		case !online:
+			seen[conn.Player.ID] = time.Now()
+			alert = true
+		}
+		m.mu.Unlock()
+
+		if alert {
+			m.raise(client, newAlert(entry, conn.Player.Name, server, "admin_log"))
+		}
+	}
+	return nil
+}
+
+// eventAge is how long ago a log line was written, from its Unix prefix or else the
+// entry timestamp
+func eventAge(e logstream.Entry, conn logparse.Connection) time.Duration {
+	if conn.UnixTime > 0 {
+		return time.Since(time.Unix(conn.UnixTime, 0))
+	}
+	if t, err := time.Parse(time.RFC3339Nano, e.Timestamp); err == nil {
+		return time.Since(t)
+	}
+	return 0
+}
+
+func newAlert(entry Entry, name, server, source string) Alert {
+	if name == "" {
+		name = entry.Name
+	}
+	return Alert{
+		PlayerID:   entry.PlayerID,
+		PlayerName: name,
+		Server:     server,
+		Source:     source,
+		Note:       entry.Note,
+		Author:     entry.Author,
+		Time:       time.Now().UTC(),
+	}
+}
+
+// raise publishes an alert to stream subscribers and delivers it to the webhook and
+// online admins in the background
+func (m *Monitor) raise(client *rcon.Client, alert Alert) {
+	slog.Info("Watched player joined", "player_id", alert.PlayerID, "name", alert.PlayerName, "server", alert.Server, "source", alert.Source)
+	m.store.publish(alert)
+
+	if m.cfg.WebhookURL != "" {
+		go m.postWebhook(alert)
+	}
+	if m.cfg.MessageAdmins {
+		go m.messageAdmins(client, alert)
+	}
+}
+
+// alertText is the human readable form of an alert
+func alertText(alert Alert) string {
+	text := fmt.Sprintf("Watchlist: %s (%s) joined %s", alert.PlayerName, alert.PlayerID, alert.Server)
+	if alert.Note != "" {
+		text += "\nNote: " + alert.Note
+	}
+	return text
+}
+
+// postWebhook sends the alert as JSON; content makes the payload a valid Discord message
+func (m *Monitor) postWebhook(alert Alert) {
+	body, err := json.Marshal(struct {
+		Content string `json:"content"`
+		Alert   Alert  `json:"alert"`
+	}{alertText(alert), alert})
+	if err != nil {
+		return
+	}
+	resp, err := m.client.Post(m.cfg.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		slog.Warn("Watchlist webhook failed", "error", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		slog.Warn("Watchlist webhook rejected alert", "status", resp.StatusCode)
+	}
+}
+
+// messageAdmins messages every admin of the server who is currently online
+func (m *Monitor) messageAdmins(client *rcon.Client, alert Alert) {
+	release, ok := m.pool.Retain(client)
+	if !ok {
+		return
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	admins, err := client.GetAdminUsers(ctx)
+	if err != nil {
+		slog.Warn("Watchlist could not list admins", "server", alert.Server, "error", err)
+		return
+	}
+	players, err := client.GetPlayers(ctx)
+	if err != nil {
+		slog.Warn("Watchlist could not list players", "server", alert.Server, "error", err)
+		return
+	}
+	online := make(map[string]bool, len(players))
+	for _, p := range players {
+		online[p.ID] = true
+	}
+
+	text := alertText(alert)
+	for _, admin := range admins {
+		if !online[admin.UserID] {
+			continue
+		}
+		if err := client.MessagePlayer(ctx, admin.UserID, text); err != nil {
+			slog.Warn("Watchlist could not message admin", "server", alert.Server, "admin_id", admin.UserID, "error", err)
+		}
+	}
+}
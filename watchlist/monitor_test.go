@@ -0,0 +1,157 @@
+package watchlist
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Sledro/hllrcon/logstream"
+	"github.com/Sledro/hllrcon/observe"
+	"github.com/Sledro/hllrcon/rcon"
+)
+
+const testServer = "10.0.0.1:7779"
+
+var (
+	able  = rcon.Player{Name: "Able", ID: "76561198000000001"}
+	baker = rcon.Player{Name: "Baker", ID: "76561198000000002"}
+)
+
+// newTestMonitor watches able only; alerts are only published, so no pool or client
+// is needed
+func newTestMonitor(t *testing.T) (*Monitor, <-chan Alert) {
+	t.Helper()
+	store := openTestStore(t, filepath.Join(t.TempDir(), "watchlist.json"))
+	if _, err := store.Create(Entry{PlayerID: able.ID, Note: "team kills"}); err != nil {
+		t.Fatal(err)
+	}
+	alerts, cancel := store.Subscribe()
+	t.Cleanup(cancel)
+	return NewMonitor(store, nil, Config{}), alerts
+}
+
+// logLine is a connection line of the admin log written at
+func logLine(kind string, p rcon.Player, at time.Time) logstream.Entry {
+	return logstream.Entry{
+		Timestamp: at.UTC().Format(time.RFC3339Nano),
+		Message:   fmt.Sprintf("[0:00 min (%d)] %s %s (%s)", at.Unix(), kind, p.Name, p.ID),
+	}
+}
+
+// sources drains the published alerts and returns their sources
+func sources(alerts <-chan Alert) []string {
+	var got []string
+	for {
+		select {
+		case a := <-alerts:
+			got = append(got, a.Source)
+		default:
+			return got
+		}
+	}
+}
+
+func watchLog(t *testing.T, m *Monitor, entries ...logstream.Entry) {
+	t.Helper()
+	if err := m.watchLog(testServer, nil, entries); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestObserveAlertsOncePerStay(t *testing.T) {
+	m, alerts := newTestMonitor(t)
+
+	m.observe(testServer, nil, []rcon.Player{able, baker})
+	if got := sources(alerts); !slices.Equal(got, []string{"player_list"}) {
+		t.Fatalf("first sample raised %v, want one player_list alert", got)
+	}
+	m.observe(testServer, nil, []rcon.Player{able, baker})
+	if got := sources(alerts); len(got) != 0 {
+		t.Errorf("second sample raised %v while the player stayed", got)
+	}
+
+	m.observe(testServer, nil, []rcon.Player{baker})
+	m.observe(testServer, nil, []rcon.Player{able})
+	if got := sources(alerts); !slices.Equal(got, []string{"player_list"}) {
+		t.Errorf("rejoin raised %v, want one player_list alert", got)
+	}
+}
+
+func TestWatchLogAlertsOncePerStay(t *testing.T) {
+	m, alerts := newTestMonitor(t)
+	now := time.Now()
+
+	watchLog(t, m, logLine("CONNECTED", baker, now), logLine("CONNECTED", able, now), logLine("CONNECTED", able, now))
+	if got := sources(alerts); !slices.Equal(got, []string{"admin_log"}) {
+		t.Fatalf("connect raised %v, want one admin_log alert", got)
+	}
+	m.observe(testServer, nil, []rcon.Player{able})
+	watchLog(t, m, logLine("CONNECTED", able, now))
+	if got := sources(alerts); len(got) != 0 {
+		t.Errorf("listing and logging the same stay raised %v", got)
+	}
+
+	watchLog(t, m, logLine("DISCONNECTED", able, now), logLine("CONNECTED", able, now))
+	if got := sources(alerts); !slices.Equal(got, []string{"admin_log"}) {
+		t.Errorf("reconnect raised %v, want one admin_log alert", got)
+	}
+}
+
+func TestWatchLogSkipsOldLines(t *testing.T) {
+	m, alerts := newTestMonitor(t)
+
+	watchLog(t, m, logLine("CONNECTED", able, time.Now().Add(-maxEventAge-time.Minute)))
+	if got := sources(alerts); len(got) != 0 {
+		t.Errorf("replayed connect raised %v", got)
+	}
+	m.observe(testServer, nil, []rcon.Player{able})
+	watchLog(t, m, logLine("DISCONNECTED", able, time.Now().Add(-maxEventAge-time.Minute)))
+	m.observe(testServer, nil, []rcon.Player{able})
+	if got := sources(alerts); !slices.Equal(got, []string{"player_list"}) {
+		t.Errorf("got %v, want one player_list alert and the replayed disconnect ignored", got)
+	}
+}
+
+func TestJoinGrace(t *testing.T) {
+	m, alerts := newTestMonitor(t)
+
+	watchLog(t, m, logLine("CONNECTED", able, time.Now()))
+	m.observe(testServer, nil, nil)
+	m.observe(testServer, nil, []rcon.Player{able})
+	if got := sources(alerts); !slices.Equal(got, []string{"admin_log"}) {
+		t.Fatalf("got %v, want the log connect to count until the player list catches up", got)
+	}
+
+	m.observe(testServer, nil, nil)
+	watchLog(t, m, logLine("CONNECTED", able, time.Now()))
+	m.mu.Lock()
+	m.present[testServer][able.ID] = time.Now().Add(-joinGrace - time.Second)
+	m.mu.Unlock()
+	m.observe(testServer, nil, nil)
+	m.observe(testServer, nil, []rcon.Player{able})
+	if got := sources(alerts); !slices.Equal(got, []string{"admin_log", "player_list"}) {
+		t.Errorf("got %v, want a connect never listed within the grace to be forgotten", got)
+	}
+}
+
+func TestObserveRoundForgetsInactiveServers(t *testing.T) {
+	m, alerts := newTestMonitor(t)
+	const other = "10.0.0.2:7779"
+
+	m.observeRound(observe.Round{Servers: []observe.Server{
+		{Addr: testServer, Players: []rcon.Player{able}},
+		{Addr: other, Players: []rcon.Player{able}},
+	}})
+	m.observeRound(observe.Round{Servers: []observe.Server{
+		{Addr: testServer, Players: []rcon.Player{able}},
+	}})
+	m.observeRound(observe.Round{Servers: []observe.Server{
+		{Addr: testServer, Players: []rcon.Player{able}},
+		{Addr: other, Players: []rcon.Player{able}},
+	}})
+	if got := sources(alerts); len(got) != 3 {
+		t.Errorf("got %v, want one alert per server and one after the session to %s returned", got, other)
+	}
+}
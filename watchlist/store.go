@@ -0,0 +1,211 @@
+// Package watchlist keeps players flagged by moderators and raises an alert the
+// moment one of them joins a connected server.
+package watchlist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sledro/hllrcon/internal/fsutil"
+)
+
+var (
+	ErrNotFound = errors.New("player is not on the watchlist")
+	ErrExists   = errors.New("player is already on the watchlist")
+)
+
+// alertBuffer is how many alerts a slow subscriber may fall behind before alerts are dropped
+const alertBuffer = 32
+
+// Entry is a watched player
+type Entry struct {
+	PlayerID  string    `json:"player_id"`
+	Name      string    `json:"name,omitempty"` // Known name, for display
+	Note      string    `json:"note"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Alert reports a watched player joining a server
+type Alert struct {
+	PlayerID   string    `json:"player_id"`
+	PlayerName string    `json:"player_name"`
+	Server     string    `json:"server"`
+	Source     string    `json:"source"` // "player_list" or "admin_log"
+	Note       string    `json:"note"`
+	Author     string    `json:"author,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// Store persists the watchlist to a JSON file and fans alerts out to subscribers
+type Store struct {
+	path string
+
+	mu      sync.RWMutex
+	entries map[string]Entry
+
+	subsMu sync.Mutex
+	subs   map[chan Alert]struct{}
+}
+
+// Open loads the store at path, creating it on first write
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:    path,
+		entries: make(map[string]Entry),
+		subs:    make(map[chan Alert]struct{}),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watchlist: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse watchlist: %w", err)
+	}
+	for _, e := range entries {
+		s.entries[e.PlayerID] = e
+	}
+	return s, nil
+}
+
+// List returns all watched players, most recently added first
+func (s *Store) List() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAt.After(entries[j].CreatedAt) })
+	return entries
+}
+
+// Get returns the entry of a watched player
+func (s *Store) Get(playerID string) (Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries[playerID]
+	if !ok {
+		return Entry{}, ErrNotFound
+	}
+	return e, nil
+}
+
+// Create adds a player to the watchlist
+func (s *Store) Create(e Entry) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[e.PlayerID]; ok {
+		return Entry{}, ErrExists
+	}
+	e.CreatedAt = time.Now().UTC()
+	e.UpdatedAt = e.CreatedAt
+	s.entries[e.PlayerID] = e
+
+	if err := s.saveLocked(); err != nil {
+		delete(s.entries, e.PlayerID)
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+// Update replaces the note and, if given, the name of a watched player
+func (s *Store) Update(playerID, name, note string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.entries[playerID]
+	if !ok {
+		return Entry{}, ErrNotFound
+	}
+	e := old
+	e.Note = note
+	if name != "" {
+		e.Name = name
+	}
+	e.UpdatedAt = time.Now().UTC()
+	s.entries[playerID] = e
+
+	if err := s.saveLocked(); err != nil {
+		s.entries[playerID] = old
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+// Delete removes a player from the watchlist
+func (s *Store) Delete(playerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.entries[playerID]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.entries, playerID)
+
+	if err := s.saveLocked(); err != nil {
+		s.entries[playerID] = old
+		return err
+	}
+	return nil
+}
+
+func (s *Store) saveLocked() error {
+	entries := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].PlayerID < entries[j].PlayerID })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode watchlist: %w", err)
+	}
+	return fsutil.WriteFileAtomic(s.path, data)
+}
+
+// Subscribe returns a channel receiving every alert from now on and a function
+// that ends the subscription
+func (s *Store) Subscribe() (<-chan Alert, func()) {
+	ch := make(chan Alert, alertBuffer)
+	s.subsMu.Lock()
+	s.subs[ch] = struct{}{}
+	s.subsMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.subsMu.Lock()
+			delete(s.subs, ch)
+			s.subsMu.Unlock()
+		})
+	}
+}
+
+// publish delivers an alert to every subscriber, skipping those that are full
+func (s *Store) publish(alert Alert) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for ch := range s.subs {
+		select {
+		case ch <- alert:
+		default:
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package watchlist
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func openTestStore(t *testing.T, path string) *Store {
+	t.Helper()
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return s
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlist.json")
+	s := openTestStore(t, path)
+	for _, e := range []Entry{
+		{PlayerID: "1", Name: "Able", Note: "team kills", Author: "mod"},
+		{PlayerID: "2", Note: "alt of a banned player"},
+	} {
+		if _, err := s.Create(e); err != nil {
+			t.Fatalf("Create(%s): %v", e.PlayerID, err)
+		}
+	}
+	if _, err := s.Create(Entry{PlayerID: "1"}); !errors.Is(err, ErrExists) {
+		t.Errorf("second Create = %v, want ErrExists", err)
+	}
+	if _, err := s.Update("2", "Baker", "confirmed alt"); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := s.Update("1", "", "spawn camping"); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := s.Update("3", "", "x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update of an unknown player = %v, want ErrNotFound", err)
+	}
+
+	reopened := openTestStore(t, path)
+	able, err := reopened.Get("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if able.Name != "Able" || able.Note != "spawn camping" || able.Author != "mod" || able.UpdatedAt.Before(able.CreatedAt) {
+		t.Errorf("Able = %+v", able)
+	}
+	if baker, _ := reopened.Get("2"); baker.Name != "Baker" || baker.Note != "confirmed alt" {
+		t.Errorf("Baker = %+v", baker)
+	}
+	if list := reopened.List(); len(list) != 2 || list[0].PlayerID != "2" {
+		t.Errorf("List = %+v, want the latest addition first", list)
+	}
+
+	if err := reopened.Delete("1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := reopened.Delete("1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+	if _, err := openTestStore(t, path).Get("1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted player still listed after reopening: %v", err)
+	}
+}